@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	adapters_consumers "uala-tweets/internal/adapters/consumers"
+	adapters_metrics "uala-tweets/internal/adapters/metrics"
+	adapters_publishers "uala-tweets/internal/adapters/publishers"
+	adapters_redis "uala-tweets/internal/adapters/redis"
+	adapters_repositories "uala-tweets/internal/adapters/repositories"
+	adapters_topics "uala-tweets/internal/adapters/topics"
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/config"
+	"uala-tweets/internal/interfaces/handlers"
+	"uala-tweets/internal/interfaces/middleware"
+
+	"uala-tweets/internal/ports/health"
+	pubports "uala-tweets/internal/ports/publishers"
+	repoports "uala-tweets/internal/ports/repositories"
+
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+)
+
+// tracingFlushTimeout is how long the spans left get to be sent on shutdown
+const tracingFlushTimeout = 5 * time.Second
+
+// app is what every process role is composed from: the connections to
+// PostgreSQL, Redis and Kafka, and the repositories, publishers and services
+// built on them. Kafka writers and readers only connect once used, so a role
+// holds no more connections than it needs.
+type app struct {
+	cfg         *config.Config
+	db          *sql.DB
+	redisClient *redis.Client
+	closers     []func() error
+	metrics     *adapters_metrics.PrometheusMetrics
+	// consumerGroups are the groups of the readers opened, checked for
+	// readiness
+	consumerGroups []string
+
+	// Repositories and caches
+	userRepo          repoports.UserRepository
+	followRepo        repoports.FollowRepository
+	tweetRepo         repoports.TweetRepository
+	muteRepo          repoports.MuteRepository
+	followRequestRepo repoports.FollowRequestRepository
+	listRepo          repoports.ListRepository
+	deadLetterRepo    repoports.DeadLetterRepository
+	timelineCache     repoports.TimelineCache
+	listTimelineCache repoports.TimelineCache
+	processedEvents   repoports.ProcessedEventStore
+
+	// Publishers
+	fanoutPub pubports.TimelineFanoutPublisher
+
+	// Services
+	policy            *application.Policy
+	userService       *application.UserService
+	followService     *application.FollowService
+	tweetService      *application.TweetService
+	timelineService   *application.TimelineService
+	muteService       *application.MuteService
+	suggestionService *application.SuggestionService
+	listService       *application.ListService
+	accountService    *application.AccountService
+	moderationService *application.ModerationService
+	adminService      *application.AdminService
+}
+
+// newApp connects to PostgreSQL and Redis, provisions the Kafka topics and
+// builds the services. Close releases what it opened.
+func newApp(cfg *config.Config) *app {
+	a := &app{cfg: cfg, metrics: adapters_metrics.NewPrometheusMetrics()}
+
+	// --- Tracing ---
+	// Closed last, to flush the spans of everything closed before
+	shutdownTracing := mustSetupTracing(cfg.Tracing)
+	a.onClose(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		return shutdownTracing(ctx)
+	})
+
+	// --- Database and Repositories ---
+	a.db = mustSetupDatabase(cfg.Database)
+	a.onClose(a.db.Close)
+	a.metrics.RegisterDB(a.db, "uala_tweets")
+	a.userRepo, a.followRepo, a.tweetRepo, a.muteRepo, a.followRequestRepo, a.listRepo = initRepositories(a.db)
+	a.deadLetterRepo = adapters_repositories.NewPostgreSQLDeadLetterRepository(a.db)
+
+	// --- Kafka Topics ---
+	if cfg.Features.ProvisionTopics {
+		provisionTopics(cfg.Kafka)
+	}
+
+	// --- Publishers ---
+	eventCodec := initEventCodec(cfg.Kafka.EventEncoding)
+	tweetPub := adapters_publishers.NewKafkaTweetPublisher(a.kafkaWriter(TopicTweetsCreated), eventCodec)
+	a.fanoutPub = adapters_publishers.NewKafkaTimelineFanoutPublisher(a.kafkaWriter(TopicTimelineFanout), eventCodec)
+	followPub := adapters_publishers.NewKafkaFollowPublisher(a.kafkaWriter(TopicUserFollowEvents), eventCodec)
+	userEventPub := adapters_publishers.NewKafkaUserEventPublisher(a.kafkaWriter(TopicUserEvents), eventCodec)
+	// Replays name their topic on each message
+	replayer := adapters_publishers.NewKafkaDeadLetterReplayer(a.kafkaWriter(""))
+
+	// --- Redis and Caches ---
+	a.redisClient = redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	a.onClose(a.redisClient.Close)
+	a.metrics.RegisterRedis(a.redisClient)
+	if err := redisotel.InstrumentTracing(a.redisClient); err != nil {
+		fatal("Failed to trace Redis commands", "error", err)
+	}
+	a.timelineCache = adapters_redis.NewTimelineCacheRedis(a.redisClient)
+	a.listTimelineCache = adapters_redis.NewListTimelineCacheRedis(a.redisClient)
+	a.processedEvents = adapters_redis.NewProcessedEventStoreRedis(a.redisClient, cfg.Consumers.ProcessedEventTTL)
+	// Keep cached suggestions around for a few missed refreshes
+	suggestionCache := adapters_redis.NewSuggestionCacheRedis(a.redisClient, 3*cfg.Jobs.SuggestionRefreshInterval)
+
+	// --- Services ---
+	a.policy = application.NewPolicy()
+	a.userService, a.followService, a.tweetService = initServices(a.userRepo, a.followRepo, a.followRequestRepo, a.tweetRepo, a.listRepo, a.timelineCache, a.listTimelineCache, tweetPub, followPub, a.policy)
+	a.timelineService = application.NewTimelineService(a.timelineCache, a.tweetRepo, a.muteRepo, a.metrics)
+	a.muteService = application.NewMuteService(a.userRepo, a.muteRepo)
+	a.suggestionService = application.NewSuggestionService(a.userRepo, a.followRepo, a.muteRepo, suggestionCache)
+	a.listService = application.NewListService(a.listRepo, a.userRepo, a.followRepo, a.tweetRepo, a.listTimelineCache)
+	a.accountService = application.NewAccountService(a.userRepo, a.followRepo, a.tweetRepo, a.listRepo, a.timelineCache, a.listTimelineCache, userEventPub, cfg.Jobs.AccountDeletionGracePeriod)
+	a.moderationService = application.NewModerationService(adapters_repositories.NewPostgreSQLReportRepository(a.db), a.tweetRepo, a.userRepo, a.tweetService, a.policy)
+	deadLetterService := application.NewDeadLetterService(a.deadLetterRepo, replayer)
+	a.adminService = application.NewAdminService(a.userRepo, a.suggestionService, a.accountService, deadLetterService, a.policy)
+
+	return a
+}
+
+// onClose registers close to be called by Close
+func (a *app) onClose(close func() error) {
+	a.closers = append(a.closers, close)
+}
+
+// Close closes what the app opened, the latest first, so Kafka writers and
+// readers are closed before the connections they were used with
+func (a *app) Close() {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](); err != nil {
+			slog.Error("Failed to close connection", "error", err)
+		}
+	}
+}
+
+// kafkaWriter returns a writer to topic that is closed with the app
+func (a *app) kafkaWriter(topic string) *kafka.Writer {
+	writer := initKafkaWriter(a.cfg.Kafka, topic)
+	a.onClose(writer.Close)
+	return writer
+}
+
+// kafkaReader returns a reader of topics that is closed with the app
+func (a *app) kafkaReader(groupID, logPrefix string, topics ...string) *kafka.Reader {
+	reader := initKafkaReader(a.cfg.Kafka, groupID, logPrefix, topics...)
+	a.onClose(reader.Close)
+	a.consumerGroups = append(a.consumerGroups, groupID)
+	return reader
+}
+
+// failureHandler returns the failure handler of a consumer, publishing to
+// its retry and dead-letter topics
+func (a *app) failureHandler(retryTopic, deadLetterTopic string, policy adapters_consumers.RetryPolicy) *adapters_consumers.FailureHandler {
+	return adapters_consumers.NewFailureHandler(a.kafkaWriter(retryTopic), a.kafkaWriter(deadLetterTopic), policy)
+}
+
+// newHealthHandler answers the probes: liveness covers the background
+// tasks, readiness the dependencies and the consumer groups of the consumers
+// started so far
+func newHealthHandler(a *app, tasks *backgroundTasks) *handlers.HealthHandler {
+	kafkaClient := &kafka.Client{Addr: kafka.TCP(a.cfg.Kafka.Brokers...)}
+	liveness := []health.Check{tasks}
+	readiness := []health.Check{
+		adapters_repositories.NewPostgreSQLHealthCheck(a.db),
+		adapters_redis.NewRedisHealthCheck(a.redisClient),
+		adapters_topics.NewKafkaHealthCheck(kafkaClient, serviceTopics()),
+	}
+	if len(a.consumerGroups) > 0 {
+		readiness = append(readiness, adapters_consumers.NewKafkaConsumerGroupHealthCheck(kafkaClient, a.consumerGroups))
+	}
+	healthService := application.NewHealthService(liveness, readiness, a.cfg.Health.CheckTimeout, a.cfg.Health.CacheTTL)
+	return handlers.NewHealthHandler(healthService)
+}
+
+// newProbeServer returns the HTTP server of roles not serving the API, which
+// only answers the probes and serves the metrics
+func newProbeServer(a *app, healthHandler *handlers.HealthHandler) *http.Server {
+	r := newEngine(a.cfg.HTTP.TrustedProxies)
+	setupOperationalRoutes(r, healthHandler, a.metrics.Handler())
+	return &http.Server{Addr: ":" + strconv.Itoa(a.cfg.HTTP.Port), Handler: r}
+}
+
+// newAPIServer returns the HTTP server of the API
+func newAPIServer(a *app, healthHandler *handlers.HealthHandler) *http.Server {
+	// --- Handlers ---
+	followHandler, userHandler, tweetHandler, timelineHandler, muteHandler, suggestionHandler, listHandler, accountHandler := initHandlers(a.userService, a.followService, a.tweetService, a.timelineService, a.muteService, a.suggestionService, a.listService, a.accountService)
+	moderationHandler := handlers.NewModerationHandler(a.moderationService)
+	adminHandler := handlers.NewAdminHandler(a.adminService)
+
+	// --- Authentication ---
+	authService := initAuthService(a.cfg.Auth, a.db, a.userRepo)
+	apiKeyService := application.NewAPIKeyService(adapters_repositories.NewPostgreSQLAPIKeyRepository(a.db), a.userRepo)
+	authenticator := middleware.NewCredentialAuthenticator(authService, apiKeyService)
+	authHandler := handlers.NewAuthHandler(authService)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyService)
+
+	// --- Router ---
+	rateLimits := newRouteRateLimits(a.cfg.Limits, adapters_redis.NewRateLimiterRedis(a.redisClient))
+	idempotent := middleware.Idempotency(adapters_redis.NewIdempotencyStoreRedis(a.redisClient), a.cfg.Limits.IdempotencyKeyTTL)
+	r := setupRouter(a.cfg, healthHandler, a.metrics.Handler(), middleware.Metrics(a.metrics), middleware.Tracing(a.cfg.Tracing.ServiceName, operationalRoutes...), authenticator, rateLimits, idempotent, authHandler, apiKeyHandler, followHandler, userHandler, tweetHandler, timelineHandler, muteHandler, suggestionHandler, listHandler, accountHandler, moderationHandler, adminHandler)
+
+	return &http.Server{Addr: ":" + strconv.Itoa(a.cfg.HTTP.Port), Handler: r}
+}
+
+// workerConsumer is a consumer a worker can run, by the name -consumers
+// takes
+type workerConsumer struct {
+	name  string
+	start func(a *app, tasks *backgroundTasks, policy adapters_consumers.RetryPolicy)
+}
+
+var workerConsumers = []workerConsumer{
+	{"tweet", startTweetConsumers},
+	{"fanout", startFanoutConsumers},
+	{"follow", startFollowConsumers},
+	{"dead-letter", startDeadLetterConsumers},
+}
+
+// startTweetConsumers runs the tweet consumer on its topic and, with the
+// same handling, on its retry topic. So do the fanout and follow consumers.
+func startTweetConsumers(a *app, tasks *backgroundTasks, policy adapters_consumers.RetryPolicy) {
+	failures := a.failureHandler(TopicTweetsCreatedRetry, TopicTweetsCreatedDeadLetter, policy)
+	pool := initPoolConfig(a.cfg.Consumers, a.cfg.Consumers.Tweet)
+	for _, reader := range []*kafka.Reader{
+		a.kafkaReader(ConsumerGroupTweetConsumer, "TWEET-READER", TopicTweetsCreated),
+		a.kafkaReader(ConsumerGroupTweetRetryConsumer, "TWEET-RETRY-READER", TopicTweetsCreatedRetry),
+	} {
+		tasks.Go("tweet consumer", func(ctx context.Context) {
+			startTweetConsumer(ctx, reader, a.tweetRepo, a.fanoutPub, a.followRepo, a.listRepo, a.processedEvents, failures, pool, a.metrics, a.metrics)
+		})
+	}
+}
+
+func startFanoutConsumers(a *app, tasks *backgroundTasks, policy adapters_consumers.RetryPolicy) {
+	failures := a.failureHandler(TopicTimelineFanoutRetry, TopicTimelineFanoutDeadLetter, policy)
+	pool := initPoolConfig(a.cfg.Consumers, a.cfg.Consumers.Fanout)
+	for _, reader := range []*kafka.Reader{
+		a.kafkaReader(ConsumerGroupFanoutConsumer, "TIMELINE-READER", TopicTimelineFanout),
+		a.kafkaReader(ConsumerGroupFanoutRetryConsumer, "TIMELINE-RETRY-READER", TopicTimelineFanoutRetry),
+	} {
+		tasks.Go("fanout consumer", func(ctx context.Context) {
+			startFanoutConsumer(ctx, reader, a.timelineCache, a.listTimelineCache, a.followRepo, a.processedEvents, failures, pool, a.metrics)
+		})
+	}
+}
+
+func startFollowConsumers(a *app, tasks *backgroundTasks, policy adapters_consumers.RetryPolicy) {
+	failures := a.failureHandler(TopicUserFollowEventsRetry, TopicUserFollowEventsDeadLetter, policy)
+	pool := initPoolConfig(a.cfg.Consumers, a.cfg.Consumers.Follow)
+	for _, reader := range []*kafka.Reader{
+		a.kafkaReader(ConsumerGroupFollowConsumer, "FOLLOW-READER", TopicUserFollowEvents),
+		a.kafkaReader(ConsumerGroupFollowRetryConsumer, "FOLLOW-RETRY-READER", TopicUserFollowEventsRetry),
+	} {
+		tasks.Go("follow consumer", func(ctx context.Context) {
+			startFollowConsumer(ctx, reader, a.timelineCache, a.tweetRepo, a.processedEvents, failures, pool, a.metrics)
+		})
+	}
+}
+
+func startDeadLetterConsumers(a *app, tasks *backgroundTasks, policy adapters_consumers.RetryPolicy) {
+	reader := a.kafkaReader(ConsumerGroupDeadLetterConsumer, "DEAD-LETTER-READER", TopicTweetsCreatedDeadLetter, TopicTimelineFanoutDeadLetter, TopicUserFollowEventsDeadLetter)
+	tasks.Go("dead-letter consumer", func(ctx context.Context) {
+		startDeadLetterConsumer(ctx, reader, a.deadLetterRepo)
+	})
+}
+
+// startJobs runs the background jobs
+func startJobs(a *app, tasks *backgroundTasks) {
+	tasks.Go("suggestion refresh job", func(ctx context.Context) {
+		startSuggestionRefreshJob(ctx, a.suggestionService, a.cfg.Jobs.SuggestionRefreshInterval)
+	})
+	tasks.Go("account purge job", func(ctx context.Context) {
+		startAccountPurgeJob(ctx, a.accountService, a.cfg.Jobs.AccountPurgeInterval)
+	})
+}
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_mutes_user_keyword;
+DROP INDEX IF EXISTS idx_mutes_user_muted_user;
+DROP INDEX IF EXISTS idx_mutes_user_id;
+
+DROP TABLE IF EXISTS mutes;
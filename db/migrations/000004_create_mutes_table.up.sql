@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS mutes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    mute_type VARCHAR(16) NOT NULL,
+    muted_user_id INTEGER,
+    keyword VARCHAR(100),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_mutes_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_mutes_muted_user
+        FOREIGN KEY (muted_user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    -- A mute targets either a user or a keyword, never both
+    CONSTRAINT chk_mute_target
+        CHECK (
+            (mute_type = 'user' AND muted_user_id IS NOT NULL AND keyword IS NULL) OR
+            (mute_type = 'keyword' AND keyword IS NOT NULL AND muted_user_id IS NULL)
+        )
+);
+
+-- Create index on user_id for loading a user's mute list at timeline read time
+CREATE INDEX IF NOT EXISTS idx_mutes_user_id ON mutes(user_id);
+
+-- Prevent muting the same user or keyword twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mutes_user_muted_user ON mutes(user_id, muted_user_id) WHERE mute_type = 'user';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mutes_user_keyword ON mutes(user_id, LOWER(keyword)) WHERE mute_type = 'keyword';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_follow_requests_target_id;
+DROP TABLE IF EXISTS follow_requests;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_protected;
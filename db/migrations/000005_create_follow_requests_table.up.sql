@@ -0,0 +1,23 @@
+-- Protected accounts must approve followers
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_protected BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Create follow_requests table for pending follows of protected accounts
+CREATE TABLE IF NOT EXISTS follow_requests (
+    requester_id INTEGER NOT NULL,
+    target_id INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (requester_id, target_id),
+    CONSTRAINT fk_follow_requests_requester
+        FOREIGN KEY (requester_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_follow_requests_target
+        FOREIGN KEY (target_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT check_not_self_follow_request
+        CHECK (requester_id != target_id)
+);
+
+-- Create index for listing the pending requests of a user
+CREATE INDEX IF NOT EXISTS idx_follow_requests_target_id ON follow_requests (target_id);
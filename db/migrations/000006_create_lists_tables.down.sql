@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_list_members_user_id;
+DROP TABLE IF EXISTS list_members;
+
+DROP INDEX IF EXISTS idx_lists_owner_id;
+DROP TABLE IF EXISTS lists;
@@ -0,0 +1,36 @@
+-- Create lists table for curated groups of accounts owned by a user
+CREATE TABLE IF NOT EXISTS lists (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL,
+    name VARCHAR(25) NOT NULL,
+    description VARCHAR(100) NOT NULL DEFAULT '',
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_lists_owner
+        FOREIGN KEY (owner_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- Create index for listing the lists of a user
+CREATE INDEX IF NOT EXISTS idx_lists_owner_id ON lists (owner_id);
+
+-- Create list_members table
+CREATE TABLE IF NOT EXISTS list_members (
+    list_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (list_id, user_id),
+    CONSTRAINT fk_list_members_list
+        FOREIGN KEY (list_id)
+        REFERENCES lists(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_list_members_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- Create index for finding the lists a new tweet fans out to
+CREATE INDEX IF NOT EXISTS idx_list_members_user_id ON list_members (user_id);
@@ -0,0 +1,22 @@
+-- Profile fields shown alongside the username
+ALTER TABLE users ADD COLUMN IF NOT EXISTS display_name VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS bio VARCHAR(160) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS location VARCHAR(30) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS website VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS avatar_url VARCHAR(255) NOT NULL DEFAULT '';
+
+-- Usernames a user has renamed away from. They keep resolving to that user,
+-- so mentions written before a rename still point at the same account, and
+-- nobody else can claim them.
+CREATE TABLE IF NOT EXISTS username_history (
+    username VARCHAR(255) PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_username_history_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+);
+
+-- Create index for cleaning up the history of a user
+CREATE INDEX IF NOT EXISTS idx_username_history_user_id ON username_history (user_id);
@@ -0,0 +1,5 @@
+-- Deactivated accounts are hard deleted once their grace period has passed
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
+
+-- Create index for the purge job to find accounts past their grace period
+CREATE INDEX IF NOT EXISTS idx_users_deactivated_at ON users (deactivated_at) WHERE deactivated_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tweet_reports;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
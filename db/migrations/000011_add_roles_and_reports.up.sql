@@ -0,0 +1,30 @@
+-- Roles decide who may moderate content and run maintenance
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD CONSTRAINT chk_users_role CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Create tweet_reports table. tweet_id has no foreign key so reports of
+-- removed tweets are kept as a moderation record.
+CREATE TABLE IF NOT EXISTS tweet_reports (
+    id BIGSERIAL PRIMARY KEY,
+    tweet_id BIGINT NOT NULL,
+    reporter_id INTEGER NOT NULL,
+    reason VARCHAR(280) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolved_by INTEGER,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (tweet_id, reporter_id),
+    CONSTRAINT fk_tweet_reports_reporter
+        FOREIGN KEY (reporter_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_tweet_reports_resolved_by
+        FOREIGN KEY (resolved_by)
+        REFERENCES users(id)
+        ON DELETE SET NULL,
+    CONSTRAINT chk_tweet_reports_status
+        CHECK (status IN ('open', 'dismissed', 'actioned'))
+);
+
+-- Create index for the moderation queue
+CREATE INDEX IF NOT EXISTS idx_tweet_reports_open ON tweet_reports (created_at) WHERE status = 'open';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tweets_event_id;
+
+ALTER TABLE tweets DROP COLUMN IF EXISTS event_id;
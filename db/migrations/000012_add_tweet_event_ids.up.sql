@@ -0,0 +1,5 @@
+-- Identify the event that created each tweet so a redelivered event does
+-- not insert the tweet twice. Tweets created before have no event ID.
+ALTER TABLE tweets ADD COLUMN IF NOT EXISTS event_id VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tweets_event_id ON tweets(event_id);
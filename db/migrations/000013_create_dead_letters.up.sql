@@ -0,0 +1,37 @@
+-- Create dead_letters table. Messages are archived from the dead-letter
+-- topics so operators can inspect and replay them.
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    consumer VARCHAR(100) NOT NULL,
+    original_topic VARCHAR(255) NOT NULL,
+    original_partition INTEGER NOT NULL,
+    original_offset BIGINT NOT NULL,
+    message_key BYTEA,
+    payload BYTEA NOT NULL,
+    headers JSONB NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL,
+    attempts INTEGER NOT NULL,
+    failed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    dead_letter_topic VARCHAR(255) NOT NULL,
+    dead_letter_partition INTEGER NOT NULL,
+    dead_letter_offset BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (dead_letter_topic, dead_letter_partition, dead_letter_offset)
+);
+
+-- Create index for listing and replaying by time range
+CREATE INDEX IF NOT EXISTS idx_dead_letters_failed_at ON dead_letters (failed_at);
+
+-- Create dead_letter_replays table, the audit trail of replays
+CREATE TABLE IF NOT EXISTS dead_letter_replays (
+    id BIGSERIAL PRIMARY KEY,
+    dead_letter_id BIGINT NOT NULL,
+    replayed_by VARCHAR(255) NOT NULL,
+    replayed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_dead_letter_replays_dead_letter
+        FOREIGN KEY (dead_letter_id)
+        REFERENCES dead_letters(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letter_replays_dead_letter_id ON dead_letter_replays (dead_letter_id);
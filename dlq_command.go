@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	adapters_publishers "uala-tweets/internal/adapters/publishers"
+	adapters_repositories "uala-tweets/internal/adapters/repositories"
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/config"
+	"uala-tweets/internal/domain"
+)
+
+const deadLetterUsage = `Usage: uala-tweets dlq <command> [flags]
+
+Commands:
+  list    [-consumer name] [-topic name] [-from time] [-to time] [-limit n]
+          List dead letters, most recent failure first
+  show    <id>
+          Show a dead letter with its payload and replays
+  replay  [-by name] <id>...
+  replay  [-by name] -from time -to time [-consumer name] [-topic name]
+          Publish dead letters back onto their original topic
+
+Times are RFC 3339, like 2024-01-02T15:04:05Z.
+`
+
+// runDeadLetterCommand runs the dlq subcommand, which lets operators inspect
+// and replay dead letters straight from the database without the API. It
+// returns the exit code.
+func runDeadLetterCommand(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, deadLetterUsage)
+		return 2
+	}
+
+	db := mustSetupDatabase(cfg.Database)
+	defer db.Close()
+	// Replays name their topic on each message
+	replayWriter := initKafkaWriter(cfg.Kafka, "")
+	defer replayWriter.Close()
+
+	service := application.NewDeadLetterService(
+		adapters_repositories.NewPostgreSQLDeadLetterRepository(db),
+		adapters_publishers.NewKafkaDeadLetterReplayer(replayWriter),
+	)
+
+	ctx := context.Background()
+	var err error
+	switch args[0] {
+	case "list":
+		err = listDeadLetters(ctx, service, args[1:], os.Stdout)
+	case "show":
+		err = showDeadLetter(ctx, service, args[1:], os.Stdout)
+	case "replay":
+		err = replayDeadLetters(ctx, service, args[1:], os.Stdout)
+	default:
+		fmt.Fprint(os.Stderr, deadLetterUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dlq %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+func listDeadLetters(ctx context.Context, service *application.DeadLetterService, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("dlq list", flag.ContinueOnError)
+	filter, err := parseDeadLetterFilter(flags, args)
+	if err != nil {
+		return err
+	}
+
+	deadLetters, err := service.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCONSUMER\tTOPIC\tFAILED AT\tATTEMPTS\tREPLAYS\tERROR")
+	for _, deadLetter := range deadLetters {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%.80s\n",
+			deadLetter.ID,
+			deadLetter.Consumer,
+			deadLetter.Topic,
+			deadLetter.FailedAt.UTC().Format(time.RFC3339),
+			deadLetter.Attempts,
+			deadLetter.ReplayCount,
+			deadLetter.Error,
+		)
+	}
+	return w.Flush()
+}
+
+func showDeadLetter(ctx context.Context, service *application.DeadLetterService, args []string, out io.Writer) error {
+	if len(args) != 1 {
+		return errors.New("expected one dead letter id")
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid dead letter id %q", args[0])
+	}
+
+	deadLetter, replays, err := service.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%d\n", deadLetter.ID)
+	fmt.Fprintf(w, "Consumer:\t%s\n", deadLetter.Consumer)
+	fmt.Fprintf(w, "Topic:\t%s (partition %d, offset %d)\n", deadLetter.Topic, deadLetter.Partition, deadLetter.Offset)
+	fmt.Fprintf(w, "Key:\t%s\n", deadLetter.Key)
+	fmt.Fprintf(w, "Failed at:\t%s\n", deadLetter.FailedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "Attempts:\t%d\n", deadLetter.Attempts)
+	fmt.Fprintf(w, "Error:\t%s\n", deadLetter.Error)
+	for _, key := range slices.Sorted(maps.Keys(deadLetter.Headers)) {
+		fmt.Fprintf(w, "Header:\t%s=%s\n", key, deadLetter.Headers[key])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	if utf8.Valid(deadLetter.Payload) {
+		fmt.Fprintf(out, "Payload:\n%s\n", deadLetter.Payload)
+	} else {
+		fmt.Fprintf(out, "Payload (base64):\n%s\n", base64.StdEncoding.EncodeToString(deadLetter.Payload))
+	}
+
+	fmt.Fprintf(out, "\nReplays: %d\n", len(replays))
+	for _, replay := range replays {
+		fmt.Fprintf(out, "  %s by %s\n", replay.ReplayedAt.UTC().Format(time.RFC3339), replay.ReplayedBy)
+	}
+	return nil
+}
+
+func replayDeadLetters(ctx context.Context, service *application.DeadLetterService, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("dlq replay", flag.ContinueOnError)
+	by := flags.String("by", os.Getenv("USER"), "Name the replay is recorded under")
+	filter, err := parseDeadLetterFilter(flags, args)
+	if err != nil {
+		return err
+	}
+	if *by == "" {
+		return errors.New("-by is required when $USER is not set")
+	}
+	replayedBy := "cli:" + *by
+
+	hasRange := !filter.From.IsZero() || !filter.To.IsZero()
+	if flags.NArg() > 0 == hasRange {
+		return errors.New("expected either dead letter ids or -from and -to")
+	}
+
+	var replayed []int64
+	if hasRange {
+		replayed, err = service.ReplayBetween(ctx, filter, replayedBy)
+	} else {
+		ids := make([]int64, flags.NArg())
+		for i, arg := range flags.Args() {
+			if ids[i], err = strconv.ParseInt(arg, 10, 64); err != nil {
+				return fmt.Errorf("invalid dead letter id %q", arg)
+			}
+		}
+		replayed, err = service.Replay(ctx, ids, replayedBy)
+	}
+
+	fmt.Fprintf(out, "Replayed %d dead letters\n", len(replayed))
+	for _, id := range replayed {
+		fmt.Fprintf(out, "  %d\n", id)
+	}
+	return err
+}
+
+// parseDeadLetterFilter parses the flags shared by list and replay
+func parseDeadLetterFilter(flags *flag.FlagSet, args []string) (domain.DeadLetterFilter, error) {
+	var filter domain.DeadLetterFilter
+	var from, to string
+	flags.StringVar(&filter.Consumer, "consumer", "", "Only dead letters of this consumer")
+	flags.StringVar(&filter.Topic, "topic", "", "Only dead letters first published to this topic")
+	flags.StringVar(&from, "from", "", "Only dead letters that failed at or after this time")
+	flags.StringVar(&to, "to", "", "Only dead letters that failed before this time")
+	flags.IntVar(&filter.Limit, "limit", application.DefaultDeadLetterLimit, "Maximum number of dead letters to list")
+	if err := flags.Parse(args); err != nil {
+		return filter, err
+	}
+
+	var err error
+	if from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return filter, fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return filter, fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+	return filter, nil
+}
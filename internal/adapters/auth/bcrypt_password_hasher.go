@@ -0,0 +1,28 @@
+package auth
+
+import "golang.org/x/crypto/bcrypt"
+
+type BcryptPasswordHasher struct {
+	cost int
+}
+
+// NewBcryptPasswordHasher creates a hasher with the given bcrypt cost; a
+// cost of 0 uses bcrypt.DefaultCost.
+func NewBcryptPasswordHasher(cost int) *BcryptPasswordHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptPasswordHasher{cost: cost}
+}
+
+func (h *BcryptPasswordHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptPasswordHasher) Compare(hash, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTTokenIssuer(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	issuer := NewJWTTokenIssuer([]byte("secret"), 15*time.Minute)
+	issuer.now = func() time.Time { return now }
+
+	token, expiresAt, err := issuer.Issue(42)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(15*time.Minute), expiresAt)
+
+	t.Run("valid token", func(t *testing.T) {
+		userID, err := issuer.Parse(token)
+		require.NoError(t, err)
+		assert.Equal(t, 42, userID)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		expired := NewJWTTokenIssuer([]byte("secret"), 15*time.Minute)
+		expired.now = func() time.Time { return now.Add(16 * time.Minute) }
+
+		_, err := expired.Parse(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		other := NewJWTTokenIssuer([]byte("other"), 15*time.Minute)
+		other.now = issuer.now
+
+		_, err := other.Parse(token)
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := issuer.Parse("not-a-token")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,29 @@
+package consumers
+
+import (
+	"uala-tweets/internal/events"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// decodeEvent unwraps the envelope of m with the codec its content type
+// names, so JSON and Protobuf messages can share a topic, and decodes its
+// payload. A message that cannot be decoded never will be, so the error is
+// permanent; an event with a schema version newer than this consumer knows
+// can be replayed from the dead-letter topic once the consumer is upgraded.
+func decodeEvent[T any](m kafka.Message, decode func(*events.Envelope) (T, error)) (T, error) {
+	var zero T
+	codec, err := events.CodecFor(headerValue(m, events.ContentTypeHeader))
+	if err != nil {
+		return zero, permanent(err)
+	}
+	envelope, err := codec.Unmarshal(m.Value)
+	if err != nil {
+		return zero, permanent(err)
+	}
+	event, err := decode(envelope)
+	if err != nil {
+		return zero, permanent(err)
+	}
+	return event, nil
+}
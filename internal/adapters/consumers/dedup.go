@@ -0,0 +1,40 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+
+	"uala-tweets/internal/ports/repositories"
+)
+
+// Names the consumers record processed events under
+const (
+	tweetConsumerName          = "tweet-consumer"
+	timelineFanoutConsumerName = "timeline-fanout-consumer"
+	followConsumerName         = "follow-consumer"
+)
+
+// alreadyProcessed reports whether consumer has processed the event before.
+// Events without an ID, published before events carried one, are always
+// processed, and so are events when the store cannot be reached: every
+// consumer can safely process an event twice, skipping is only a shortcut.
+func alreadyProcessed(ctx context.Context, store repositories.ProcessedEventStore, consumer, eventID string) bool {
+	if eventID == "" {
+		return false
+	}
+	processed, err := store.IsProcessed(ctx, consumer, eventID)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error checking whether the event was processed", "error", err)
+		return false
+	}
+	return processed
+}
+
+func markProcessed(ctx context.Context, store repositories.ProcessedEventStore, consumer, eventID string) {
+	if eventID == "" {
+		return
+	}
+	if err := store.MarkProcessed(ctx, consumer, eventID); err != nil {
+		slog.ErrorContext(ctx, "Error recording that the event was processed", "error", err)
+	}
+}
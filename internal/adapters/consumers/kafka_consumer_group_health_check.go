@@ -0,0 +1,51 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaGroupDescriber is the part of a Kafka client that describes consumer
+// groups
+type KafkaGroupDescriber interface {
+	DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error)
+}
+
+// KafkaConsumerGroupHealthCheck checks that the consumer groups of the
+// consumers have members and are not dead. A group only has members once
+// its consumers joined, so the check fails until then.
+type KafkaConsumerGroupHealthCheck struct {
+	client KafkaGroupDescriber
+	groups []string
+}
+
+func NewKafkaConsumerGroupHealthCheck(client KafkaGroupDescriber, groups []string) *KafkaConsumerGroupHealthCheck {
+	return &KafkaConsumerGroupHealthCheck{client: client, groups: groups}
+}
+
+func (c *KafkaConsumerGroupHealthCheck) Name() string { return "consumer_groups" }
+
+func (c *KafkaConsumerGroupHealthCheck) Check(ctx context.Context) error {
+	if len(c.groups) == 0 {
+		return nil
+	}
+
+	resp, err := c.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: c.groups})
+	if err != nil {
+		return fmt.Errorf("failed to describe consumer groups: %w", err)
+	}
+
+	var errs []error
+	for _, group := range resp.Groups {
+		switch {
+		case group.Error != nil:
+			errs = append(errs, fmt.Errorf("group %s: %w", group.GroupID, group.Error))
+		case group.GroupState == "Dead" || len(group.Members) == 0:
+			errs = append(errs, fmt.Errorf("group %s is %s with %d members", group.GroupID, group.GroupState, len(group.Members)))
+		}
+	}
+	return errors.Join(errs...)
+}
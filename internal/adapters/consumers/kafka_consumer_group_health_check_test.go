@@ -0,0 +1,62 @@
+package consumers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockGroupDescriber struct {
+	groups []kafka.DescribeGroupsResponseGroup
+}
+
+func (d *mockGroupDescriber) DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error) {
+	return &kafka.DescribeGroupsResponse{Groups: d.groups}, nil
+}
+
+func TestKafkaConsumerGroupHealthCheck(t *testing.T) {
+	member := []kafka.DescribeGroupsResponseMember{{MemberID: "worker-1"}}
+
+	testCases := []struct {
+		name    string
+		groups  []kafka.DescribeGroupsResponseGroup
+		wantErr string
+	}{
+		{
+			name: "groups with members",
+			groups: []kafka.DescribeGroupsResponseGroup{
+				{GroupID: "tweet-consumer-group", GroupState: "Stable", Members: member},
+				{GroupID: "fanout-consumer-group", GroupState: "PreparingRebalance", Members: member},
+			},
+		},
+		{
+			name: "group not joined yet",
+			groups: []kafka.DescribeGroupsResponseGroup{
+				{GroupID: "tweet-consumer-group", GroupState: "Stable", Members: member},
+				{GroupID: "fanout-consumer-group", GroupState: "Empty"},
+			},
+			wantErr: "group fanout-consumer-group is Empty with 0 members",
+		},
+		{
+			name: "group error",
+			groups: []kafka.DescribeGroupsResponseGroup{
+				{GroupID: "tweet-consumer-group", Error: kafka.GroupCoordinatorNotAvailable},
+			},
+			wantErr: "group tweet-consumer-group: [15] Group Coordinator Not Available",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			check := NewKafkaConsumerGroupHealthCheck(&mockGroupDescriber{groups: tc.groups}, []string{"tweet-consumer-group", "fanout-consumer-group"})
+			err := check.Check(context.Background())
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package consumers
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Backoff between attempts to archive a dead letter. Archiving is retried
+// until it succeeds, as there is nowhere left to send the message.
+const (
+	archiveBackoff    = 200 * time.Millisecond
+	archiveMaxBackoff = 10 * time.Second
+)
+
+const deadLetterConsumerName = "dead-letter-consumer"
+
+// KafkaDeadLetterConsumer archives the messages on the dead-letter topics
+// so they can be listed, inspected and replayed
+type KafkaDeadLetterConsumer struct {
+	reader         KafkaReader
+	deadLetterRepo repositories.DeadLetterRepository
+}
+
+func NewKafkaDeadLetterConsumer(reader KafkaReader, deadLetterRepo repositories.DeadLetterRepository) *KafkaDeadLetterConsumer {
+	return &KafkaDeadLetterConsumer{
+		reader:         reader,
+		deadLetterRepo: deadLetterRepo,
+	}
+}
+
+// Start starts the consumer loop. It should be run as a goroutine.
+func (c *KafkaDeadLetterConsumer) Start(ctx context.Context) error {
+	slog.InfoContext(ctx, "Starting dead letter consumer", "consumer", deadLetterConsumerName)
+	defer slog.InfoContext(ctx, "Dead letter consumer stopped", "consumer", deadLetterConsumerName)
+
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stopConsumer(ctx)
+			}
+			slog.ErrorContext(ctx, "Error fetching message from Kafka", "consumer", deadLetterConsumerName, "error", err)
+			continue
+		}
+		mctx := messageContext(ctx, deadLetterConsumerName, m)
+
+		deadLetter := toDeadLetter(m)
+		backoff := archiveBackoff
+		for {
+			err := c.deadLetterRepo.Create(ctx, deadLetter)
+			if err == nil {
+				break
+			}
+			slog.ErrorContext(mctx, "Error archiving dead letter, retrying", "backoff", backoff, "error", err)
+			if err := sleep(ctx, backoff); err != nil {
+				return stopConsumer(ctx)
+			}
+			backoff = min(2*backoff, archiveMaxBackoff)
+		}
+
+		slog.WarnContext(mctx, "Archived dead letter",
+			"dead_letter_id", deadLetter.ID, "failed_consumer", deadLetter.Consumer, "failure", deadLetter.Error)
+
+		// The dead letter is archived, so commit it even when stopping
+		if err := c.reader.CommitMessages(context.WithoutCancel(ctx), m); err != nil {
+			slog.ErrorContext(mctx, "Error committing offset", "error", err)
+		}
+	}
+}
+
+// toDeadLetter reads the failure back from the headers failedMessage set
+func toDeadLetter(m kafka.Message) *domain.DeadLetter {
+	deadLetter := &domain.DeadLetter{
+		Consumer:            headerValue(m, HeaderConsumer),
+		Topic:               headerValue(m, HeaderOriginalTopic),
+		Key:                 m.Key,
+		Payload:             m.Value,
+		Headers:             make(map[string]string),
+		Error:               headerValue(m, HeaderError),
+		Attempts:            redeliveryCount(m),
+		FailedAt:            m.Time,
+		DeadLetterTopic:     m.Topic,
+		DeadLetterPartition: m.Partition,
+		DeadLetterOffset:    m.Offset,
+	}
+	deadLetter.Partition, _ = strconv.Atoi(headerValue(m, HeaderOriginalPartition))
+	deadLetter.Offset, _ = strconv.ParseInt(headerValue(m, HeaderOriginalOffset), 10, 64)
+	if failedAt, err := time.Parse(time.RFC3339, headerValue(m, HeaderFailedAt)); err == nil {
+		deadLetter.FailedAt = failedAt
+	}
+
+	// Headers the message was published with travel along on replay
+	for _, h := range m.Headers {
+		if !isFailureHeader(h.Key) {
+			deadLetter.Headers[h.Key] = string(h.Value)
+		}
+	}
+
+	return deadLetter
+}
+
+func (c *KafkaDeadLetterConsumer) Close() error {
+	return c.reader.Close()
+}
@@ -0,0 +1,40 @@
+package consumers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToDeadLetter(t *testing.T) {
+	failed := failedMessage(
+		kafka.Message{
+			Topic:     "timeline.fanout",
+			Partition: 1,
+			Offset:    99,
+			Key:       []byte("fanout_42_1"),
+			Value:     []byte("payload"),
+			Headers:   []kafka.Header{{Key: "x-request-id", Value: []byte("abc")}},
+		},
+		"timeline-fanout-consumer", 4, errors.New("redis down"), time.Time{},
+	)
+	failed.Topic, failed.Partition, failed.Offset = "timeline.fanout.dlq", 0, 7
+
+	deadLetter := toDeadLetter(failed)
+
+	assert.Equal(t, "timeline-fanout-consumer", deadLetter.Consumer)
+	assert.Equal(t, "timeline.fanout", deadLetter.Topic)
+	assert.Equal(t, 1, deadLetter.Partition)
+	assert.Equal(t, int64(99), deadLetter.Offset)
+	assert.Equal(t, []byte("fanout_42_1"), deadLetter.Key)
+	assert.Equal(t, []byte("payload"), deadLetter.Payload)
+	assert.Equal(t, map[string]string{"x-request-id": "abc"}, deadLetter.Headers)
+	assert.Equal(t, "redis down", deadLetter.Error)
+	assert.Equal(t, 4, deadLetter.Attempts)
+	assert.False(t, deadLetter.FailedAt.IsZero())
+	assert.Equal(t, "timeline.fanout.dlq", deadLetter.DeadLetterTopic)
+	assert.Equal(t, int64(7), deadLetter.DeadLetterOffset)
+}
@@ -2,117 +2,107 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 
-	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/logging"
+	"uala-tweets/internal/ports/metrics"
 	"uala-tweets/internal/ports/repositories"
+
+	"github.com/segmentio/kafka-go"
 )
 
 type KafkaFollowConsumer struct {
 	reader        KafkaReader
 	timelineCache repositories.TimelineCache
 	tweetRepo     repositories.TweetRepository
+	processed     repositories.ProcessedEventStore
+	failures      *FailureHandler
+	pool          PoolConfig
+	recorder      metrics.ConsumerMetrics
 }
 
-func NewKafkaFollowConsumer(reader KafkaReader, timelineCache repositories.TimelineCache, tweetRepo repositories.TweetRepository) *KafkaFollowConsumer {
+func NewKafkaFollowConsumer(reader KafkaReader, timelineCache repositories.TimelineCache, tweetRepo repositories.TweetRepository, processed repositories.ProcessedEventStore, failures *FailureHandler, pool PoolConfig, recorder metrics.ConsumerMetrics) *KafkaFollowConsumer {
 	return &KafkaFollowConsumer{
 		reader:        reader,
 		timelineCache: timelineCache,
 		tweetRepo:     tweetRepo,
+		processed:     processed,
+		failures:      failures,
+		pool:          pool,
+		recorder:      recorder,
 	}
 }
 
 // Start starts the consumer loop. It should be run as a goroutine.
 func (c *KafkaFollowConsumer) Start(ctx context.Context) error {
-	log.Println("Starting Kafka follow consumer...")
-	defer log.Println("Stopped Kafka follow consumer")
-
-	for {
-		select {
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				log.Printf("Context canceled, stopping consumer")
-				return nil
-			}
-			log.Printf("Context done, stopping consumer: %v", ctx.Err())
-			return ctx.Err()
-		default:
-			m, err := c.reader.ReadMessage(ctx)
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					log.Printf("Context canceled, stopping consumer")
-					return nil
-				}
-				log.Printf("Error reading from kafka: %v", err)
-				continue
-			}
+	slog.InfoContext(ctx, "Starting follow consumer", "consumer", followConsumerName)
+	defer slog.InfoContext(ctx, "Follow consumer stopped", "consumer", followConsumerName)
 
-			log.Printf("Received follow event - Topic: %s, Partition: %d, Offset: %d", m.Topic, m.Partition, m.Offset)
+	return consume(ctx, followConsumerName, c.reader, c.failures, c.pool, c.recorder, c.process)
+}
 
-			var event domain.FollowEvent
-			if err := json.Unmarshal(m.Value, &event); err != nil {
-				log.Printf("Error unmarshaling follow event: %v, Raw: %s", err, string(m.Value))
-				continue
-			}
+func (c *KafkaFollowConsumer) process(ctx context.Context, m kafka.Message) error {
+	event, err := decodeEvent(m, events.DecodeFollow)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx,
+		slog.String("event_id", event.EventID),
+		slog.Int("follower_id", event.FollowerID),
+		slog.Int("followed_id", event.FollowedID),
+		slog.Bool("following", event.Following),
+	)
+
+	if alreadyProcessed(ctx, c.processed, followConsumerName, event.EventID) {
+		slog.InfoContext(ctx, "Skipping already processed follow event")
+		return nil
+	}
 
-			log.Printf("Processing follow event - Type: %s, FollowerID: %d, FollowedID: %d",
-				map[bool]string{true: "FOLLOW", false: "UNFOLLOW"}[event.Following],
-				event.FollowerID,
-				event.FollowedID)
+	slog.DebugContext(ctx, "Processing follow event")
 
-			if event.FollowerID == 0 || event.FollowedID == 0 {
-				log.Printf("Invalid follow event - missing IDs: %+v", event)
-				continue
-			}
+	if event.FollowerID == 0 || event.FollowedID == 0 {
+		return permanent(fmt.Errorf("invalid follow event - missing IDs: %+v", *event))
+	}
 
-			if event.Following {
-				// On follow: Add followed user's tweets to follower's timeline
-				tweetIDs, err := c.tweetRepo.GetTweetIDsByUser(event.FollowedID)
-				if err != nil {
-					log.Printf("Error getting tweet IDs for user %d: %v", event.FollowedID, err)
-					continue
-				}
-
-				log.Printf("Adding %d tweets to user %d's timeline from user %d",
-					len(tweetIDs), event.FollowerID, event.FollowedID)
-
-				for _, tweetID := range tweetIDs {
-					if err := c.timelineCache.AddToTimeline(event.FollowerID, tweetID); err != nil {
-						log.Printf("Error adding tweet %d to timeline for user %d: %v",
-							tweetID, event.FollowerID, err)
-					} else {
-						log.Printf("Successfully added tweet %d to user %d's timeline",
-							tweetID, event.FollowerID)
-					}
-				}
-			} else {
-				// On unfollow: Remove followed user's tweets from follower's timeline
-				tweetIDs, err := c.tweetRepo.GetTweetIDsByUser(event.FollowedID)
-				if err != nil {
-					log.Printf("Error getting tweet IDs for user %d: %v", event.FollowedID, err)
-					continue
-				}
-
-				log.Printf("Removing %d tweets from user %d's timeline (unfollowing user %d)",
-					len(tweetIDs), event.FollowerID, event.FollowedID)
-
-				for _, tweetID := range tweetIDs {
-					if err := c.timelineCache.RemoveFromTimeline(event.FollowerID, tweetID); err != nil {
-						log.Printf("Error removing tweet %d from timeline for user %d: %v",
-							tweetID, event.FollowerID, err)
-					} else {
-						log.Printf("Successfully removed tweet %d from user %d's timeline",
-							tweetID, event.FollowerID)
-					}
-				}
-			}
+	tweetIDs, err := c.tweetRepo.GetTweetIDsByUser(ctx, event.FollowedID)
+	if err != nil {
+		return fmt.Errorf("error getting tweet IDs for user %d: %w", event.FollowedID, err)
+	}
 
-			log.Printf("Completed processing follow event - FollowerID: %d, FollowedID: %d",
-				event.FollowerID, event.FollowedID)
+	// Adding and removing are both safe to repeat, so on a failure the
+	// whole event is retried rather than tracking which tweets made it
+	var failed []error
+	if event.Following {
+		// On follow: Merge the followed user's newest tweets into the
+		// follower's timeline, in order and in one step. Tweet IDs come
+		// newest first, and older ones would not fit in the timeline.
+		tweetIDs = tweetIDs[:min(len(tweetIDs), repositories.TimelineLength)]
+		slog.DebugContext(ctx, "Adding tweets of the followed user to the timeline", "tweets", len(tweetIDs))
+
+		if err := c.timelineCache.MergeIntoTimeline(ctx, event.FollowerID, tweetIDs, repositories.TimelineLength); err != nil {
+			failed = append(failed, fmt.Errorf("error adding tweets to timeline for user %d: %w", event.FollowerID, err))
 		}
+	} else {
+		// On unfollow: Remove followed user's tweets from follower's timeline
+		slog.DebugContext(ctx, "Removing tweets of the unfollowed user from the timeline", "tweets", len(tweetIDs))
+
+		for _, tweetID := range tweetIDs {
+			if err := c.timelineCache.RemoveFromTimeline(ctx, event.FollowerID, tweetID); err != nil {
+				failed = append(failed, fmt.Errorf("error removing tweet %d from timeline for user %d: %w",
+					tweetID, event.FollowerID, err))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Join(failed...)
 	}
+
+	markProcessed(ctx, c.processed, followConsumerName, event.EventID)
+	slog.InfoContext(ctx, "Follow event processed", "tweets", len(tweetIDs))
+	return nil
 }
 
 func (c *KafkaFollowConsumer) Close() error {
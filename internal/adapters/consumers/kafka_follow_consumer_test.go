@@ -0,0 +1,73 @@
+package consumers
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/ports/repositories"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTimelineCache keeps timelines the way the Redis cache does: newest
+// first, without duplicates and bounded by TimelineLength
+type memoryTimelineCache struct {
+	MockTimelineCache
+	mu        sync.Mutex
+	timelines map[int][]int64
+}
+
+func (c *memoryTimelineCache) MergeIntoTimeline(ctx context.Context, userID int, tweetIDs []int64, size int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timeline := append(slices.Clone(c.timelines[userID]), tweetIDs...)
+	slices.SortFunc(timeline, func(a, b int64) int { return int(b - a) })
+	timeline = slices.Compact(timeline)
+	c.timelines[userID] = timeline[:min(len(timeline), size, repositories.TimelineLength)]
+	return nil
+}
+
+func (c *memoryTimelineCache) Timeline(userID int) []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.timelines[userID])
+}
+
+func TestKafkaFollowConsumer_FollowKeepsTheNewestTweets(t *testing.T) {
+	// The followed user has more tweets than fit in a timeline, newest first
+	var followedTweets []int64
+	for id := int64(repositories.TimelineLength + 500); id > 0; id-- {
+		followedTweets = append(followedTweets, id)
+	}
+	tweetRepo := new(MockTweetRepository)
+	tweetRepo.On("GetTweetIDsByUser", 2).Return(followedTweets, nil)
+
+	// The follower's timeline already has newer tweets of other users
+	cache := &memoryTimelineCache{timelines: map[int][]int64{1: {5001, 5000}}}
+
+	reader := NewMockKafkaReader(kafka.Message{
+		Value: EventValue(events.NewFollow(&domain.FollowEvent{FollowerID: 1, FollowedID: 2, Following: true})),
+	})
+	consumer := NewKafkaFollowConsumer(reader, cache, tweetRepo, NewMockProcessedEventStore(), NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{}, &MockConsumerMetrics{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- consumer.Start(ctx) }()
+
+	require.Eventually(t, func() bool { return len(reader.Committed()) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	timeline := cache.Timeline(1)
+	require.Len(t, timeline, repositories.TimelineLength)
+	assert.Equal(t, []int64{5001, 5000, followedTweets[0]}, timeline[:3])
+	assert.Equal(t, followedTweets[repositories.TimelineLength-3], timeline[len(timeline)-1])
+}
@@ -2,75 +2,88 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"log"
-	"uala-tweets/internal/domain"
+	"fmt"
+	"log/slog"
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/logging"
+	"uala-tweets/internal/ports/metrics"
 	"uala-tweets/internal/ports/repositories"
+
+	"github.com/segmentio/kafka-go"
 )
 
 type KafkaTimelineFanoutConsumer struct {
-	reader        KafkaReader
-	timelineCache repositories.TimelineCache
-	followRepo    repositories.FollowRepository
+	reader            KafkaReader
+	timelineCache     repositories.TimelineCache
+	listTimelineCache repositories.TimelineCache
+	followRepo        repositories.FollowRepository
+	processed         repositories.ProcessedEventStore
+	failures          *FailureHandler
+	pool              PoolConfig
+	recorder          metrics.ConsumerMetrics
 }
 
-func NewKafkaTimelineFanoutConsumer(reader KafkaReader, timelineCache repositories.TimelineCache, followRepo repositories.FollowRepository) *KafkaTimelineFanoutConsumer {
+func NewKafkaTimelineFanoutConsumer(reader KafkaReader, timelineCache repositories.TimelineCache, listTimelineCache repositories.TimelineCache, followRepo repositories.FollowRepository, processed repositories.ProcessedEventStore, failures *FailureHandler, pool PoolConfig, recorder metrics.ConsumerMetrics) *KafkaTimelineFanoutConsumer {
 	return &KafkaTimelineFanoutConsumer{
-		reader:        reader,
-		timelineCache: timelineCache,
-		followRepo:    followRepo,
+		reader:            reader,
+		timelineCache:     timelineCache,
+		listTimelineCache: listTimelineCache,
+		followRepo:        followRepo,
+		processed:         processed,
+		failures:          failures,
+		pool:              pool,
+		recorder:          recorder,
 	}
 }
 
 // Start starts the consumer loop. It should be run as a goroutine.
 func (c *KafkaTimelineFanoutConsumer) Start(ctx context.Context) error {
-	log.Printf("Starting TimelineFanout consumer...")
-	defer log.Printf("TimelineFanout consumer stopped")
-
-	for {
-		select {
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				log.Printf("Context canceled, stopping consumer")
-				return nil
-			}
-			log.Printf("Context error, stopping consumer: %v", ctx.Err())
-			return ctx.Err()
-		default:
-			m, err := c.reader.ReadMessage(ctx)
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					log.Printf("Context canceled, stopping consumer")
-					return nil
-				}
-				log.Printf("Error reading message from Kafka: %v", err)
-				continue
-			}
-
-			log.Printf("Received message - Topic: %s, Partition: %d, Offset: %d", m.Topic, m.Partition, m.Offset)
-
-			var event domain.TimelineFanoutEvent
-			if err := json.Unmarshal(m.Value, &event); err != nil {
-				log.Printf("Error unmarshaling message: %v, Message: %s", err, string(m.Value))
-				continue
-			}
-
-			if event.UserID == 0 {
-				log.Printf("Received invalid event with UserID 0")
-				continue
-			}
-
-			log.Printf("Processing fanout event - UserID: %d, TweetID: %d", event.UserID, event.TweetID)
-
-			if err := c.timelineCache.AddToTimeline(event.UserID, event.TweetID); err != nil {
-				log.Printf("Error adding to timeline - UserID: %d, TweetID: %d, Error: %v", event.UserID, event.TweetID, err)
-				continue
-			}
-
-			log.Printf("Successfully processed fanout event - UserID: %d, TweetID: %d", event.UserID, event.TweetID)
+	slog.InfoContext(ctx, "Starting timeline fanout consumer", "consumer", timelineFanoutConsumerName)
+	defer slog.InfoContext(ctx, "Timeline fanout consumer stopped", "consumer", timelineFanoutConsumerName)
+
+	return consume(ctx, timelineFanoutConsumerName, c.reader, c.failures, c.pool, c.recorder, c.process)
+}
+
+func (c *KafkaTimelineFanoutConsumer) process(ctx context.Context, m kafka.Message) error {
+	event, err := decodeEvent(m, events.DecodeTimelineFanout)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, slog.String("event_id", event.EventID), slog.Int64("tweet_id", event.TweetID))
+
+	// Every tweet fans out to many timelines, so these are only logged
+	// when debugging
+	if alreadyProcessed(ctx, c.processed, timelineFanoutConsumerName, event.EventID) {
+		slog.DebugContext(ctx, "Skipping already processed fanout event")
+		return nil
+	}
+
+	if event.ListID != 0 {
+		slog.DebugContext(ctx, "Processing list fanout event", "list_id", event.ListID)
+
+		if err := c.listTimelineCache.AddToTimeline(ctx, event.ListID, event.TweetID); err != nil {
+			return fmt.Errorf("error adding tweet %d to list timeline %d: %w", event.TweetID, event.ListID, err)
 		}
+
+		markProcessed(ctx, c.processed, timelineFanoutConsumerName, event.EventID)
+		slog.DebugContext(ctx, "Added tweet to list timeline", "list_id", event.ListID)
+		return nil
+	}
+
+	if event.UserID == 0 {
+		return permanent(errors.New("invalid fanout event with UserID 0"))
 	}
+
+	slog.DebugContext(ctx, "Processing fanout event", "user_id", event.UserID)
+
+	if err := c.timelineCache.AddToTimeline(ctx, event.UserID, event.TweetID); err != nil {
+		return fmt.Errorf("error adding tweet %d to timeline of user %d: %w", event.TweetID, event.UserID, err)
+	}
+
+	markProcessed(ctx, c.processed, timelineFanoutConsumerName, event.EventID)
+	slog.DebugContext(ctx, "Added tweet to timeline", "user_id", event.UserID)
+	return nil
 }
 
 func (c *KafkaTimelineFanoutConsumer) Close() error {
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
@@ -18,17 +19,17 @@ type MockTimelineCache struct {
 	mock.Mock
 }
 
-func (m *MockTimelineCache) AddToTimeline(userID int, tweetID int64) error {
+func (m *MockTimelineCache) AddToTimeline(ctx context.Context, userID int, tweetID int64) error {
 	args := m.Called(userID, tweetID)
 	return args.Error(0)
 }
 
-func (m *MockTimelineCache) ClearTimeline(userID int) error {
+func (m *MockTimelineCache) ClearTimeline(ctx context.Context, userID int) error {
 	args := m.Called(userID)
 	return args.Error(0)
 }
 
-func (m *MockTimelineCache) GetTimeline(userID int, limit int) ([]int64, error) {
+func (m *MockTimelineCache) GetTimeline(ctx context.Context, userID int, limit int) ([]int64, error) {
 	args := m.Called(userID, limit)
 	if timeline, ok := args.Get(0).([]int64); ok {
 		return timeline, args.Error(1)
@@ -36,36 +37,101 @@ func (m *MockTimelineCache) GetTimeline(userID int, limit int) ([]int64, error)
 	return nil, args.Error(1)
 }
 
-func (m *MockTimelineCache) RemoveFromTimeline(userID int, tweetID int64) error {
+func (m *MockTimelineCache) RemoveFromTimeline(ctx context.Context, userID int, tweetID int64) error {
 	args := m.Called(userID, tweetID)
 	return args.Error(0)
 }
 
+func (m *MockTimelineCache) MergeIntoTimeline(ctx context.Context, userID int, tweetIDs []int64, size int) error {
+	args := m.Called(userID, tweetIDs, size)
+	return args.Error(0)
+}
+
+func (m *MockTimelineCache) RemoveFromTimelines(ctx context.Context, userIDs []int, tweetIDs []int64) error {
+	args := m.Called(userIDs, tweetIDs)
+	return args.Error(0)
+}
+
 func TestKafkaTimelineFanoutConsumer_Start(t *testing.T) {
 	testCases := []struct {
 		name       string
 		msgValue   []byte
-		setupMock  func(m *MockTimelineCache)
-		assertions func(t *testing.T, cache *MockTimelineCache)
+		setupMock  func(m, lists *MockTimelineCache)
+		msgHeaders []kafka.Header
+		assertions func(t *testing.T, cache, lists *MockTimelineCache)
 	}{
 		{
-			name: "successfully adds to timeline",
+			name:     "successfully adds to timeline",
+			msgValue: EventValue(events.NewTimelineFanout(&domain.TimelineFanoutEvent{TweetID: 1, UserID: 42})),
+			setupMock: func(m, lists *MockTimelineCache) {
+				m.On("AddToTimeline", 42, int64(1)).Return(nil)
+			},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
+				cache.AssertCalled(t, "AddToTimeline", 42, int64(1))
+			},
+		},
+		{
+			name: "protobuf event adds to timeline",
+			msgValue: func() []byte {
+				codec, _ := events.DefaultProtobufCodec()
+				envelope, _ := events.NewTimelineFanout(&domain.TimelineFanoutEvent{TweetID: 1, UserID: 42})
+				b, _ := codec.Marshal(envelope)
+				return b
+			}(),
+			msgHeaders: []kafka.Header{{Key: events.ContentTypeHeader, Value: []byte(events.ContentTypeProtobuf)}},
+			setupMock: func(m, lists *MockTimelineCache) {
+				m.On("AddToTimeline", 42, int64(1)).Return(nil)
+			},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
+				cache.AssertCalled(t, "AddToTimeline", 42, int64(1))
+			},
+		},
+		{
+			name:       "unsupported content type does not add to timeline",
+			msgValue:   EventValue(events.NewTimelineFanout(&domain.TimelineFanoutEvent{TweetID: 1, UserID: 42})),
+			msgHeaders: []kafka.Header{{Key: events.ContentTypeHeader, Value: []byte("application/avro")}},
+			setupMock:  func(m, lists *MockTimelineCache) {},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
+				cache.AssertNotCalled(t, "AddToTimeline", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "legacy event published without an envelope adds to timeline",
 			msgValue: func() []byte {
 				b, _ := json.Marshal(&domain.TimelineFanoutEvent{TweetID: 1, UserID: 42})
 				return b
 			}(),
-			setupMock: func(m *MockTimelineCache) {
+			setupMock: func(m, lists *MockTimelineCache) {
 				m.On("AddToTimeline", 42, int64(1)).Return(nil)
 			},
-			assertions: func(t *testing.T, cache *MockTimelineCache) {
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
 				cache.AssertCalled(t, "AddToTimeline", 42, int64(1))
 			},
 		},
+		{
+			name:     "list event adds to list timeline",
+			msgValue: EventValue(events.NewTimelineFanout(&domain.TimelineFanoutEvent{TweetID: 1, ListID: 7})),
+			setupMock: func(m, lists *MockTimelineCache) {
+				lists.On("AddToTimeline", 7, int64(1)).Return(nil)
+			},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
+				lists.AssertCalled(t, "AddToTimeline", 7, int64(1))
+				cache.AssertNotCalled(t, "AddToTimeline", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:      "redelivered event is skipped",
+			msgValue:  EventValue(events.NewTimelineFanout(&domain.TimelineFanoutEvent{EventID: "processed", TweetID: 1, UserID: 42})),
+			setupMock: func(m, lists *MockTimelineCache) {},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
+				cache.AssertNotCalled(t, "AddToTimeline", mock.Anything, mock.Anything)
+			},
+		},
 		{
 			name:      "invalid JSON does not add to timeline",
 			msgValue:  []byte("not json"),
-			setupMock: func(m *MockTimelineCache) {},
-			assertions: func(t *testing.T, cache *MockTimelineCache) {
+			setupMock: func(m, lists *MockTimelineCache) {},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
 				cache.AssertNotCalled(t, "AddToTimeline", mock.Anything, mock.Anything)
 			},
 		},
@@ -75,8 +141,8 @@ func TestKafkaTimelineFanoutConsumer_Start(t *testing.T) {
 				b, _ := json.Marshal(&domain.TimelineFanoutEvent{TweetID: 1, UserID: 0})
 				return b
 			}(),
-			setupMock: func(m *MockTimelineCache) {},
-			assertions: func(t *testing.T, cache *MockTimelineCache) {
+			setupMock: func(m, lists *MockTimelineCache) {},
+			assertions: func(t *testing.T, cache, lists *MockTimelineCache) {
 				cache.AssertNotCalled(t, "AddToTimeline", mock.Anything, mock.Anything)
 			},
 		},
@@ -84,11 +150,13 @@ func TestKafkaTimelineFanoutConsumer_Start(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			mockReader := NewMockKafkaReader(kafka.Message{Value: tc.msgValue})
+			mockReader := NewMockKafkaReader(kafka.Message{Value: tc.msgValue, Headers: tc.msgHeaders})
 			mockCache := new(MockTimelineCache)
-			tc.setupMock(mockCache)
+			mockListCache := new(MockTimelineCache)
+			tc.setupMock(mockCache, mockListCache)
 
-			consumer := NewKafkaTimelineFanoutConsumer(mockReader, mockCache, &MockFollowRepository{})
+			processed := NewMockProcessedEventStore(timelineFanoutConsumerName + "/processed")
+			consumer := NewKafkaTimelineFanoutConsumer(mockReader, mockCache, mockListCache, &MockFollowRepository{}, processed, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{}, &MockConsumerMetrics{})
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
@@ -104,7 +172,7 @@ func TestKafkaTimelineFanoutConsumer_Start(t *testing.T) {
 			time.Sleep(10 * time.Millisecond)
 
 			// Verify the assertions
-			tc.assertions(t, mockCache)
+			tc.assertions(t, mockCache, mockListCache)
 
 			// Cancel the context to stop the consumer
 			cancel()
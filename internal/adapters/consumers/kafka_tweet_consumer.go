@@ -2,11 +2,13 @@ package consumers
 
 import (
 	"context"
-	"encoding/json"
-	"errors"
-	"log"
+	"fmt"
+	"log/slog"
 	"time"
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/logging"
+	"uala-tweets/internal/ports/metrics"
 	"uala-tweets/internal/ports/publishers"
 	"uala-tweets/internal/ports/repositories"
 
@@ -14,7 +16,8 @@ import (
 )
 
 type KafkaReader interface {
-	ReadMessage(ctx context.Context) (kafka.Message, error)
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
 	Close() error
 }
 
@@ -23,101 +26,120 @@ type KafkaTweetConsumer struct {
 	tweetRepo  repositories.TweetRepository
 	fanoutPub  publishers.TimelineFanoutPublisher
 	followRepo repositories.FollowRepository
+	listRepo   repositories.ListRepository
+	processed  repositories.ProcessedEventStore
+	failures   *FailureHandler
+	pool       PoolConfig
+	recorder   metrics.ConsumerMetrics
+	fanouts    metrics.FanoutMetrics
 }
 
-func NewKafkaTweetConsumer(reader KafkaReader, tweetRepo repositories.TweetRepository, fanoutPub publishers.TimelineFanoutPublisher, followRepo repositories.FollowRepository) *KafkaTweetConsumer {
+func NewKafkaTweetConsumer(reader KafkaReader, tweetRepo repositories.TweetRepository, fanoutPub publishers.TimelineFanoutPublisher, followRepo repositories.FollowRepository, listRepo repositories.ListRepository, processed repositories.ProcessedEventStore, failures *FailureHandler, pool PoolConfig, recorder metrics.ConsumerMetrics, fanouts metrics.FanoutMetrics) *KafkaTweetConsumer {
 	return &KafkaTweetConsumer{
 		reader:     reader,
 		tweetRepo:  tweetRepo,
 		fanoutPub:  fanoutPub,
 		followRepo: followRepo,
+		listRepo:   listRepo,
+		processed:  processed,
+		failures:   failures,
+		pool:       pool,
+		recorder:   recorder,
+		fanouts:    fanouts,
 	}
 }
 
 // Start starts the consumer loop. It should be run as a goroutine.
 func (c *KafkaTweetConsumer) Start(ctx context.Context) error {
-	log.Println("Starting Tweet consumer...")
-	defer log.Println("Tweet consumer stopped")
-
-	for {
-		select {
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				log.Printf("Context canceled, stopping consumer")
-				return nil
-			}
-			log.Printf("Context error, stopping consumer: %v", ctx.Err())
-			return ctx.Err()
-		default:
-			m, err := c.reader.ReadMessage(ctx)
-			if err != nil {
-				if errors.Is(err, context.Canceled) {
-					log.Printf("Context canceled, stopping consumer")
-					return nil
-				}
-				log.Printf("Error reading message from Kafka: %v", err)
-				continue
-			}
-
-			log.Printf("Received tweet message - Topic: %s, Partition: %d, Offset: %d",
-				m.Topic, m.Partition, m.Offset)
-
-			var tweet domain.Tweet
-			if err := json.Unmarshal(m.Value, &tweet); err != nil {
-				log.Printf("Error unmarshaling tweet: %v, Raw: %s", err, string(m.Value))
-				continue
-			}
-
-			log.Printf("Processing new tweet - ID: %d, UserID: %d, Content: %.50s...",
-				tweet.ID, tweet.UserID, tweet.Content)
-
-			// Persist the tweet
-			if err := c.tweetRepo.Create(&tweet); err != nil {
-				log.Printf("Error persisting tweet %d: %v", tweet.ID, err)
-				continue
-			}
-
-			log.Printf("Successfully persisted tweet %d, finding followers for user %d",
-				tweet.ID, tweet.UserID)
-
-			// After successful persistence, publish one fan-out event per user (author + followers)
-			followers, err := c.followRepo.GetFollowers(int(tweet.UserID))
-			if err != nil {
-				log.Printf("Error getting followers for user %d: %v. Will only fanout to author.",
-					tweet.UserID, err)
-				followers = []int{}
-			}
-
-			userIDs := append([]int{int(tweet.UserID)}, followers...)
-			log.Printf("Fanning out tweet %d to %d users (author + %d followers)",
-				tweet.ID, len(userIDs), len(followers))
-
-			for _, userID := range userIDs {
-				func(uid int) {
-					event := &domain.TimelineFanoutEvent{
-						TweetID: tweet.ID,
-						UserID:  uid,
-					}
-
-					log.Printf("Publishing fanout event - TweetID: %d, UserID: %d",
-						event.TweetID, event.UserID)
-
-					fanoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-					defer cancel()
-
-					if err := c.fanoutPub.PublishFanoutEvent(fanoutCtx, event); err != nil {
-						log.Printf("Error publishing fanout event for tweet %d to user %d: %v",
-							event.TweetID, event.UserID, err)
-					} else {
-						log.Printf("Successfully published fanout event for tweet %d to user %d",
-							event.TweetID, event.UserID)
-					}
-				}(userID)
-			}
-
-			log.Printf("Completed processing tweet %d", tweet.ID)
+	slog.InfoContext(ctx, "Starting tweet consumer", "consumer", tweetConsumerName)
+	defer slog.InfoContext(ctx, "Tweet consumer stopped", "consumer", tweetConsumerName)
+
+	return consume(ctx, tweetConsumerName, c.reader, c.failures, c.pool, c.recorder, c.process)
+}
+
+func (c *KafkaTweetConsumer) process(ctx context.Context, m kafka.Message) error {
+	tweet, err := decodeEvent(m, events.DecodeTweetCreated)
+	if err != nil {
+		return err
+	}
+	ctx = logging.With(ctx, slog.String("event_id", tweet.EventID))
+
+	if alreadyProcessed(ctx, c.processed, tweetConsumerName, tweet.EventID) {
+		slog.InfoContext(ctx, "Skipping already processed tweet event")
+		return nil
+	}
+
+	slog.DebugContext(ctx, "Processing tweet", "user_id", tweet.UserID)
+
+	// Persist the tweet; a redelivered event gets the tweet stored
+	// the first time back instead of a duplicate
+	if err := c.tweetRepo.Create(ctx, tweet); err != nil {
+		return fmt.Errorf("error persisting tweet: %w", err)
+	}
+	// The tweet has an ID only once stored
+	ctx = logging.With(ctx, slog.Int64("tweet_id", tweet.ID))
+
+	slog.DebugContext(ctx, "Persisted tweet, finding followers", "user_id", tweet.UserID)
+
+	// After successful persistence, publish one fan-out event per user (author + followers)
+	followers, err := c.followRepo.GetFollowers(ctx, int(tweet.UserID))
+	if err != nil {
+		return fmt.Errorf("error getting followers for user %d: %w", tweet.UserID, err)
+	}
+
+	userIDs := append([]int{int(tweet.UserID)}, followers...)
+	slog.DebugContext(ctx, "Fanning out tweet to the author and followers", "followers", len(followers))
+
+	for _, userID := range userIDs {
+		if err := c.publishFanout(ctx, &domain.TimelineFanoutEvent{
+			EventID: fanoutEventID(tweet.EventID, "user", userID),
+			TweetID: tweet.ID,
+			UserID:  userID,
+		}); err != nil {
+			return err
 		}
 	}
+
+	// Lists the author is a member of get the tweet in their timeline too
+	listIDs, err := c.listRepo.GetListIDsByMember(ctx, int(tweet.UserID))
+	if err != nil {
+		return fmt.Errorf("error getting lists of user %d: %w", tweet.UserID, err)
+	}
+	for _, listID := range listIDs {
+		if err := c.publishFanout(ctx, &domain.TimelineFanoutEvent{
+			EventID: fanoutEventID(tweet.EventID, "list", listID),
+			TweetID: tweet.ID,
+			ListID:  listID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	markProcessed(ctx, c.processed, tweetConsumerName, tweet.EventID)
+	c.fanouts.TweetFannedOut(len(userIDs) + len(listIDs))
+	slog.InfoContext(ctx, "Tweet fanned out", "users", len(userIDs), "lists", len(listIDs))
+	return nil
+}
+
+// fanoutEventID derives the ID of a fanout event from the tweet event, so
+// that fanning a redelivered tweet out again publishes events the fanout
+// consumer recognises as duplicates.
+func fanoutEventID(tweetEventID, target string, id int) string {
+	if tweetEventID == "" {
+		return domain.NewEventID()
+	}
+	return fmt.Sprintf("%s:%s:%d", tweetEventID, target, id)
+}
+
+func (c *KafkaTweetConsumer) publishFanout(ctx context.Context, event *domain.TimelineFanoutEvent) error {
+	fanoutCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := c.fanoutPub.PublishFanoutEvent(fanoutCtx, event); err != nil {
+		return fmt.Errorf("error publishing fanout event for tweet %d (user %d, list %d): %w",
+			event.TweetID, event.UserID, event.ListID, err)
+	}
+	return nil
 }
 
 func (c *KafkaTweetConsumer) Close() error {
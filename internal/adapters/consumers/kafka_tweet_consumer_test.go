@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
@@ -28,12 +29,12 @@ type MockTweetRepository struct {
 	mock.Mock
 }
 
-func (m *MockTweetRepository) Create(tweet *domain.Tweet) error {
+func (m *MockTweetRepository) Create(ctx context.Context, tweet *domain.Tweet) error {
 	args := m.Called(tweet)
 	return args.Error(0)
 }
 
-func (m *MockTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
+func (m *MockTweetRepository) GetByID(ctx context.Context, id int64) (*domain.Tweet, error) {
 	args := m.Called(id)
 	if tw, ok := args.Get(0).(*domain.Tweet); ok {
 		return tw, args.Error(1)
@@ -41,7 +42,15 @@ func (m *MockTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
 	return nil, args.Error(1)
 }
 
-func (m *MockTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet, error) {
+func (m *MockTweetRepository) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Tweet, error) {
+	args := m.Called(ids)
+	if tweets, ok := args.Get(0).([]*domain.Tweet); ok {
+		return tweets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTweetRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Tweet, error) {
 	args := m.Called(userID)
 	if tweets, ok := args.Get(0).([]*domain.Tweet); ok {
 		return tweets, args.Error(1)
@@ -49,7 +58,7 @@ func (m *MockTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet, error)
 	return nil, args.Error(1)
 }
 
-func (m *MockTweetRepository) GetTweetIDsByUser(userID int) ([]int64, error) {
+func (m *MockTweetRepository) GetTweetIDsByUser(ctx context.Context, userID int) ([]int64, error) {
 	args := m.Called(userID)
 	if tweetIDs, ok := args.Get(0).([]int64); ok {
 		return tweetIDs, args.Error(1)
@@ -57,6 +66,16 @@ func (m *MockTweetRepository) GetTweetIDsByUser(userID int) ([]int64, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockTweetRepository) Update(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(tweet)
+	return args.Error(0)
+}
+
+func (m *MockTweetRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 func TestKafkaTweetConsumer_Start(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -66,6 +85,18 @@ func TestKafkaTweetConsumer_Start(t *testing.T) {
 	}{
 		{
 			name:     "successfully consumes and stores tweet",
+			msgValue: EventValue(events.NewTweetCreated(&domain.Tweet{UserID: 42, Content: "hello", EventID: "event-1"})),
+			setupRepoMock: func(m *MockTweetRepository) {
+				m.On("Create", mock.AnythingOfType("*domain.Tweet")).Return(nil)
+			},
+			assertions: func(t *testing.T, repo *MockTweetRepository) {
+				repo.AssertCalled(t, "Create", mock.MatchedBy(func(tw *domain.Tweet) bool {
+					return tw.Content == "hello" && tw.UserID == 42 && tw.EventID == "event-1"
+				}))
+			},
+		},
+		{
+			name:     "stores legacy tweet published without an envelope",
 			msgValue: func() []byte { b, _ := json.Marshal(&domain.Tweet{ID: 1, UserID: 42, Content: "hello"}); return b }(),
 			setupRepoMock: func(m *MockTweetRepository) {
 				m.On("Create", mock.AnythingOfType("*domain.Tweet")).Return(nil)
@@ -76,6 +107,29 @@ func TestKafkaTweetConsumer_Start(t *testing.T) {
 				}))
 			},
 		},
+		{
+			name: "redelivered tweet is skipped",
+			msgValue: func() []byte {
+				b, _ := json.Marshal(&domain.Tweet{UserID: 42, Content: "hello", EventID: "processed"})
+				return b
+			}(),
+			setupRepoMock: func(m *MockTweetRepository) {},
+			assertions: func(t *testing.T, repo *MockTweetRepository) {
+				repo.AssertNotCalled(t, "Create", mock.Anything)
+			},
+		},
+		{
+			name: "unsupported schema version is not stored",
+			msgValue: func() []byte {
+				envelope, err := events.NewTweetCreated(&domain.Tweet{UserID: 42, Content: "hello"})
+				envelope.SchemaVersion = 99
+				return EventValue(envelope, err)
+			}(),
+			setupRepoMock: func(m *MockTweetRepository) {},
+			assertions: func(t *testing.T, repo *MockTweetRepository) {
+				repo.AssertNotCalled(t, "Create", mock.Anything)
+			},
+		},
 		{
 			name:          "invalid JSON does not call Create",
 			msgValue:      []byte("not json"),
@@ -92,7 +146,8 @@ func TestKafkaTweetConsumer_Start(t *testing.T) {
 			mockRepo := new(MockTweetRepository)
 			tc.setupRepoMock(mockRepo)
 
-			consumer := NewKafkaTweetConsumer(mockReader, mockRepo, &MockTimelineFanoutPublisher{}, &MockFollowRepository{})
+			processed := NewMockProcessedEventStore(tweetConsumerName + "/processed")
+			consumer := NewKafkaTweetConsumer(mockReader, mockRepo, &MockTimelineFanoutPublisher{}, &MockFollowRepository{}, &MockListRepository{}, processed, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{}, &MockConsumerMetrics{}, &MockConsumerMetrics{})
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
@@ -125,3 +180,43 @@ func TestKafkaTweetConsumer_Start(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaTweetConsumer_MarksEventProcessed(t *testing.T) {
+	msg, _ := json.Marshal(&domain.Tweet{UserID: 42, Content: "hello", EventID: "event-1"})
+	mockReader := NewMockKafkaReader(kafka.Message{Value: msg})
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("Create", mock.AnythingOfType("*domain.Tweet")).Return(nil)
+	processed := NewMockProcessedEventStore()
+	recorder := &MockConsumerMetrics{}
+
+	consumer := NewKafkaTweetConsumer(mockReader, mockRepo, &MockTimelineFanoutPublisher{}, &MockFollowRepository{}, &MockListRepository{}, processed, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{}, recorder, recorder)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consumer.Start(ctx)
+	}()
+
+	mockReader.WaitForRead()
+	time.Sleep(10 * time.Millisecond)
+
+	isProcessed, _ := processed.IsProcessed(ctx, tweetConsumerName, "event-1")
+	assert.True(t, isProcessed)
+	assert.Equal(t, []int{1}, recorder.Fanouts(), "the author has no followers")
+
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timed out waiting for consumer to stop")
+	}
+}
+
+func TestFanoutEventID(t *testing.T) {
+	// Fanning the same tweet event out twice yields the same event IDs
+	assert.Equal(t, fanoutEventID("event-1", "user", 42), fanoutEventID("event-1", "user", 42))
+	assert.NotEqual(t, fanoutEventID("event-1", "user", 7), fanoutEventID("event-1", "list", 7))
+	assert.NotEqual(t, fanoutEventID("", "user", 42), fanoutEventID("", "user", 42))
+}
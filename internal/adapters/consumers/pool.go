@@ -0,0 +1,287 @@
+package consumers
+
+import (
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"uala-tweets/internal/adapters/tracing"
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/logging"
+	"uala-tweets/internal/ports/metrics"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "uala-tweets/internal/adapters/consumers"
+
+// PoolConfig sizes the workers a consumer processes messages with
+type PoolConfig struct {
+	// Workers is how many messages are processed at once. Messages with the
+	// same key always go to the same worker, so they keep their order.
+	Workers int
+	// QueueSize is how many messages wait for each worker before the
+	// consumer stops fetching more
+	QueueSize int
+}
+
+// consume fetches messages until ctx is done and processes them with the
+// workers of pool. Messages are routed to workers by key, so those with the
+// same key are processed one after the other in the order they were
+// published, and a worker that falls behind holds fetching back. Once ctx
+// is done, consume stops fetching and returns when the messages in progress
+// are finished; messages still queued are delivered again. An offset is
+// committed once its message and every one before it in the partition are
+// processed or handed over to the retry or dead-letter topic, so a message
+// is never lost to a crash halfway through. Every attempt at processing a
+// message and the lag of every partition fetched from go to recorder, and
+// each attempt is traced in the trace the message was published in and
+// logged with the request ID it was published with.
+func consume(ctx context.Context, consumer string, reader KafkaReader, failures *FailureHandler, pool PoolConfig, recorder metrics.ConsumerMetrics, process func(context.Context, kafka.Message) error) error {
+	offsets := newOffsetTracker(reader)
+	stopCommits, commitsStopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(commitsStopped)
+		// Commit what was finished even when stopping
+		offsets.run(context.WithoutCancel(ctx), stopCommits)
+	}()
+	process = measured(consumer, recorder, traced(consumer, process))
+
+	queues := make([]chan kafka.Message, max(pool.Workers, 1))
+	var workers sync.WaitGroup
+	for i := range queues {
+		queues[i] = make(chan kafka.Message, max(pool.QueueSize, 0))
+		workers.Add(1)
+		go func(queue <-chan kafka.Message) {
+			defer workers.Done()
+			for m := range queue {
+				// Once ctx is done, what is left is delivered again
+				// after a restart
+				if ctx.Err() != nil {
+					continue
+				}
+				mctx := messageContext(ctx, consumer, m)
+				if err := waitForRedelivery(mctx, m); err != nil {
+					continue
+				}
+				if err := failures.handle(mctx, consumer, m, process); err != nil {
+					continue
+				}
+				offsets.done(m)
+			}
+		}(queues[i])
+	}
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+		workers.Wait()
+		close(stopCommits)
+		<-commitsStopped
+	}()
+
+	var unkeyed uint32
+	for {
+		m, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stopConsumer(ctx)
+			}
+			slog.ErrorContext(ctx, "Error fetching message from Kafka", "consumer", consumer, "error", err)
+			continue
+		}
+
+		slog.DebugContext(ctx, "Received message", "consumer", consumer, "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
+		if m.HighWaterMark > 0 {
+			recorder.ConsumerLag(consumer, m.Topic, m.Partition, m.HighWaterMark-m.Offset-1)
+		}
+
+		offsets.fetched(m)
+		worker := unkeyed % uint32(len(queues))
+		if len(m.Key) > 0 {
+			worker = keyHash(m.Key) % uint32(len(queues))
+		} else {
+			// Messages without a key have no order to keep
+			unkeyed++
+		}
+
+		select {
+		case queues[worker] <- m:
+		case <-ctx.Done():
+			return stopConsumer(ctx)
+		}
+	}
+}
+
+// messageContext returns ctx for handling m, whose records name the message
+// and the request it was published in. Messages published while handling m
+// carry the request ID on.
+func messageContext(ctx context.Context, consumer string, m kafka.Message) context.Context {
+	if id := headerValue(m, events.RequestIDHeader); id != "" {
+		ctx = logging.WithRequestID(ctx, id)
+	}
+	return logging.With(ctx,
+		slog.String("consumer", consumer),
+		slog.String("topic", m.Topic),
+		slog.Int("partition", m.Partition),
+		slog.Int64("offset", m.Offset),
+	)
+}
+
+// measured records every call to process with recorder
+func measured(consumer string, recorder metrics.ConsumerMetrics, process func(context.Context, kafka.Message) error) func(context.Context, kafka.Message) error {
+	return func(ctx context.Context, m kafka.Message) error {
+		start := time.Now()
+		err := process(ctx, m)
+		recorder.MessageProcessed(consumer, m.Topic, err == nil, time.Since(start))
+		return err
+	}
+}
+
+// traced runs every call to process in a span continuing the trace carried
+// in the headers of its message
+func traced(consumer string, process func(context.Context, kafka.Message) error) func(context.Context, kafka.Message) error {
+	tracer := otel.Tracer(tracerName)
+	return func(ctx context.Context, m kafka.Message) error {
+		ctx, span := tracer.Start(tracing.Extract(ctx, m.Headers), "process "+m.Topic,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				semconv.MessagingSystemKafka,
+				semconv.MessagingOperationTypeProcess,
+				semconv.MessagingDestinationName(m.Topic),
+				semconv.MessagingDestinationPartitionID(strconv.Itoa(m.Partition)),
+				semconv.MessagingKafkaOffset(int(m.Offset)),
+				attribute.String("consumer", consumer),
+			),
+		)
+		defer span.End()
+
+		err := process(ctx, m)
+		tracing.RecordError(span, err)
+		return err
+	}
+}
+
+func keyHash(key []byte) uint32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32()
+}
+
+type topicPartition struct {
+	topic     string
+	partition int
+}
+
+// offsetTracker commits the offset of each partition up to the last message
+// done with no message fetched before it still in progress. Workers only
+// record what they are done with; the commits are made by run, so that a
+// slow commit does not hold the workers up, and the offsets done while one
+// is in flight go out together in the next.
+type offsetTracker struct {
+	reader KafkaReader
+
+	mu        sync.Mutex
+	pending   map[topicPartition][]int64
+	doneAhead map[topicPartition]map[int64]bool
+	// committable is the offset each partition can be committed up to
+	committable map[topicPartition]int64
+
+	// ready wakes run when there is something to commit
+	ready chan struct{}
+}
+
+func newOffsetTracker(reader KafkaReader) *offsetTracker {
+	return &offsetTracker{
+		reader:      reader,
+		pending:     make(map[topicPartition][]int64),
+		doneAhead:   make(map[topicPartition]map[int64]bool),
+		committable: make(map[topicPartition]int64),
+		ready:       make(chan struct{}, 1),
+	}
+}
+
+// fetched records m as in progress. Messages of a partition are fetched in
+// offset order.
+func (t *offsetTracker) fetched(m kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tp := topicPartition{m.Topic, m.Partition}
+	t.pending[tp] = append(t.pending[tp], m.Offset)
+}
+
+// done records m as done and moves the committable offset of its partition
+// as far as the partition is done
+func (t *offsetTracker) done(m kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tp := topicPartition{m.Topic, m.Partition}
+	if t.doneAhead[tp] == nil {
+		t.doneAhead[tp] = make(map[int64]bool)
+	}
+	done := t.doneAhead[tp]
+	done[m.Offset] = true
+
+	pending := t.pending[tp]
+	committable := -1
+	for i, offset := range pending {
+		if !done[offset] {
+			break
+		}
+		delete(done, offset)
+		committable = i
+	}
+	if committable < 0 {
+		return
+	}
+	t.committable[tp] = pending[committable]
+	t.pending[tp] = pending[committable+1:]
+
+	select {
+	case t.ready <- struct{}{}:
+	default:
+		// run is already due to commit
+	}
+}
+
+// run commits offsets as they become committable until stop is closed, then
+// commits what is left. Being the only one to commit keeps the commits of
+// a partition in order.
+func (t *offsetTracker) run(ctx context.Context, stop <-chan struct{}) {
+	for {
+		select {
+		case <-t.ready:
+			t.commit(ctx)
+		case <-stop:
+			t.commit(ctx)
+			return
+		}
+	}
+}
+
+// commit commits the committable offset of every partition in one call
+func (t *offsetTracker) commit(ctx context.Context) {
+	t.mu.Lock()
+	commits := make([]kafka.Message, 0, len(t.committable))
+	for tp, offset := range t.committable {
+		commits = append(commits, kafka.Message{Topic: tp.topic, Partition: tp.partition, Offset: offset})
+	}
+	clear(t.committable)
+	t.mu.Unlock()
+
+	if len(commits) == 0 {
+		return
+	}
+	if err := t.reader.CommitMessages(ctx, commits...); err != nil && ctx.Err() == nil {
+		// The messages will be delivered again, which consumers tolerate
+		slog.ErrorContext(ctx, "Error committing offsets", "offsets", len(commits), "error", err)
+	}
+}
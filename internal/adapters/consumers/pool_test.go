@@ -0,0 +1,341 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/logging"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// messagesReader returns its messages one after the other, then blocks
+// until ctx is done
+type messagesReader struct {
+	mu        sync.Mutex
+	messages  []kafka.Message
+	fetched   int
+	committed []int64
+}
+
+func (r *messagesReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.mu.Lock()
+	if r.fetched < len(r.messages) {
+		m := r.messages[r.fetched]
+		r.fetched++
+		r.mu.Unlock()
+		return m, nil
+	}
+	r.mu.Unlock()
+	<-ctx.Done()
+	return kafka.Message{}, ctx.Err()
+}
+
+func (r *messagesReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range msgs {
+		r.committed = append(r.committed, m.Offset)
+	}
+	return nil
+}
+
+func (r *messagesReader) Close() error { return nil }
+
+func (r *messagesReader) Fetched() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fetched
+}
+
+func (r *messagesReader) Committed() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64(nil), r.committed...)
+}
+
+// CommittedUpTo reports whether offset was the last one committed. Commits
+// made together only commit the last offset.
+func (r *messagesReader) CommittedUpTo(offset int64) bool {
+	committed := r.Committed()
+	return len(committed) > 0 && committed[len(committed)-1] == offset
+}
+
+func TestConsume_KeepsOrderPerKey(t *testing.T) {
+	reader := &messagesReader{}
+	for offset := range 60 {
+		reader.messages = append(reader.messages, kafka.Message{
+			Topic:     "timeline.fanout",
+			Key:       fmt.Appendf(nil, "fanout_%d", offset%6),
+			Offset:    int64(offset),
+			Partition: 0,
+		})
+	}
+
+	var mu sync.Mutex
+	processed := make(map[string][]int64)
+	process := func(ctx context.Context, m kafka.Message) error {
+		// Later messages finish sooner, so only the pool keeps them in order
+		time.Sleep(time.Duration(60-m.Offset) * 50 * time.Microsecond)
+		mu.Lock()
+		defer mu.Unlock()
+		processed[string(m.Key)] = append(processed[string(m.Key)], m.Offset)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume(ctx, "fanout-consumer", reader, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{Workers: 4, QueueSize: 2}, &MockConsumerMetrics{}, process)
+	}()
+
+	require.Eventually(t, func() bool { return reader.CommittedUpTo(59) }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, processed, 6)
+	for key, offsets := range processed {
+		assert.IsIncreasing(t, offsets, key)
+		assert.Len(t, offsets, 10, key)
+	}
+
+	committed := reader.Committed()
+	assert.IsIncreasing(t, committed)
+}
+
+func TestConsume_HoldsFetchingBackWhenWorkersAreBusy(t *testing.T) {
+	reader := &messagesReader{}
+	for offset := range 10 {
+		reader.messages = append(reader.messages, kafka.Message{Topic: "tweets.created", Key: []byte("tweet_1"), Offset: int64(offset)})
+	}
+
+	release := make(chan struct{})
+	process := func(ctx context.Context, m kafka.Message) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume(ctx, "tweet-consumer", reader, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{Workers: 2, QueueSize: 1}, &MockConsumerMetrics{}, process)
+	}()
+
+	// One message being processed, one queued and one waiting to be queued
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 3, reader.Fetched())
+	assert.Empty(t, reader.Committed())
+
+	close(release)
+	require.Eventually(t, func() bool { return reader.Fetched() == 10 }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestConsume_FinishesMessageInProgressWhenStopped(t *testing.T) {
+	reader := &messagesReader{messages: []kafka.Message{
+		{Topic: "tweets.created", Key: []byte("tweet_1"), Offset: 0},
+		{Topic: "tweets.created", Key: []byte("tweet_1"), Offset: 1},
+	}}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var processed []int64
+	process := func(ctx context.Context, m kafka.Message) error {
+		if m.Offset == 0 {
+			close(started)
+			<-release
+		}
+		processed = append(processed, m.Offset)
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume(ctx, "tweet-consumer", reader, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{Workers: 1, QueueSize: 1}, &MockConsumerMetrics{}, process)
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-errCh:
+		t.Fatal("Consumer stopped before finishing its message")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-errCh)
+	assert.Equal(t, []int64{0}, processed, "the queued message is left for redelivery")
+	assert.Equal(t, []int64{0}, reader.Committed())
+}
+
+func TestConsume_RecordsMetrics(t *testing.T) {
+	reader := &messagesReader{messages: []kafka.Message{
+		{Topic: "user.follow.events", Partition: 2, Offset: 7, HighWaterMark: 10},
+		{Topic: "user.follow.events", Partition: 2, Offset: 8, HighWaterMark: 10},
+	}}
+
+	failed := false
+	process := func(ctx context.Context, m kafka.Message) error {
+		if m.Offset == 8 && !failed {
+			failed = true
+			return errors.New("redis unavailable")
+		}
+		return nil
+	}
+
+	recorder := &MockConsumerMetrics{}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume(ctx, "follow-consumer", reader, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{Workers: 1}, recorder, process)
+	}()
+
+	require.Eventually(t, func() bool { return reader.CommittedUpTo(8) }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	processed, failures := recorder.Processed()
+	assert.Equal(t, 3, processed, "every attempt is recorded")
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, map[string]int64{"user.follow.events/2": 1}, recorder.Lag())
+}
+
+func TestConsume_ContinuesTheTraceOfMessages(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	reader := &messagesReader{messages: []kafka.Message{{
+		Topic:   "user.follow.events",
+		Offset:  7,
+		Headers: []kafka.Header{{Key: "traceparent", Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")}},
+	}}}
+
+	var processed trace.SpanContext
+	process := func(ctx context.Context, m kafka.Message) error {
+		processed = trace.SpanContextFromContext(ctx)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume(ctx, "follow-consumer", reader, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{Workers: 1}, &MockConsumerMetrics{}, process)
+	}()
+
+	require.Eventually(t, func() bool { return len(reader.Committed()) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "process user.follow.events", spans[0].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+	assert.Equal(t, spans[0].SpanContext.SpanID(), processed.SpanID())
+}
+
+func TestConsume_RestoresTheRequestIDOfMessages(t *testing.T) {
+	reader := &messagesReader{messages: []kafka.Message{
+		{Topic: "user.follow.events", Offset: 1, Headers: []kafka.Header{{Key: events.RequestIDHeader, Value: []byte("req-1")}}},
+		{Topic: "user.follow.events", Offset: 2},
+	}}
+
+	var mu sync.Mutex
+	var requestIDs []string
+	process := func(ctx context.Context, m kafka.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		requestIDs = append(requestIDs, logging.RequestID(ctx))
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consume(ctx, "follow-consumer", reader, NewTestFailureHandler(&MockKafkaWriter{}, &MockKafkaWriter{}), PoolConfig{Workers: 1}, &MockConsumerMetrics{}, process)
+	}()
+
+	require.Eventually(t, func() bool { return reader.CommittedUpTo(2) }, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	assert.Equal(t, []string{"req-1", ""}, requestIDs)
+}
+
+func TestOffsetTracker_CommitsContiguousOffsets(t *testing.T) {
+	reader := &messagesReader{}
+	tracker := newOffsetTracker(reader)
+	message := func(partition int, offset int64) kafka.Message {
+		return kafka.Message{Topic: "user.follow.events", Partition: partition, Offset: offset}
+	}
+
+	for offset := int64(10); offset < 14; offset++ {
+		tracker.fetched(message(0, offset))
+	}
+	tracker.fetched(message(1, 3))
+
+	ctx := context.Background()
+	tracker.done(message(0, 12))
+	tracker.done(message(0, 11))
+	tracker.commit(ctx)
+	assert.Empty(t, reader.Committed(), "offset 10 is still in progress")
+
+	tracker.done(message(0, 10))
+	tracker.commit(ctx)
+	assert.Equal(t, []int64{12}, reader.Committed())
+
+	tracker.done(message(1, 3))
+	tracker.commit(ctx)
+	tracker.done(message(0, 13))
+	tracker.commit(ctx)
+	assert.Equal(t, []int64{12, 3, 13}, reader.Committed())
+}
+
+func TestOffsetTracker_CoalescesCommits(t *testing.T) {
+	reader := &messagesReader{}
+	tracker := newOffsetTracker(reader)
+	message := func(offset int64) kafka.Message {
+		return kafka.Message{Topic: "user.follow.events", Offset: offset}
+	}
+
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	for offset := int64(0); offset < 3; offset++ {
+		tracker.fetched(message(offset))
+	}
+	// Done before run starts, as if while a commit was in flight
+	tracker.done(message(0))
+	tracker.done(message(1))
+	go func() {
+		defer close(stopped)
+		tracker.run(context.Background(), stop)
+	}()
+	require.Eventually(t, func() bool { return len(reader.Committed()) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, []int64{1}, reader.Committed())
+
+	// What is done by the time run stops is still committed
+	tracker.done(message(2))
+	close(stop)
+	<-stopped
+	assert.Equal(t, []int64{1, 2}, reader.Committed())
+}
@@ -0,0 +1,244 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers describing why a message was moved to a retry or dead-letter
+// topic. The original key and payload are kept as they were.
+const (
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderConsumer          = "x-consumer"
+	HeaderAttempt           = "x-attempt"
+	HeaderError             = "x-error"
+	HeaderFailedAt          = "x-failed-at"
+	HeaderRetryAt           = "x-retry-at"
+)
+
+// KafkaWriter writes messages to the topic it was configured with
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// RetryPolicy bounds how hard consumers try before giving up on a message
+type RetryPolicy struct {
+	// Attempts is how many times a message is processed in a row before
+	// it is moved to the retry topic
+	Attempts int
+	// Backoff is the wait before the second attempt, doubled for every
+	// further one up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Redeliveries is how many times a message goes through the retry
+	// topic before it is dead-lettered
+	Redeliveries int
+	// RedeliveryDelay is how long the retry topic holds a message back,
+	// doubled for every further redelivery
+	RedeliveryDelay time.Duration
+}
+
+// FailureHandler retries messages a consumer fails to process and moves
+// them to the consumer's retry topic, or to its dead-letter topic once the
+// redeliveries are used up or the failure is permanent.
+type FailureHandler struct {
+	retry      KafkaWriter
+	deadLetter KafkaWriter
+	policy     RetryPolicy
+}
+
+func NewFailureHandler(retry, deadLetter KafkaWriter, policy RetryPolicy) *FailureHandler {
+	return &FailureHandler{
+		retry:      retry,
+		deadLetter: deadLetter,
+		policy:     policy,
+	}
+}
+
+// permanentError marks failures that retrying cannot fix, like a payload
+// that does not parse
+type permanentError struct {
+	err error
+}
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func stopConsumer(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.Canceled) {
+		slog.InfoContext(ctx, "Context canceled, stopping consumer")
+		return nil
+	}
+	slog.ErrorContext(ctx, "Context error, stopping consumer", "error", ctx.Err())
+	return ctx.Err()
+}
+
+// handle processes m, retrying with exponential backoff, and hands it
+// over to the retry or dead-letter topic when it keeps failing. It only
+// returns an error when ctx is done before m was dealt with. An attempt
+// under way when ctx is done runs to the end, so a consumer that is
+// stopping finishes its message rather than abandoning it halfway.
+func (h *FailureHandler) handle(ctx context.Context, consumer string, m kafka.Message, process func(context.Context, kafka.Message) error) error {
+	backoff := h.policy.Backoff
+	for attempt := 1; ; attempt++ {
+		err := process(context.WithoutCancel(ctx), m)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if errors.As(err, new(*permanentError)) || attempt >= h.policy.Attempts {
+			return h.giveUp(ctx, consumer, m, err)
+		}
+
+		slog.WarnContext(ctx, "Error processing message, retrying",
+			"attempt", attempt, "attempts", h.policy.Attempts, "backoff", backoff, "error", err)
+		if err := sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = min(2*backoff, h.policy.MaxBackoff)
+	}
+}
+
+// giveUp writes m to the retry topic, or to the dead-letter topic when it
+// cannot be retried any more, trying until the write succeeds: committing
+// m before that would lose it.
+func (h *FailureHandler) giveUp(ctx context.Context, consumer string, m kafka.Message, cause error) error {
+	redeliveries := redeliveryCount(m)
+
+	writer, topic := h.deadLetter, "dead-letter"
+	var retryAt time.Time
+	if !errors.As(cause, new(*permanentError)) && redeliveries < h.policy.Redeliveries {
+		writer, topic = h.retry, "retry"
+		retryAt = time.Now().Add(h.policy.RedeliveryDelay << redeliveries)
+	}
+
+	failed := failedMessage(m, consumer, redeliveries+1, cause, retryAt)
+	slog.ErrorContext(ctx, "Moving message to the "+topic+" topic",
+		"redeliveries", redeliveries, "error", cause)
+
+	backoff := h.policy.Backoff
+	for {
+		err := writer.WriteMessages(ctx, failed)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		slog.ErrorContext(ctx, "Error writing message to the "+topic+" topic, retrying",
+			"backoff", backoff, "error", err)
+		if err := sleep(ctx, backoff); err != nil {
+			return err
+		}
+		backoff = min(2*backoff, h.policy.MaxBackoff)
+	}
+}
+
+// failedMessage copies m with headers recording the failure. A message that
+// failed before keeps pointing at where it was first published.
+func failedMessage(m kafka.Message, consumer string, attempt int, cause error, retryAt time.Time) kafka.Message {
+	originalTopic := headerValue(m, HeaderOriginalTopic)
+	originalPartition := headerValue(m, HeaderOriginalPartition)
+	originalOffset := headerValue(m, HeaderOriginalOffset)
+	if originalTopic == "" {
+		originalTopic = m.Topic
+		originalPartition = strconv.Itoa(m.Partition)
+		originalOffset = strconv.FormatInt(m.Offset, 10)
+	}
+
+	headers := make([]kafka.Header, 0, len(m.Headers)+8)
+	for _, h := range m.Headers {
+		if !isFailureHeader(h.Key) {
+			headers = append(headers, h)
+		}
+	}
+	headers = append(headers,
+		kafka.Header{Key: HeaderOriginalTopic, Value: []byte(originalTopic)},
+		kafka.Header{Key: HeaderOriginalPartition, Value: []byte(originalPartition)},
+		kafka.Header{Key: HeaderOriginalOffset, Value: []byte(originalOffset)},
+		kafka.Header{Key: HeaderConsumer, Value: []byte(consumer)},
+		kafka.Header{Key: HeaderAttempt, Value: []byte(strconv.Itoa(attempt))},
+		kafka.Header{Key: HeaderError, Value: []byte(cause.Error())},
+		kafka.Header{Key: HeaderFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+	if !retryAt.IsZero() {
+		headers = append(headers, kafka.Header{Key: HeaderRetryAt, Value: []byte(retryAt.UTC().Format(time.RFC3339Nano))})
+	}
+
+	return kafka.Message{
+		Key:     m.Key,
+		Value:   m.Value,
+		Headers: headers,
+	}
+}
+
+func isFailureHeader(key string) bool {
+	switch key {
+	case HeaderOriginalTopic, HeaderOriginalPartition, HeaderOriginalOffset,
+		HeaderConsumer, HeaderAttempt, HeaderError, HeaderFailedAt, HeaderRetryAt:
+		return true
+	}
+	return false
+}
+
+func headerValue(m kafka.Message, key string) string {
+	for _, h := range m.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// redeliveryCount is how many times m already went through the retry topic
+func redeliveryCount(m kafka.Message) int {
+	attempt, err := strconv.Atoi(headerValue(m, HeaderAttempt))
+	if err != nil {
+		return 0
+	}
+	return attempt
+}
+
+// waitForRedelivery holds a message from the retry topic back until it is
+// due, so retries are spread out instead of failing again straight away
+func waitForRedelivery(ctx context.Context, m kafka.Message) error {
+	value := headerValue(m, HeaderRetryAt)
+	if value == "" {
+		return nil
+	}
+	retryAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		slog.WarnContext(ctx, "Ignoring invalid "+HeaderRetryAt+" header", "value", value, "error", err)
+		return nil
+	}
+	return sleep(ctx, time.Until(retryAt))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,194 @@
+package consumers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureHandler_Handle(t *testing.T) {
+	msg := kafka.Message{Topic: "tweets.created", Partition: 2, Offset: 40, Key: []byte("key"), Value: []byte("payload")}
+	redelivered := kafka.Message{
+		Topic: "tweets.created.retry",
+		Value: []byte("payload"),
+		Headers: []kafka.Header{
+			{Key: HeaderOriginalTopic, Value: []byte("tweets.created")},
+			{Key: HeaderOriginalPartition, Value: []byte("2")},
+			{Key: HeaderOriginalOffset, Value: []byte("40")},
+			{Key: HeaderAttempt, Value: []byte("2")},
+			{Key: HeaderRetryAt, Value: []byte(time.Now().Format(time.RFC3339Nano))},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		msg            kafka.Message
+		errs           []error
+		wantCalls      int
+		wantRetried    bool
+		wantDeadLetter bool
+		wantAttempt    string
+	}{
+		{
+			name:      "succeeds first time",
+			msg:       msg,
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "transient failure is retried",
+			msg:       msg,
+			errs:      []error{errors.New("redis down"), errors.New("redis down"), nil},
+			wantCalls: 3,
+		},
+		{
+			name:        "keeps failing moves to retry topic",
+			msg:         msg,
+			errs:        []error{errors.New("redis down"), errors.New("redis down"), errors.New("redis down")},
+			wantCalls:   3,
+			wantRetried: true,
+			wantAttempt: "1",
+		},
+		{
+			name:           "permanent failure is dead-lettered straight away",
+			msg:            msg,
+			errs:           []error{permanent(errors.New("bad payload"))},
+			wantCalls:      1,
+			wantDeadLetter: true,
+			wantAttempt:    "1",
+		},
+		{
+			name:           "redeliveries used up is dead-lettered",
+			msg:            redelivered,
+			errs:           []error{errors.New("redis down"), errors.New("redis down"), errors.New("redis down")},
+			wantCalls:      3,
+			wantDeadLetter: true,
+			wantAttempt:    "3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			retry, deadLetter := &MockKafkaWriter{}, &MockKafkaWriter{}
+			handler := NewTestFailureHandler(retry, deadLetter)
+
+			calls := 0
+			err := handler.handle(context.Background(), "tweet-consumer", tc.msg, func(ctx context.Context, m kafka.Message) error {
+				err := tc.errs[calls]
+				calls++
+				return err
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantCalls, calls)
+
+			var written []kafka.Message
+			if tc.wantRetried {
+				written = retry.Messages()
+				assert.Empty(t, deadLetter.Messages())
+			} else if tc.wantDeadLetter {
+				written = deadLetter.Messages()
+				assert.Empty(t, retry.Messages())
+			} else {
+				assert.Empty(t, retry.Messages())
+				assert.Empty(t, deadLetter.Messages())
+				return
+			}
+
+			require.Len(t, written, 1)
+			failed := written[0]
+			assert.Equal(t, tc.msg.Key, failed.Key)
+			assert.Equal(t, []byte("payload"), failed.Value)
+			assert.Empty(t, failed.Topic)
+			assert.Equal(t, "tweets.created", headerValue(failed, HeaderOriginalTopic))
+			assert.Equal(t, "2", headerValue(failed, HeaderOriginalPartition))
+			assert.Equal(t, "40", headerValue(failed, HeaderOriginalOffset))
+			assert.Equal(t, "tweet-consumer", headerValue(failed, HeaderConsumer))
+			assert.Equal(t, tc.wantAttempt, headerValue(failed, HeaderAttempt))
+			assert.NotEmpty(t, headerValue(failed, HeaderError))
+			assert.NotEmpty(t, headerValue(failed, HeaderFailedAt))
+			assert.Equal(t, tc.wantRetried, headerValue(failed, HeaderRetryAt) != "")
+		})
+	}
+}
+
+func TestConsume_CommitsAfterHandingOver(t *testing.T) {
+	mockReader := NewMockKafkaReader(kafka.Message{Topic: "tweets.created", Value: []byte("not json")})
+	mockRepo := new(MockTweetRepository)
+	deadLetter := &MockKafkaWriter{}
+	consumer := NewKafkaTweetConsumer(mockReader, mockRepo, &MockTimelineFanoutPublisher{}, &MockFollowRepository{}, &MockListRepository{}, NewMockProcessedEventStore(), NewTestFailureHandler(&MockKafkaWriter{}, deadLetter), PoolConfig{}, &MockConsumerMetrics{}, &MockConsumerMetrics{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consumer.Start(ctx)
+	}()
+
+	mockReader.WaitForRead()
+	time.Sleep(10 * time.Millisecond)
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	assert.Len(t, deadLetter.Messages(), 1)
+	assert.Len(t, mockReader.Committed(), 1)
+
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timed out waiting for consumer to stop")
+	}
+}
+
+func TestConsume_DoesNotCommitUnhandledMessage(t *testing.T) {
+	mockReader := NewMockKafkaReader(kafka.Message{Topic: "tweets.created", Value: []byte("not json")})
+	deadLetter := &MockKafkaWriter{err: errors.New("kafka down")}
+	consumer := NewKafkaTweetConsumer(mockReader, new(MockTweetRepository), &MockTimelineFanoutPublisher{}, &MockFollowRepository{}, &MockListRepository{}, NewMockProcessedEventStore(), NewTestFailureHandler(&MockKafkaWriter{}, deadLetter), PoolConfig{}, &MockConsumerMetrics{}, &MockConsumerMetrics{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- consumer.Start(ctx)
+	}()
+
+	mockReader.WaitForRead()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("Timed out waiting for consumer to stop")
+	}
+	assert.Empty(t, mockReader.Committed())
+}
+
+func TestWaitForRedelivery(t *testing.T) {
+	due := kafka.Message{Headers: []kafka.Header{
+		{Key: HeaderRetryAt, Value: []byte(time.Now().Add(20 * time.Millisecond).Format(time.RFC3339Nano))},
+	}}
+
+	start := time.Now()
+	require.NoError(t, waitForRedelivery(context.Background(), due))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	later := kafka.Message{Headers: []kafka.Header{
+		{Key: HeaderRetryAt, Value: []byte(time.Now().Add(time.Hour).Format(time.RFC3339Nano))},
+	}}
+	assert.ErrorIs(t, waitForRedelivery(ctx, later), context.Canceled)
+
+	assert.NoError(t, waitForRedelivery(context.Background(), kafka.Message{}))
+}
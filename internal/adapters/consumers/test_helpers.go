@@ -2,7 +2,13 @@ package consumers
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"sync"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/mock"
@@ -14,16 +20,89 @@ type MockKafkaReader struct {
 	once       sync.Once
 	readCalled chan struct{}
 	readCount  int
+	mu         sync.Mutex
+	committed  []kafka.Message
 }
 
 type MockFollowRepository struct{}
 
-func (m *MockFollowRepository) Follow(followerID, followedID int) error   { return nil }
-func (m *MockFollowRepository) Unfollow(followerID, followedID int) error { return nil }
-func (m *MockFollowRepository) IsFollowing(followerID, followedID int) (bool, error) {
+func (m *MockFollowRepository) Follow(ctx context.Context, followerID, followedID int) error {
+	return nil
+}
+func (m *MockFollowRepository) Unfollow(ctx context.Context, followerID, followedID int) error {
+	return nil
+}
+func (m *MockFollowRepository) IsFollowing(ctx context.Context, followerID, followedID int) (bool, error) {
 	return false, nil
 }
-func (m *MockFollowRepository) GetFollowers(userID int) ([]int, error) { return []int{}, nil }
+func (m *MockFollowRepository) GetFollowers(ctx context.Context, userID int) ([]int, error) {
+	return []int{}, nil
+}
+func (m *MockFollowRepository) GetFollowing(ctx context.Context, userID int) ([]int, error) {
+	return []int{}, nil
+}
+func (m *MockFollowRepository) GetFriendsOfFriends(ctx context.Context, userID int, limit int) ([]*domain.Suggestion, error) {
+	return nil, nil
+}
+
+type MockListRepository struct{}
+
+func (m *MockListRepository) Create(ctx context.Context, list *domain.List) error { return nil }
+func (m *MockListRepository) GetByID(ctx context.Context, id int) (*domain.List, error) {
+	return nil, nil
+}
+func (m *MockListRepository) GetByOwnerID(ctx context.Context, ownerID int) ([]*domain.List, error) {
+	return nil, nil
+}
+func (m *MockListRepository) Update(ctx context.Context, list *domain.List) error        { return nil }
+func (m *MockListRepository) Delete(ctx context.Context, id int) error                   { return nil }
+func (m *MockListRepository) AddMember(ctx context.Context, listID, userID int) error    { return nil }
+func (m *MockListRepository) RemoveMember(ctx context.Context, listID, userID int) error { return nil }
+func (m *MockListRepository) GetMembers(ctx context.Context, listID int) ([]int, error) {
+	return []int{}, nil
+}
+func (m *MockListRepository) GetListIDsByMember(ctx context.Context, userID int) ([]int, error) {
+	return []int{}, nil
+}
+
+// MockProcessedEventStore keeps processed events in memory
+type MockProcessedEventStore struct {
+	mu     sync.Mutex
+	events map[string]bool
+}
+
+func NewMockProcessedEventStore(processed ...string) *MockProcessedEventStore {
+	s := &MockProcessedEventStore{events: make(map[string]bool)}
+	for _, key := range processed {
+		s.events[key] = true
+	}
+	return s
+}
+
+func (s *MockProcessedEventStore) IsProcessed(ctx context.Context, consumer, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.events[consumer+"/"+eventID], nil
+}
+
+func (s *MockProcessedEventStore) MarkProcessed(ctx context.Context, consumer, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[consumer+"/"+eventID] = true
+	return nil
+}
+
+// EventValue wraps an event in its envelope as a Kafka message value
+func EventValue(envelope *events.Envelope, err error) []byte {
+	if err != nil {
+		panic(err)
+	}
+	data, err := events.Marshal(envelope)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
 
 func NewMockKafkaReader(msg kafka.Message) *MockKafkaReader {
 	return &MockKafkaReader{
@@ -32,7 +111,7 @@ func NewMockKafkaReader(msg kafka.Message) *MockKafkaReader {
 	}
 }
 
-func (m *MockKafkaReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+func (m *MockKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
 	m.readCount++
 	if m.readCount == 1 {
 		// First call - return the message
@@ -53,13 +132,118 @@ func (m *MockKafkaReader) ReadMessage(ctx context.Context) (kafka.Message, error
 	}
 }
 
+func (m *MockKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.committed = append(m.committed, msgs...)
+	return nil
+}
+
+// Committed returns the messages whose offsets were committed
+func (m *MockKafkaReader) Committed() []kafka.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]kafka.Message(nil), m.committed...)
+}
+
 func (m *MockKafkaReader) Close() error {
 	return nil
 }
 
-// WaitForRead waits for the ReadMessage method to be called
+// WaitForRead waits for the FetchMessage method to be called
 func (m *MockKafkaReader) WaitForRead() {
 	if m.readCalled != nil {
 		<-m.readCalled
 	}
 }
+
+// MockKafkaWriter records the messages written to it
+type MockKafkaWriter struct {
+	mu       sync.Mutex
+	messages []kafka.Message
+	err      error
+}
+
+func (w *MockKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return w.err
+	}
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+// Messages returns the messages written so far
+func (w *MockKafkaWriter) Messages() []kafka.Message {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]kafka.Message(nil), w.messages...)
+}
+
+// NewTestFailureHandler returns a FailureHandler that retries without
+// waiting, writing to the given retry and dead-letter writers
+func NewTestFailureHandler(retry, deadLetter *MockKafkaWriter) *FailureHandler {
+	return NewFailureHandler(retry, deadLetter, RetryPolicy{
+		Attempts:        3,
+		Backoff:         time.Millisecond,
+		MaxBackoff:      time.Millisecond,
+		Redeliveries:    2,
+		RedeliveryDelay: time.Millisecond,
+	})
+}
+
+// MockConsumerMetrics records what consumers report
+type MockConsumerMetrics struct {
+	mu        sync.Mutex
+	processed int
+	failed    int
+	lag       map[string]int64
+	fanouts   []int
+}
+
+func (m *MockConsumerMetrics) MessageProcessed(consumer, topic string, ok bool, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.processed++
+	if !ok {
+		m.failed++
+	}
+}
+
+func (m *MockConsumerMetrics) ConsumerLag(consumer, topic string, partition int, lag int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.lag == nil {
+		m.lag = make(map[string]int64)
+	}
+	m.lag[fmt.Sprintf("%s/%d", topic, partition)] = lag
+}
+
+func (m *MockConsumerMetrics) TweetFannedOut(timelines int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fanouts = append(m.fanouts, timelines)
+}
+
+// Processed returns how many attempts were recorded, and how many of them
+// failed
+func (m *MockConsumerMetrics) Processed() (processed, failed int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.processed, m.failed
+}
+
+// Lag returns the last lag recorded for each topic/partition
+func (m *MockConsumerMetrics) Lag() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.lag)
+}
+
+// Fanouts returns the fanout sizes recorded so far
+func (m *MockConsumerMetrics) Fanouts() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int(nil), m.fanouts...)
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AccountPurger deletes the accounts whose deactivation grace period has
+// passed.
+type AccountPurger interface {
+	PurgeDeactivated(ctx context.Context) (purged int, failed int, err error)
+}
+
+// AccountPurgeJob periodically hard deletes deactivated accounts.
+type AccountPurgeJob struct {
+	purger   AccountPurger
+	interval time.Duration
+}
+
+func NewAccountPurgeJob(purger AccountPurger, interval time.Duration) *AccountPurgeJob {
+	return &AccountPurgeJob{
+		purger:   purger,
+		interval: interval,
+	}
+}
+
+// Start runs a purge immediately and then once per interval until ctx is
+// done. It should be run as a goroutine.
+func (j *AccountPurgeJob) Start(ctx context.Context) error {
+	slog.InfoContext(ctx, "Starting account purge job", "interval", j.interval)
+	defer slog.InfoContext(ctx, "Account purge job stopped")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *AccountPurgeJob) run(ctx context.Context) {
+	purged, failed, err := j.purger.PurgeDeactivated(ctx)
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "Error purging deactivated accounts", "error", err)
+	}
+	if purged > 0 || failed > 0 {
+		slog.InfoContext(ctx, "Purged deactivated accounts", "purged", purged, "failed", failed)
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SuggestionRefresher recomputes the follow suggestions of every user.
+type SuggestionRefresher interface {
+	RefreshAll(ctx context.Context) (refreshed int, failed int, err error)
+}
+
+// SuggestionRefreshJob periodically precomputes follow suggestions so that
+// reads are served from the cache.
+type SuggestionRefreshJob struct {
+	refresher SuggestionRefresher
+	interval  time.Duration
+}
+
+func NewSuggestionRefreshJob(refresher SuggestionRefresher, interval time.Duration) *SuggestionRefreshJob {
+	return &SuggestionRefreshJob{
+		refresher: refresher,
+		interval:  interval,
+	}
+}
+
+// Start runs a refresh immediately and then once per interval until ctx is
+// done. It should be run as a goroutine.
+func (j *SuggestionRefreshJob) Start(ctx context.Context) error {
+	slog.InfoContext(ctx, "Starting suggestion refresh job", "interval", j.interval)
+	defer slog.InfoContext(ctx, "Suggestion refresh job stopped")
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *SuggestionRefreshJob) run(ctx context.Context) {
+	start := time.Now()
+	refreshed, failed, err := j.refresher.RefreshAll(ctx)
+	if err != nil && ctx.Err() == nil {
+		slog.ErrorContext(ctx, "Error refreshing suggestions", "error", err)
+	}
+	slog.InfoContext(ctx, "Refreshed suggestions", "users", refreshed, "failed", failed, "duration", time.Since(start))
+}
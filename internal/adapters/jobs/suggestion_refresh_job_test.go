@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingRefresher struct {
+	runs atomic.Int32
+}
+
+func (r *countingRefresher) RefreshAll(ctx context.Context) (int, int, error) {
+	r.runs.Add(1)
+	return 1, 0, nil
+}
+
+func TestSuggestionRefreshJob_RunsUntilCanceled(t *testing.T) {
+	refresher := &countingRefresher{}
+	job := NewSuggestionRefreshJob(refresher, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- job.Start(ctx) }()
+
+	assert.Eventually(t, func() bool { return refresher.runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("job did not stop after context was canceled")
+	}
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+const namespace = "uala_tweets"
+
+// PrometheusMetrics records the metrics of the service in a registry of its
+// own, exposed by Handler along with the Go runtime and process metrics.
+type PrometheusMetrics struct {
+	registry *prometheus.Registry
+
+	httpRequests     *prometheus.HistogramVec
+	consumerMessages *prometheus.HistogramVec
+	consumerFailures *prometheus.CounterVec
+	consumerLag      *prometheus.GaugeVec
+	fanoutTimelines  prometheus.Histogram
+	timelineReads    *prometheus.HistogramVec
+}
+
+func NewPrometheusMetrics() *PrometheusMetrics {
+	m := &PrometheusMetrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to answer HTTP requests, by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		consumerMessages: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "consumer_message_duration_seconds",
+			Help:      "Time taken by attempts at processing a message, failed or not.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"consumer", "topic"}),
+		consumerFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "consumer_message_failures_total",
+			Help:      "Attempts at processing a message that failed.",
+		}, []string{"consumer", "topic"}),
+		consumerLag: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "consumer_lag_messages",
+			Help:      "Messages of a partition left to fetch, as of the last message fetched from it.",
+		}, []string{"consumer", "topic", "partition"}),
+		fanoutTimelines: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "fanout_timelines",
+			Help:      "Timelines, of users and of lists, tweets are fanned out to.",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		}),
+		timelineReads: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "timeline_read_duration_seconds",
+			Help:      "Time taken to read home timelines, by whether the cache held them (hit or miss).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"cache"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.consumerMessages,
+		m.consumerFailures,
+		m.consumerLag,
+		m.fanoutTimelines,
+		m.timelineReads,
+	)
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of db
+func (m *PrometheusMetrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterRedis exposes the connection pool statistics of client
+func (m *PrometheusMetrics) RegisterRedis(client *redis.Client) {
+	m.registry.MustRegister(newRedisPoolCollector(client))
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *PrometheusMetrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *PrometheusMetrics) RequestServed(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+}
+
+func (m *PrometheusMetrics) MessageProcessed(consumer, topic string, ok bool, duration time.Duration) {
+	m.consumerMessages.WithLabelValues(consumer, topic).Observe(duration.Seconds())
+	if !ok {
+		m.consumerFailures.WithLabelValues(consumer, topic).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) ConsumerLag(consumer, topic string, partition int, lag int64) {
+	m.consumerLag.WithLabelValues(consumer, topic, strconv.Itoa(partition)).Set(float64(lag))
+}
+
+func (m *PrometheusMetrics) TweetFannedOut(timelines int) {
+	m.fanoutTimelines.Observe(float64(timelines))
+}
+
+func (m *PrometheusMetrics) TimelineRead(duration time.Duration, hit bool) {
+	cache := "miss"
+	if hit {
+		cache = "hit"
+	}
+	m.timelineReads.WithLabelValues(cache).Observe(duration.Seconds())
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	m := NewPrometheusMetrics()
+	m.RegisterRedis(redis.NewClient(&redis.Options{Addr: "localhost:0"}))
+
+	m.RequestServed(http.MethodGet, "/users/:id", http.StatusOK, 20*time.Millisecond)
+	m.MessageProcessed("tweet-consumer", "tweets.created", true, time.Millisecond)
+	m.MessageProcessed("tweet-consumer", "tweets.created", false, time.Millisecond)
+	m.ConsumerLag("tweet-consumer", "tweets.created", 3, 42)
+	m.TweetFannedOut(150)
+	m.TimelineRead(time.Millisecond, true)
+	m.TimelineRead(time.Millisecond, false)
+	m.TimelineRead(time.Millisecond, false)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.consumerFailures.WithLabelValues("tweet-consumer", "tweets.created")))
+	assert.Equal(t, 42.0, testutil.ToFloat64(m.consumerLag.WithLabelValues("tweet-consumer", "tweets.created", "3")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.timelineReads))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	for _, want := range []string{
+		`uala_tweets_http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 1`,
+		`uala_tweets_consumer_message_duration_seconds_count{consumer="tweet-consumer",topic="tweets.created"} 2`,
+		`uala_tweets_fanout_timelines_sum 150`,
+		`uala_tweets_timeline_read_duration_seconds_count{cache="miss"} 2`,
+		`uala_tweets_redis_pool_connections 0`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, w.Body.String(), want)
+	}
+}
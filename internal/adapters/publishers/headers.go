@@ -0,0 +1,56 @@
+package publishers
+
+import (
+	"context"
+
+	"uala-tweets/internal/adapters/tracing"
+	"uala-tweets/internal/events"
+	"uala-tweets/internal/logging"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("uala-tweets/internal/adapters/publishers")
+
+// contentType tells consumers which codec a message was encoded with
+func contentType(codec events.Codec) []kafka.Header {
+	return []kafka.Header{{Key: events.ContentTypeHeader, Value: []byte(codec.ContentType())}}
+}
+
+// headers returns the headers of a message encoded with codec and published
+// in ctx, carrying its trace and request ID to consumers
+func headers(ctx context.Context, codec events.Codec) []kafka.Header {
+	h := contentType(codec)
+	if id := logging.RequestID(ctx); id != "" {
+		h = append(h, kafka.Header{Key: events.RequestIDHeader, Value: []byte(id)})
+	}
+	tracing.Inject(ctx, &h)
+	return h
+}
+
+// traceContext returns the trace context of ctx for the envelope of an
+// event, which keeps it wherever the event is copied to without its headers
+func traceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// startPublish starts the span of publishing a message to topic
+func startPublish(ctx context.Context, topic string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingOperationTypeSend,
+			semconv.MessagingDestinationName(topic),
+		),
+	)
+}
@@ -0,0 +1,68 @@
+package publishers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+
+	"uala-tweets/internal/adapters/tracing"
+	"uala-tweets/internal/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// HeaderReplayOf carries the ID of the dead letter a replayed message came from
+const HeaderReplayOf = "x-replay-of"
+
+// KafkaDeadLetterReplayer writes dead letters back to the topic they were
+// first published to. Its writer must not have a topic set, as each
+// message names its own.
+type KafkaDeadLetterReplayer struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaDeadLetterReplayer(writer *kafka.Writer) *KafkaDeadLetterReplayer {
+	return &KafkaDeadLetterReplayer{writer: writer}
+}
+
+func (p *KafkaDeadLetterReplayer) Replay(ctx context.Context, deadLetter *domain.DeadLetter) error {
+	ctx, span := startPublish(ctx, deadLetter.Topic)
+	defer span.End()
+
+	// Sorted so replays of the same dead letter are identical
+	keys := make([]string, 0, len(deadLetter.Headers))
+	for key := range deadLetter.Headers {
+		if key != HeaderReplayOf {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	headers := make([]kafka.Header, 0, len(keys)+1)
+	for _, key := range keys {
+		headers = append(headers, kafka.Header{Key: key, Value: []byte(deadLetter.Headers[key])})
+	}
+	headers = append(headers, kafka.Header{Key: HeaderReplayOf, Value: []byte(strconv.FormatInt(deadLetter.ID, 10))})
+	// The replay is followed in the trace of whoever replayed it, rather
+	// than that of the first delivery
+	tracing.Inject(ctx, &headers)
+
+	msg := kafka.Message{
+		Topic:   deadLetter.Topic,
+		Key:     deadLetter.Key,
+		Value:   deadLetter.Payload,
+		Headers: headers,
+	}
+
+	slog.InfoContext(ctx, "Replaying dead letter", "dead_letter_id", deadLetter.ID, "topic", deadLetter.Topic)
+
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "Failed to replay dead letter", "dead_letter_id", deadLetter.ID, "error", err)
+		return fmt.Errorf("failed to replay dead letter %d: %w", deadLetter.ID, err)
+	}
+
+	return nil
+}
@@ -2,45 +2,59 @@ package publishers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 
+	"uala-tweets/internal/adapters/tracing"
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 
 	"github.com/segmentio/kafka-go"
 )
 
 type KafkaFollowPublisher struct {
 	writer *kafka.Writer
+	codec  events.Codec
 }
 
-func NewKafkaFollowPublisher(writer *kafka.Writer) *KafkaFollowPublisher {
+func NewKafkaFollowPublisher(writer *kafka.Writer, codec events.Codec) *KafkaFollowPublisher {
 	return &KafkaFollowPublisher{
 		writer: writer,
+		codec:  codec,
 	}
 }
 
-func (p *KafkaFollowPublisher) PublishFollowEvent(event domain.FollowEvent) error {
-	ctx := context.Background()
-	data, err := json.Marshal(event)
+func (p *KafkaFollowPublisher) PublishFollowEvent(ctx context.Context, event domain.FollowEvent) error {
+	ctx, span := startPublish(ctx, p.writer.Topic)
+	defer span.End()
+
+	envelope, err := events.NewFollow(&event)
+	if err != nil {
+		return err
+	}
+	envelope.TraceContext = traceContext(ctx)
+	data, err := p.codec.Marshal(envelope)
 	if err != nil {
-		log.Printf("Error marshaling follow event: %v", err)
+		slog.ErrorContext(ctx, "Failed to marshal follow event", "published_event_id", envelope.EventID, "error", err)
 		return fmt.Errorf("error marshaling follow event: %w", err)
 	}
 
+	// Keyed by follower so consumers apply a user's follows and unfollows
+	// in the order they were made
 	msg := kafka.Message{
-		Key:   fmt.Appendf(nil, "follow_%d_%d_%v", event.FollowerID, event.FollowedID, event.Following),
-		Value: data,
+		Key:     fmt.Appendf(nil, "follow_%d", event.FollowerID),
+		Value:   data,
+		Headers: headers(ctx, p.codec),
 	}
 
-	log.Printf("Publishing follow event: %+v", event)
+	slog.DebugContext(ctx, "Publishing follow event", "published_event_id", envelope.EventID, "follower_id", event.FollowerID, "followed_id", event.FollowedID, "following", event.Following)
 
 	if err := p.writer.WriteMessages(ctx, msg); err != nil {
-		log.Printf("Error publishing follow event: %v", err)
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "Failed to publish follow event", "published_event_id", envelope.EventID, "error", err)
 		return fmt.Errorf("error publishing follow event: %w", err)
 	}
 
-	log.Printf("Successfully published follow event: %+v", event)
+	slog.DebugContext(ctx, "Published follow event", "published_event_id", envelope.EventID)
 	return nil
 }
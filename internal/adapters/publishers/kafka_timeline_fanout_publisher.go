@@ -2,52 +2,67 @@ package publishers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"uala-tweets/internal/adapters/tracing"
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 
 	"github.com/segmentio/kafka-go"
 )
 
 type KafkaTimelineFanoutPublisher struct {
 	writer *kafka.Writer
+	codec  events.Codec
 }
 
-func NewKafkaTimelineFanoutPublisher(writer *kafka.Writer) *KafkaTimelineFanoutPublisher {
-	return &KafkaTimelineFanoutPublisher{writer: writer}
+func NewKafkaTimelineFanoutPublisher(writer *kafka.Writer, codec events.Codec) *KafkaTimelineFanoutPublisher {
+	return &KafkaTimelineFanoutPublisher{writer: writer, codec: codec}
 }
 
 func (p *KafkaTimelineFanoutPublisher) PublishFanoutEvent(ctx context.Context, event *domain.TimelineFanoutEvent) error {
+	ctx, span := startPublish(ctx, p.writer.Topic)
+	defer span.End()
 
-	data, err := json.Marshal(event)
+	envelope, err := events.NewTimelineFanout(event)
 	if err != nil {
-		log.Printf("Failed to marshal fanout event (TweetID: %d, UserID: %d): %v", event.TweetID, event.UserID, err)
+		return err
+	}
+	envelope.TraceContext = traceContext(ctx)
+	data, err := p.codec.Marshal(envelope)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to marshal fanout event", "published_event_id", envelope.EventID, "tweet_id", event.TweetID, "error", err)
 		return fmt.Errorf("failed to marshal fanout event: %w", err)
 	}
 
+	// Keyed by the timeline the tweet goes to, so consumers update each
+	// timeline in order
+	key := fmt.Appendf(nil, "fanout_%d", event.UserID)
+	if event.ListID != 0 {
+		key = fmt.Appendf(nil, "fanout_list_%d", event.ListID)
+	}
+
 	msg := kafka.Message{
-		Key:   fmt.Appendf(nil, "fanout_%d_%d", event.UserID, event.TweetID),
-		Value: data,
+		Key:     key,
+		Value:   data,
+		Headers: headers(ctx, p.codec),
 	}
 
-	log.Printf("Publishing fanout event - TweetID: %d, UserID: %d, Topic: %s",
-		event.TweetID, event.UserID, p.writer.Topic)
+	slog.DebugContext(ctx, "Publishing fanout event", "published_event_id", envelope.EventID, "tweet_id", event.TweetID, "user_id", event.UserID, "list_id", event.ListID)
 
 	if err := p.writer.WriteMessages(ctx, msg); err != nil {
-		log.Printf("Failed to publish fanout event (TweetID: %d, UserID: %d): %v",
-			event.TweetID, event.UserID, err)
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "Failed to publish fanout event", "published_event_id", envelope.EventID, "tweet_id", event.TweetID, "error", err)
 		return fmt.Errorf("failed to publish fanout event: %w", err)
 	}
 
-	log.Printf("Successfully published fanout event - TweetID: %d, UserID: %d",
-		event.TweetID, event.UserID)
+	slog.DebugContext(ctx, "Published fanout event", "published_event_id", envelope.EventID)
 	return nil
 }
 
 func (p *KafkaTimelineFanoutPublisher) Close() error {
 	if err := p.writer.Close(); err != nil {
-		log.Printf("Error closing Kafka writer for topic %s: %v", p.writer.Topic, err)
+		slog.Error("Failed to close Kafka writer", "topic", p.writer.Topic, "error", err)
 		return fmt.Errorf("error closing kafka writer: %w", err)
 	}
 	return nil
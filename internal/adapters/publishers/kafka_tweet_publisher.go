@@ -2,43 +2,58 @@ package publishers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"uala-tweets/internal/adapters/tracing"
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 
 	"github.com/segmentio/kafka-go"
 )
 
 type KafkaTweetPublisher struct {
 	writer *kafka.Writer
+	codec  events.Codec
 }
 
-func NewKafkaTweetPublisher(writer *kafka.Writer) *KafkaTweetPublisher {
+func NewKafkaTweetPublisher(writer *kafka.Writer, codec events.Codec) *KafkaTweetPublisher {
 	return &KafkaTweetPublisher{
 		writer: writer,
+		codec:  codec,
 	}
 }
 
 func (p *KafkaTweetPublisher) Publish(ctx context.Context, tweet *domain.Tweet) error {
-	data, err := json.Marshal(tweet)
+	ctx, span := startPublish(ctx, p.writer.Topic)
+	defer span.End()
+
+	envelope, err := events.NewTweetCreated(tweet)
+	if err != nil {
+		return err
+	}
+	envelope.TraceContext = traceContext(ctx)
+	data, err := p.codec.Marshal(envelope)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tweet: %w", err)
 	}
 
+	// Keyed by author so an author's tweets are stored in the order they
+	// were written
 	msg := kafka.Message{
-		Key:   fmt.Appendf(nil, "tweet_%d_%d", tweet.UserID, tweet.ID),
-		Value: data,
+		Key:     fmt.Appendf(nil, "tweet_%d", tweet.UserID),
+		Value:   data,
+		Headers: headers(ctx, p.codec),
 	}
 
-	log.Printf("Publishing tweet %d for user %d to topic %s", tweet.ID, tweet.UserID, p.writer.Topic)
+	slog.DebugContext(ctx, "Publishing tweet", "published_event_id", envelope.EventID, "user_id", tweet.UserID, "topic", p.writer.Topic)
 
 	if err := p.writer.WriteMessages(ctx, msg); err != nil {
-		log.Printf("Failed to publish tweet %d: %v", tweet.ID, err)
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "Failed to publish tweet", "published_event_id", envelope.EventID, "error", err)
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
-	log.Printf("Successfully published tweet %d for user %d", tweet.ID, tweet.UserID)
+	slog.DebugContext(ctx, "Published tweet", "published_event_id", envelope.EventID)
 	return nil
 }
 
@@ -2,7 +2,6 @@ package publishers_test
 
 import (
 	"context"
-	"encoding/json"
 	"os"
 	"testing"
 	"time"
@@ -12,6 +11,7 @@ import (
 
 	"uala-tweets/internal/adapters/publishers"
 	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
 )
 
 func TestKafkaTweetPublisher_Publish(t *testing.T) {
@@ -29,7 +29,7 @@ func TestKafkaTweetPublisher_Publish(t *testing.T) {
 		Topic:    topic,
 		Balancer: &kafka.LeastBytes{},
 	}
-	pub := publishers.NewKafkaTweetPublisher(writer)
+	pub := publishers.NewKafkaTweetPublisher(writer, events.JSON)
 	defer pub.Close()
 
 	tweet := &domain.Tweet{
@@ -37,6 +37,7 @@ func TestKafkaTweetPublisher_Publish(t *testing.T) {
 		UserID:    1,
 		Content:   "integration test tweet",
 		CreatedAt: time.Now(),
+		EventID:   domain.NewEventID(),
 	}
 
 	ctx := context.Background()
@@ -65,9 +66,12 @@ func TestKafkaTweetPublisher_Publish(t *testing.T) {
 			if err != nil {
 				continue // keep waiting
 			}
-			var received domain.Tweet
-			unmarshalErr := json.Unmarshal(msg.Value, &received)
-			if unmarshalErr == nil && received.ID == tweet.ID && received.Content == tweet.Content && received.UserID == tweet.UserID {
+			envelope, err := events.Unmarshal(msg.Value)
+			if err != nil || envelope.EventID != tweet.EventID {
+				continue
+			}
+			received, decodeErr := events.DecodeTweetCreated(envelope)
+			if decodeErr == nil && received.Content == tweet.Content && received.UserID == tweet.UserID {
 				found = true
 				assert.WithinDuration(t, tweet.CreatedAt, received.CreatedAt, time.Second)
 			}
@@ -0,0 +1,59 @@
+package publishers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"uala-tweets/internal/adapters/tracing"
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/events"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaUserEventPublisher struct {
+	writer *kafka.Writer
+	codec  events.Codec
+}
+
+func NewKafkaUserEventPublisher(writer *kafka.Writer, codec events.Codec) *KafkaUserEventPublisher {
+	return &KafkaUserEventPublisher{
+		writer: writer,
+		codec:  codec,
+	}
+}
+
+func (p *KafkaUserEventPublisher) PublishUserEvent(ctx context.Context, event domain.UserEvent) error {
+	ctx, span := startPublish(ctx, p.writer.Topic)
+	defer span.End()
+
+	envelope, err := events.NewUserEvent(&event)
+	if err != nil {
+		return err
+	}
+	envelope.TraceContext = traceContext(ctx)
+	data, err := p.codec.Marshal(envelope)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to marshal user event", "published_event_id", envelope.EventID, "error", err)
+		return fmt.Errorf("error marshaling user event: %w", err)
+	}
+
+	// Keyed by user so the events of an account stay in order
+	msg := kafka.Message{
+		Key:     fmt.Appendf(nil, "user_%d", event.UserID),
+		Value:   data,
+		Headers: headers(ctx, p.codec),
+	}
+
+	slog.DebugContext(ctx, "Publishing user event", "published_event_id", envelope.EventID, "type", event.Type, "user_id", event.UserID)
+
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		tracing.RecordError(span, err)
+		slog.ErrorContext(ctx, "Failed to publish user event", "published_event_id", envelope.EventID, "error", err)
+		return fmt.Errorf("error publishing user event: %w", err)
+	}
+
+	slog.DebugContext(ctx, "Published user event", "published_event_id", envelope.EventID)
+	return nil
+}
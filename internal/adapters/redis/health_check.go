@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisHealthCheck checks that Redis answers
+type RedisHealthCheck struct {
+	client *redis.Client
+}
+
+func NewRedisHealthCheck(client *redis.Client) *RedisHealthCheck {
+	return &RedisHealthCheck{client: client}
+}
+
+func (c *RedisHealthCheck) Name() string { return "redis" }
+
+func (c *RedisHealthCheck) Check(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
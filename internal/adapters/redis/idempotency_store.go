@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"uala-tweets/internal/ports/idempotency"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type IdempotencyStoreRedis struct {
+	client *redis.Client
+}
+
+func NewIdempotencyStoreRedis(client *redis.Client) *IdempotencyStoreRedis {
+	return &IdempotencyStoreRedis{client: client}
+}
+
+func idempotencyKey(key string) string {
+	return fmt.Sprintf("idempotency:%s", key)
+}
+
+func (s *IdempotencyStoreRedis) Reserve(ctx context.Context, key, fingerprint string, lease time.Duration) (idempotency.Response, bool, error) {
+	pending, err := json.Marshal(idempotency.Response{Fingerprint: fingerprint})
+	if err != nil {
+		return idempotency.Response{}, false, err
+	}
+
+	reserved, err := s.client.SetNX(ctx, idempotencyKey(key), pending, lease).Result()
+	if err != nil {
+		return idempotency.Response{}, false, err
+	}
+	if reserved {
+		return idempotency.Response{}, true, nil
+	}
+
+	data, err := s.client.Get(ctx, idempotencyKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// Released or expired since; report it as still in progress
+			// rather than racing another retry for it
+			return idempotency.Response{Fingerprint: fingerprint}, false, nil
+		}
+		return idempotency.Response{}, false, err
+	}
+
+	var existing idempotency.Response
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return idempotency.Response{}, false, err
+	}
+	return existing, false, nil
+}
+
+func (s *IdempotencyStoreRedis) Complete(ctx context.Context, key string, response idempotency.Response, ttl time.Duration) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, idempotencyKey(key), data, ttl).Err()
+}
+
+func (s *IdempotencyStoreRedis) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, idempotencyKey(key)).Err()
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type ProcessedEventStoreRedis struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewProcessedEventStoreRedis creates a store that remembers events for
+// ttl, which must be longer than events can take to be redelivered.
+func NewProcessedEventStoreRedis(client *redis.Client, ttl time.Duration) *ProcessedEventStoreRedis {
+	return &ProcessedEventStoreRedis{client: client, ttl: ttl}
+}
+
+func processedEventKey(consumer, eventID string) string {
+	return fmt.Sprintf("processed_events:%s:%s", consumer, eventID)
+}
+
+func (s *ProcessedEventStoreRedis) IsProcessed(ctx context.Context, consumer, eventID string) (bool, error) {
+	n, err := s.client.Exists(ctx, processedEventKey(consumer, eventID)).Result()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (s *ProcessedEventStoreRedis) MarkProcessed(ctx context.Context, consumer, eventID string) error {
+	return s.client.Set(ctx, processedEventKey(consumer, eventID), 1, s.ttl).Err()
+}
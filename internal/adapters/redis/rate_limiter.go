@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"uala-tweets/internal/ports/ratelimit"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript keeps the timestamps of the requests of the current
+// window in a sorted set. It drops those that left the window, then records
+// the request if there is room. It returns whether the request was allowed,
+// the requests left and the timestamp of the oldest request in the window.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	count = count + 1
+	allowed = 1
+end
+
+local oldest = now
+local first = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if first[2] then
+	oldest = tonumber(first[2])
+end
+return {allowed, limit - count, oldest}
+`)
+
+// RateLimiterRedis is a sliding window rate limiter. Unlike fixed windows it
+// does not let a client send twice the limit around a window boundary.
+type RateLimiterRedis struct {
+	client *redis.Client
+	now    func() time.Time
+}
+
+func NewRateLimiterRedis(client *redis.Client) *RateLimiterRedis {
+	return &RateLimiterRedis{client: client, now: time.Now}
+}
+
+func rateLimitKey(key string) string {
+	return fmt.Sprintf("ratelimit:%s", key)
+}
+
+func (r *RateLimiterRedis) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	now := r.now().UnixMilli()
+	window := limit.Window.Milliseconds()
+	// Requests in the same millisecond need distinct members
+	member := fmt.Sprintf("%d-%d", now, rand.Int64())
+
+	values, err := slidingWindowScript.Run(ctx, r.client, []string{rateLimitKey(key)}, now, window, limit.Requests, member).Int64Slice()
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	resetAt := time.UnixMilli(values[2] + window)
+	result := ratelimit.Result{
+		Allowed:   values[0] == 1,
+		Remaining: int(max(values[1], 0)),
+		ResetAt:   resetAt,
+	}
+	if !result.Allowed {
+		result.RetryAfter = resetAt.Sub(time.UnixMilli(now))
+	}
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"uala-tweets/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type SuggestionCacheRedis struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewSuggestionCacheRedis creates a cache whose entries expire after ttl, so
+// suggestions for users the refresh job no longer reaches do not linger.
+func NewSuggestionCacheRedis(client *redis.Client, ttl time.Duration) *SuggestionCacheRedis {
+	return &SuggestionCacheRedis{client: client, ttl: ttl}
+}
+
+func suggestionsKey(userID int) string {
+	return fmt.Sprintf("suggestions:%d", userID)
+}
+
+func (r *SuggestionCacheRedis) SetSuggestions(ctx context.Context, userID int, suggestions []*domain.Suggestion) error {
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, suggestionsKey(userID), data, r.ttl).Err()
+}
+
+func (r *SuggestionCacheRedis) GetSuggestions(ctx context.Context, userID int) ([]*domain.Suggestion, bool, error) {
+	data, err := r.client.Get(ctx, suggestionsKey(userID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	var suggestions []*domain.Suggestion
+	if err := json.Unmarshal(data, &suggestions); err != nil {
+		return nil, false, err
+	}
+	return suggestions, true, nil
+}
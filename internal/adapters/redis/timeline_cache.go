@@ -3,31 +3,103 @@ package redis
 import (
 	"context"
 	"fmt"
+	"slices"
+
+	"uala-tweets/internal/ports/repositories"
 
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	homeTimelinePrefix = "timeline"
+	listTimelinePrefix = "list_timeline"
+
+	// Timelines and tweets removed per round trip by RemoveFromTimelines
+	removeTimelineBatch = 100
+	removeTweetBatch    = 1000
+)
+
 type TimelineCacheRedis struct {
 	client *redis.Client
+	prefix string
 }
 
+// NewTimelineCacheRedis creates the cache of users' home timelines.
 func NewTimelineCacheRedis(client *redis.Client) *TimelineCacheRedis {
-	return &TimelineCacheRedis{client: client}
+	return &TimelineCacheRedis{client: client, prefix: homeTimelinePrefix}
+}
+
+// NewListTimelineCacheRedis creates the cache of list timelines. It is keyed
+// by list ID in its own namespace, so the userID arguments of the
+// TimelineCache methods are list IDs here.
+func NewListTimelineCacheRedis(client *redis.Client) *TimelineCacheRedis {
+	return &TimelineCacheRedis{client: client, prefix: listTimelinePrefix}
 }
 
-func timelineKey(userID int) string {
-	return fmt.Sprintf("timeline:%d", userID)
+func (r *TimelineCacheRedis) timelineKey(id int) string {
+	return fmt.Sprintf("%s:%d", r.prefix, id)
 }
 
-func (r *TimelineCacheRedis) AddToTimeline(userID int, tweetID int64) error {
-	ctx := context.Background()
-	key := timelineKey(userID)
-	return r.client.LPush(ctx, key, tweetID).Err()
+// addIfAbsentScript pushes a tweet onto a timeline unless it is already
+// there, so that adding the same tweet twice leaves a single entry, and
+// trims the timeline to ARGV[2] entries. Trimming bounds the duplicate
+// check as well.
+var addIfAbsentScript = redis.NewScript(`
+local length = tonumber(ARGV[2])
+if redis.call('LPOS', KEYS[1], ARGV[1], 'MAXLEN', length) then
+	return 0
+end
+redis.call('LPUSH', KEYS[1], ARGV[1])
+redis.call('LTRIM', KEYS[1], 0, length - 1)
+return 1
+`)
+
+func (r *TimelineCacheRedis) AddToTimeline(ctx context.Context, userID int, tweetID int64) error {
+	key := r.timelineKey(userID)
+	return addIfAbsentScript.Run(ctx, r.client, []string{key}, tweetID, repositories.TimelineLength).Err()
+}
+
+// mergeScript merges tweets into a timeline newest first, by ID, dropping
+// duplicates and keeping the newest ARGV[1] entries. Running as a script,
+// it never leaves a timeline half rebuilt for readers or fanout to see.
+var mergeScript = redis.NewScript(`
+local size = tonumber(ARGV[1])
+local seen, ids = {}, {}
+local function add(id)
+	if not seen[id] then
+		seen[id] = true
+		ids[#ids + 1] = id
+	end
+end
+for _, id in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	add(id)
+end
+for i = 2, #ARGV do
+	add(ARGV[i])
+end
+table.sort(ids, function(a, b) return tonumber(a) > tonumber(b) end)
+redis.call('DEL', KEYS[1])
+local last = math.min(#ids, size)
+for i = 1, last, 500 do
+	redis.call('RPUSH', KEYS[1], unpack(ids, i, math.min(i + 499, last)))
+end
+return last
+`)
+
+func (r *TimelineCacheRedis) MergeIntoTimeline(ctx context.Context, userID int, tweetIDs []int64, size int) error {
+	if len(tweetIDs) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(tweetIDs)+1)
+	args = append(args, min(size, repositories.TimelineLength))
+	for _, id := range tweetIDs {
+		args = append(args, id)
+	}
+	return mergeScript.Run(ctx, r.client, []string{r.timelineKey(userID)}, args...).Err()
 }
 
-func (r *TimelineCacheRedis) GetTimeline(userID int, limit int) ([]int64, error) {
-	ctx := context.Background()
-	key := timelineKey(userID)
+func (r *TimelineCacheRedis) GetTimeline(ctx context.Context, userID int, limit int) ([]int64, error) {
+	key := r.timelineKey(userID)
 	values, err := r.client.LRange(ctx, key, 0, int64(limit-1)).Result()
 	if err != nil {
 		return nil, err
@@ -44,16 +116,64 @@ func (r *TimelineCacheRedis) GetTimeline(userID int, limit int) ([]int64, error)
 	return result, nil
 }
 
-func (r *TimelineCacheRedis) ClearTimeline(userID int) error {
-	ctx := context.Background()
-	key := timelineKey(userID)
+func (r *TimelineCacheRedis) ClearTimeline(ctx context.Context, userID int) error {
+	key := r.timelineKey(userID)
 	return r.client.Del(ctx, key).Err()
 }
 
-func (r *TimelineCacheRedis) RemoveFromTimeline(userID int, tweetID int64) error {
-	ctx := context.Background()
-	key := timelineKey(userID)
+func (r *TimelineCacheRedis) RemoveFromTimeline(ctx context.Context, userID int, tweetID int64) error {
+	key := r.timelineKey(userID)
 	// Remove all occurrences of the tweetID from the list
 	// Using LREM with count=0 removes all occurrences
 	return r.client.LRem(ctx, key, 0, tweetID).Err()
 }
+
+// removeScript drops the tweets in ARGV from a timeline with one pass over
+// it, instead of an LREM per tweet
+var removeScript = redis.NewScript(`
+local remove = {}
+for i = 1, #ARGV do
+	remove[ARGV[i]] = true
+end
+local kept, removed = {}, 0
+for _, id in ipairs(redis.call('LRANGE', KEYS[1], 0, -1)) do
+	if remove[id] then
+		removed = removed + 1
+	else
+		kept[#kept + 1] = id
+	end
+end
+if removed == 0 then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+for i = 1, #kept, 500 do
+	redis.call('RPUSH', KEYS[1], unpack(kept, i, math.min(i + 499, #kept)))
+end
+return removed
+`)
+
+func (r *TimelineCacheRedis) RemoveFromTimelines(ctx context.Context, userIDs []int, tweetIDs []int64) error {
+	if len(userIDs) == 0 || len(tweetIDs) == 0 {
+		return nil
+	}
+	if err := removeScript.Load(ctx, r.client).Err(); err != nil {
+		return err
+	}
+	for tweets := range slices.Chunk(tweetIDs, removeTweetBatch) {
+		args := make([]any, len(tweets))
+		for i, id := range tweets {
+			args[i] = id
+		}
+		for timelines := range slices.Chunk(userIDs, removeTimelineBatch) {
+			pipe := r.client.Pipeline()
+			for _, id := range timelines {
+				removeScript.EvalSha(ctx, pipe, []string{r.timelineKey(id)}, args...)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLAPIKeyRepository(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	keyRepo := NewPostgreSQLAPIKeyRepository(db)
+
+	userID, otherID := setupTestUsers(t, userRepo)
+
+	key := &domain.APIKey{
+		UserID:  userID,
+		Name:    "scheduler",
+		Prefix:  "uala_abc",
+		KeyHash: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+		Scopes:  []domain.APIKeyScope{domain.APIKeyScopeTweetsWrite, domain.APIKeyScopeTimelineRead},
+	}
+	require.NoError(t, keyRepo.Create(context.Background(), key))
+	assert.NotZero(t, key.ID)
+
+	found, err := keyRepo.GetByHash(context.Background(), key.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, key.Scopes, found.Scopes)
+	assert.Nil(t, found.LastUsedAt)
+
+	_, err = keyRepo.GetByHash(context.Background(), "unknown")
+	assert.IsType(t, &application.ErrUnauthorized{}, err)
+
+	require.NoError(t, keyRepo.SetLastUsedAt(context.Background(), key.ID, time.Now().UTC()))
+	keys, err := keyRepo.GetByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].LastUsedAt)
+
+	// Keys can only be revoked by their owner
+	err = keyRepo.Revoke(context.Background(), otherID, key.ID)
+	assert.IsType(t, &application.ErrAPIKeyNotFound{}, err)
+
+	require.NoError(t, keyRepo.Revoke(context.Background(), userID, key.ID))
+	err = keyRepo.Revoke(context.Background(), userID, key.ID)
+	assert.IsType(t, &application.ErrAPIKeyNotFound{}, err)
+
+	keys, err = keyRepo.GetByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	found, err = keyRepo.GetByHash(context.Background(), key.KeyHash)
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+}
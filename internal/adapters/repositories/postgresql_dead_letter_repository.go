@@ -0,0 +1,237 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+type PostgreSQLDeadLetterRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLDeadLetterRepository(db *sql.DB) *PostgreSQLDeadLetterRepository {
+	return &PostgreSQLDeadLetterRepository{db: db}
+}
+
+const deadLetterColumns = `
+	d.id, d.consumer, d.original_topic, d.original_partition, d.original_offset,
+	d.message_key, d.payload, d.headers, d.error, d.attempts, d.failed_at,
+	d.dead_letter_topic, d.dead_letter_partition, d.dead_letter_offset, d.created_at,
+	COUNT(r.id), MAX(r.replayed_at)`
+
+const deadLetterFrom = `
+	FROM dead_letters d
+	LEFT JOIN dead_letter_replays r ON r.dead_letter_id = d.id`
+
+func (r *PostgreSQLDeadLetterRepository) Create(ctx context.Context, deadLetter *domain.DeadLetter) error {
+	query := `
+		INSERT INTO dead_letters (
+			consumer, original_topic, original_partition, original_offset,
+			message_key, payload, headers, error, attempts, failed_at,
+			dead_letter_topic, dead_letter_partition, dead_letter_offset, created_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (dead_letter_topic, dead_letter_partition, dead_letter_offset) DO NOTHING
+		RETURNING id, created_at
+	`
+
+	headers, err := json.Marshal(deadLetter.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter headers: %w", err)
+	}
+	if deadLetter.Headers == nil {
+		headers = []byte("{}")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		deadLetter.Consumer,
+		deadLetter.Topic,
+		deadLetter.Partition,
+		deadLetter.Offset,
+		deadLetter.Key,
+		deadLetter.Payload,
+		headers,
+		deadLetter.Error,
+		deadLetter.Attempts,
+		deadLetter.FailedAt,
+		deadLetter.DeadLetterTopic,
+		deadLetter.DeadLetterPartition,
+		deadLetter.DeadLetterOffset,
+		time.Now().UTC(),
+	).Scan(&deadLetter.ID, &deadLetter.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Archived before
+		return nil
+	}
+	return err
+}
+
+func (r *PostgreSQLDeadLetterRepository) GetByID(ctx context.Context, id int64) (*domain.DeadLetter, error) {
+	query := `SELECT ` + deadLetterColumns + deadLetterFrom + ` WHERE d.id = $1 GROUP BY d.id`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	deadLetter, err := scanDeadLetter(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, application.NewErrDeadLetterNotFound(id)
+		}
+		return nil, err
+	}
+
+	return deadLetter, nil
+}
+
+func (r *PostgreSQLDeadLetterRepository) List(ctx context.Context, filter domain.DeadLetterFilter) ([]*domain.DeadLetter, error) {
+	var conditions []string
+	var args []any
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Consumer != "" {
+		addCondition("d.consumer = $%d", filter.Consumer)
+	}
+	if filter.Topic != "" {
+		addCondition("d.original_topic = $%d", filter.Topic)
+	}
+	if !filter.From.IsZero() {
+		addCondition("d.failed_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		addCondition("d.failed_at < $%d", filter.To)
+	}
+
+	query := `SELECT ` + deadLetterColumns + deadLetterFrom
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	// A limit of zero lists every match; LIMIT NULL is no limit
+	var limit any
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(` GROUP BY d.id ORDER BY d.failed_at DESC, d.id DESC LIMIT $%d`, len(args))
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters := make([]*domain.DeadLetter, 0)
+	for rows.Next() {
+		deadLetter, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deadLetters, nil
+}
+
+func (r *PostgreSQLDeadLetterRepository) RecordReplay(ctx context.Context, replay *domain.DeadLetterReplay) error {
+	query := `
+		INSERT INTO dead_letter_replays (dead_letter_id, replayed_by, replayed_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, replayed_at
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.db.QueryRowContext(
+		ctx,
+		query,
+		replay.DeadLetterID,
+		replay.ReplayedBy,
+		time.Now().UTC(),
+	).Scan(&replay.ID, &replay.ReplayedAt)
+}
+
+func (r *PostgreSQLDeadLetterRepository) GetReplays(ctx context.Context, deadLetterID int64) ([]*domain.DeadLetterReplay, error) {
+	query := `
+		SELECT id, dead_letter_id, replayed_by, replayed_at
+		FROM dead_letter_replays
+		WHERE dead_letter_id = $1
+		ORDER BY replayed_at, id
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, deadLetterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	replays := make([]*domain.DeadLetterReplay, 0)
+	for rows.Next() {
+		var replay domain.DeadLetterReplay
+		if err := rows.Scan(&replay.ID, &replay.DeadLetterID, &replay.ReplayedBy, &replay.ReplayedAt); err != nil {
+			return nil, err
+		}
+		replays = append(replays, &replay)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return replays, nil
+}
+
+func scanDeadLetter(row interface{ Scan(...any) error }) (*domain.DeadLetter, error) {
+	var deadLetter domain.DeadLetter
+	var headers []byte
+	var lastReplayedAt sql.NullTime
+	err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.Consumer,
+		&deadLetter.Topic,
+		&deadLetter.Partition,
+		&deadLetter.Offset,
+		&deadLetter.Key,
+		&deadLetter.Payload,
+		&headers,
+		&deadLetter.Error,
+		&deadLetter.Attempts,
+		&deadLetter.FailedAt,
+		&deadLetter.DeadLetterTopic,
+		&deadLetter.DeadLetterPartition,
+		&deadLetter.DeadLetterOffset,
+		&deadLetter.CreatedAt,
+		&deadLetter.ReplayCount,
+		&lastReplayedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(headers, &deadLetter.Headers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead letter headers: %w", err)
+	}
+	if lastReplayedAt.Valid {
+		deadLetter.LastReplayedAt = &lastReplayedAt.Time
+	}
+	return &deadLetter, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLDeadLetterRepository(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLDeadLetterRepository(db)
+	failedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+
+	tweetEvent := &domain.DeadLetter{
+		Consumer:            "tweet-consumer",
+		Topic:               "tweets.created",
+		Partition:           1,
+		Offset:              10,
+		Key:                 []byte("tweet_42"),
+		Payload:             []byte(`{"user_id":42}`),
+		Headers:             map[string]string{"x-request-id": "abc"},
+		Error:               "connection refused",
+		Attempts:            4,
+		FailedAt:            failedAt,
+		DeadLetterTopic:     "tweets.created.dlq",
+		DeadLetterPartition: 0,
+		DeadLetterOffset:    3,
+	}
+	require.NoError(t, repo.Create(context.Background(), tweetEvent))
+	assert.NotZero(t, tweetEvent.ID)
+
+	// Archiving the same dead-letter message again is a no-op
+	require.NoError(t, repo.Create(context.Background(), &domain.DeadLetter{
+		Consumer: "tweet-consumer", Topic: "tweets.created", Payload: []byte("{}"), FailedAt: failedAt,
+		DeadLetterTopic: "tweets.created.dlq", DeadLetterPartition: 0, DeadLetterOffset: 3,
+	}))
+
+	fanoutEvent := &domain.DeadLetter{
+		Consumer:         "timeline-fanout-consumer",
+		Topic:            "timeline.fanout",
+		Payload:          []byte("not json"),
+		Error:            "invalid character",
+		Attempts:         1,
+		FailedAt:         failedAt.Add(30 * time.Minute),
+		DeadLetterTopic:  "timeline.fanout.dlq",
+		DeadLetterOffset: 0,
+	}
+	require.NoError(t, repo.Create(context.Background(), fanoutEvent))
+
+	all, err := repo.List(context.Background(), domain.DeadLetterFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, fanoutEvent.ID, all[0].ID, "most recent failure first")
+
+	byTopic, err := repo.List(context.Background(), domain.DeadLetterFilter{Topic: "tweets.created", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, byTopic, 1)
+	assert.Equal(t, tweetEvent.ID, byTopic[0].ID)
+
+	inRange, err := repo.List(context.Background(), domain.DeadLetterFilter{From: failedAt, To: failedAt.Add(time.Minute), Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, inRange, 1)
+	assert.Equal(t, tweetEvent.ID, inRange[0].ID)
+
+	replay := &domain.DeadLetterReplay{DeadLetterID: tweetEvent.ID, ReplayedBy: "user:1"}
+	require.NoError(t, repo.RecordReplay(context.Background(), replay))
+	assert.NotZero(t, replay.ID)
+
+	found, err := repo.GetByID(context.Background(), tweetEvent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("tweet_42"), found.Key)
+	assert.Equal(t, []byte(`{"user_id":42}`), found.Payload)
+	assert.Equal(t, "abc", found.Headers["x-request-id"])
+	assert.Equal(t, 4, found.Attempts)
+	assert.Equal(t, 1, found.ReplayCount)
+	assert.NotNil(t, found.LastReplayedAt)
+
+	replays, err := repo.GetReplays(context.Background(), tweetEvent.ID)
+	require.NoError(t, err)
+	require.Len(t, replays, 1)
+	assert.Equal(t, "user:1", replays[0].ReplayedBy)
+
+	_, err = repo.GetByID(context.Background(), 9999)
+	assert.IsType(t, &application.ErrDeadLetterNotFound{}, err)
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"time"
+
+	"uala-tweets/internal/domain"
 )
 
 type PostgreSQLFollowRepository struct {
@@ -15,7 +17,7 @@ func NewPostgreSQLFollowRepository(db *sql.DB) *PostgreSQLFollowRepository {
 	return &PostgreSQLFollowRepository{db: db}
 }
 
-func (r *PostgreSQLFollowRepository) Follow(followerID, followedID int) error {
+func (r *PostgreSQLFollowRepository) Follow(ctx context.Context, followerID, followedID int) error {
 	if followerID == followedID {
 		return errors.New("cannot follow yourself")
 	}
@@ -25,7 +27,7 @@ func (r *PostgreSQLFollowRepository) Follow(followerID, followedID int) error {
 		VALUES ($1, $2, $3)
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	_, err := r.db.ExecContext(
@@ -47,13 +49,13 @@ func (r *PostgreSQLFollowRepository) Follow(followerID, followedID int) error {
 	return nil
 }
 
-func (r *PostgreSQLFollowRepository) Unfollow(followerID, followedID int) error {
+func (r *PostgreSQLFollowRepository) Unfollow(ctx context.Context, followerID, followedID int) error {
 	query := `
 		DELETE FROM follows
 		WHERE follower_id = $1 AND followed_id = $2
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	result, err := r.db.ExecContext(ctx, query, followerID, followedID)
@@ -73,9 +75,9 @@ func (r *PostgreSQLFollowRepository) Unfollow(followerID, followedID int) error
 	return nil
 }
 
-func (r *PostgreSQLFollowRepository) GetFollowers(userID int) ([]int, error) {
+func (r *PostgreSQLFollowRepository) GetFollowers(ctx context.Context, userID int) ([]int, error) {
 	query := `SELECT follower_id FROM follows WHERE followed_id = $1`
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
@@ -96,7 +98,30 @@ func (r *PostgreSQLFollowRepository) GetFollowers(userID int) ([]int, error) {
 	return followers, nil
 }
 
-func (r *PostgreSQLFollowRepository) IsFollowing(followerID, followedID int) (bool, error) {
+func (r *PostgreSQLFollowRepository) GetFollowing(ctx context.Context, userID int) ([]int, error) {
+	query := `SELECT followed_id FROM follows WHERE follower_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var following []int
+	for rows.Next() {
+		var followedID int
+		if err := rows.Scan(&followedID); err != nil {
+			return nil, err
+		}
+		following = append(following, followedID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return following, nil
+}
+
+func (r *PostgreSQLFollowRepository) IsFollowing(ctx context.Context, followerID, followedID int) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM follows 
@@ -104,7 +129,7 @@ func (r *PostgreSQLFollowRepository) IsFollowing(followerID, followedID int) (bo
 		)
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var exists bool
@@ -115,3 +140,49 @@ func (r *PostgreSQLFollowRepository) IsFollowing(followerID, followedID int) (bo
 
 	return exists, nil
 }
+
+// GetFriendsOfFriends ranks the accounts followed by the accounts userID
+// follows, scored by how many of them follow each candidate. Accounts the
+// user already follows or has a pending request to are left out.
+func (r *PostgreSQLFollowRepository) GetFriendsOfFriends(ctx context.Context, userID int, limit int) ([]*domain.Suggestion, error) {
+	query := `
+		SELECT f2.followed_id, COUNT(*) AS score
+		FROM follows f1
+		JOIN follows f2 ON f2.follower_id = f1.followed_id
+		WHERE f1.follower_id = $1
+		  AND f2.followed_id != $1
+		  AND NOT EXISTS (
+			SELECT 1 FROM follows f
+			WHERE f.follower_id = $1 AND f.followed_id = f2.followed_id
+		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM follow_requests fr
+			WHERE fr.requester_id = $1 AND fr.target_id = f2.followed_id
+		  )
+		GROUP BY f2.followed_id
+		ORDER BY score DESC, f2.followed_id
+		LIMIT $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]*domain.Suggestion, 0)
+	for rows.Next() {
+		var s domain.Suggestion
+		if err := rows.Scan(&s.UserID, &s.Score); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ func setupTestUsers(t *testing.T, repo *PostgreSQLUserRepository) (int, int) {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
-	err := repo.Create(user1)
+	err := repo.Create(context.Background(), user1)
 	require.NoError(t, err)
 
 	user2 := &domain.User{
@@ -25,7 +26,7 @@ func setupTestUsers(t *testing.T, repo *PostgreSQLUserRepository) (int, int) {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
-	err = repo.Create(user2)
+	err = repo.Create(context.Background(), user2)
 	require.NoError(t, err)
 
 	return user1.ID, user2.ID
@@ -55,7 +56,7 @@ func TestPostgreSQLFollowRepository_Follow(t *testing.T) {
 			followedID: followedID,
 			expectErr:  false,
 			expectFunc: func(t *testing.T, err error) {
-				isFollowing, err := followRepo.IsFollowing(followerID, followedID)
+				isFollowing, err := followRepo.IsFollowing(context.Background(), followerID, followedID)
 				require.NoError(t, err)
 				assert.True(t, isFollowing)
 			},
@@ -64,7 +65,7 @@ func TestPostgreSQLFollowRepository_Follow(t *testing.T) {
 		// 	name: "duplicate follow",
 		// 	setup: func() error {
 		// 		// First follow should succeed
-		// 		return followRepo.Follow(followerID, followedID)
+		// 		return followRepo.Follow(context.Background(), followerID, followedID)
 		// 	},
 		// 	followerID: followerID,
 		// 	followedID: followedID,
@@ -84,7 +85,7 @@ func TestPostgreSQLFollowRepository_Follow(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			err := followRepo.Follow(tt.followerID, tt.followedID)
+			err := followRepo.Follow(context.Background(), tt.followerID, tt.followedID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -118,12 +119,12 @@ func TestPostgreSQLFollowRepository_Unfollow(t *testing.T) {
 	}{
 		{
 			name:       "successful unfollow",
-			setup:      func() error { return followRepo.Follow(followerID, followedID) },
+			setup:      func() error { return followRepo.Follow(context.Background(), followerID, followedID) },
 			followerID: followerID,
 			followedID: followedID,
 			expectErr:  false,
 			expectFunc: func(t *testing.T, err error) {
-				isFollowing, err := followRepo.IsFollowing(followerID, followedID)
+				isFollowing, err := followRepo.IsFollowing(context.Background(), followerID, followedID)
 				require.NoError(t, err)
 				assert.False(t, isFollowing)
 			},
@@ -145,7 +146,7 @@ func TestPostgreSQLFollowRepository_Unfollow(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			err := followRepo.Unfollow(tt.followerID, tt.followedID)
+			err := followRepo.Unfollow(context.Background(), tt.followerID, tt.followedID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -187,7 +188,7 @@ func TestPostgreSQLFollowRepository_IsFollowing(t *testing.T) {
 		},
 		{
 			name:       "is following",
-			setup:      func() error { return followRepo.Follow(followerID, followedID) },
+			setup:      func() error { return followRepo.Follow(context.Background(), followerID, followedID) },
 			followerID: followerID,
 			followedID: followedID,
 			expected:   true,
@@ -202,7 +203,7 @@ func TestPostgreSQLFollowRepository_IsFollowing(t *testing.T) {
 				require.NoError(t, err)
 			}
 
-			result, err := followRepo.IsFollowing(tt.followerID, tt.followedID)
+			result, err := followRepo.IsFollowing(context.Background(), tt.followerID, tt.followedID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -213,3 +214,46 @@ func TestPostgreSQLFollowRepository_IsFollowing(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgreSQLFollowRepository_GetFriendsOfFriends(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	followRepo := NewPostgreSQLFollowRepository(db)
+	requestRepo := NewPostgreSQLFollowRequestRepository(db)
+
+	ids := make(map[string]int)
+	for _, name := range []string{"alice", "bob", "carol", "dave", "erin", "frank"} {
+		user := &domain.User{Username: name, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+		require.NoError(t, userRepo.Create(context.Background(), user))
+		ids[name] = user.ID
+	}
+
+	follow := func(follower, followed string) {
+		require.NoError(t, followRepo.Follow(context.Background(), ids[follower], ids[followed]))
+	}
+	// alice follows bob and carol
+	follow("alice", "bob")
+	follow("alice", "carol")
+	// dave is followed by both, erin by one, and alice is followed back
+	follow("bob", "dave")
+	follow("carol", "dave")
+	follow("bob", "erin")
+	follow("bob", "alice")
+	// carol is already followed by alice, so she is not suggested
+	follow("bob", "carol")
+	// frank is followed by carol but alice already asked to follow him
+	follow("carol", "frank")
+	require.NoError(t, requestRepo.Create(context.Background(), ids["alice"], ids["frank"]))
+
+	suggestions, err := followRepo.GetFriendsOfFriends(context.Background(), ids["alice"], 10)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, domain.Suggestion{UserID: ids["dave"], Score: 2}, *suggestions[0])
+	assert.Equal(t, domain.Suggestion{UserID: ids["erin"], Score: 1}, *suggestions[1])
+
+	suggestions, err = followRepo.GetFriendsOfFriends(context.Background(), ids["alice"], 1)
+	require.NoError(t, err)
+	assert.Len(t, suggestions, 1)
+}
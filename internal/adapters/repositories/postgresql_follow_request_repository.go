@@ -0,0 +1,157 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+type PostgreSQLFollowRequestRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLFollowRequestRepository(db *sql.DB) *PostgreSQLFollowRequestRepository {
+	return &PostgreSQLFollowRequestRepository{db: db}
+}
+
+func (r *PostgreSQLFollowRequestRepository) Create(ctx context.Context, requesterID, targetID int) error {
+	if requesterID == targetID {
+		return errors.New("cannot request to follow yourself")
+	}
+
+	query := `
+		INSERT INTO follow_requests (requester_id, target_id, created_at)
+		VALUES ($1, $2, $3)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, requesterID, targetID, time.Now().UTC())
+	if err != nil {
+		if err.Error() == "pq: duplicate key value violates unique constraint \"follow_requests_pkey\"" {
+			return application.NewErrFollowRequestAlreadyExists(requesterID, targetID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLFollowRequestRepository) Delete(ctx context.Context, requesterID, targetID int) error {
+	query := `
+		DELETE FROM follow_requests
+		WHERE requester_id = $1 AND target_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrFollowRequestNotFound(requesterID, targetID)
+	}
+
+	return nil
+}
+
+// Approve deletes the request and inserts the follow in one transaction, so
+// a failure leaves the request pending to be approved again
+func (r *PostgreSQLFollowRequestRepository) Approve(ctx context.Context, requesterID, targetID int) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM follow_requests WHERE requester_id = $1 AND target_id = $2`, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return application.NewErrFollowRequestNotFound(requesterID, targetID)
+	}
+
+	query := `
+		INSERT INTO follows (follower_id, followed_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (follower_id, followed_id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, requesterID, targetID, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgreSQLFollowRequestRepository) Exists(ctx context.Context, requesterID, targetID int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM follow_requests
+			WHERE requester_id = $1 AND target_id = $2
+		)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, requesterID, targetID).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+func (r *PostgreSQLFollowRequestRepository) GetByTargetID(ctx context.Context, targetID int) ([]*domain.FollowRequest, error) {
+	query := `
+		SELECT requester_id, target_id, created_at
+		FROM follow_requests
+		WHERE target_id = $1
+		ORDER BY created_at ASC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, targetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := make([]*domain.FollowRequest, 0)
+	for rows.Next() {
+		request := &domain.FollowRequest{}
+		if err := rows.Scan(&request.RequesterID, &request.TargetID, &request.CreatedAt); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"uala-tweets/internal/application"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLFollowRequestRepository(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	requestRepo := NewPostgreSQLFollowRequestRepository(db)
+
+	requesterID, targetID := setupTestUsers(t, userRepo)
+
+	require.NoError(t, requestRepo.Create(context.Background(), requesterID, targetID))
+
+	err := requestRepo.Create(context.Background(), requesterID, targetID)
+	assert.IsType(t, &application.ErrFollowRequestAlreadyExists{}, err)
+
+	exists, err := requestRepo.Exists(context.Background(), requesterID, targetID)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	requests, err := requestRepo.GetByTargetID(context.Background(), targetID)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, requesterID, requests[0].RequesterID)
+
+	require.NoError(t, requestRepo.Delete(context.Background(), requesterID, targetID))
+
+	err = requestRepo.Delete(context.Background(), requesterID, targetID)
+	assert.IsType(t, &application.ErrFollowRequestNotFound{}, err)
+
+	exists, err = requestRepo.Exists(context.Background(), requesterID, targetID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestPostgreSQLFollowRequestRepository_Approve(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	followRepo := NewPostgreSQLFollowRepository(db)
+	requestRepo := NewPostgreSQLFollowRequestRepository(db)
+
+	requesterID, targetID := setupTestUsers(t, userRepo)
+
+	err := requestRepo.Approve(context.Background(), requesterID, targetID)
+	assert.IsType(t, &application.ErrFollowRequestNotFound{}, err)
+
+	// A follow left behind by an earlier approval does not block this one
+	require.NoError(t, followRepo.Follow(context.Background(), requesterID, targetID))
+	require.NoError(t, requestRepo.Create(context.Background(), requesterID, targetID))
+	require.NoError(t, requestRepo.Approve(context.Background(), requesterID, targetID))
+
+	following, err := followRepo.IsFollowing(context.Background(), requesterID, targetID)
+	require.NoError(t, err)
+	assert.True(t, following)
+
+	exists, err := requestRepo.Exists(context.Background(), requesterID, targetID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// PostgreSQLHealthCheck checks that PostgreSQL answers
+type PostgreSQLHealthCheck struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLHealthCheck(db *sql.DB) *PostgreSQLHealthCheck {
+	return &PostgreSQLHealthCheck{db: db}
+}
+
+func (c *PostgreSQLHealthCheck) Name() string { return "postgres" }
+
+func (c *PostgreSQLHealthCheck) Check(ctx context.Context) error {
+	return c.db.PingContext(ctx)
+}
@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+type PostgreSQLListRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLListRepository(db *sql.DB) *PostgreSQLListRepository {
+	return &PostgreSQLListRepository{db: db}
+}
+
+func (r *PostgreSQLListRepository) Create(ctx context.Context, list *domain.List) error {
+	query := `
+		INSERT INTO lists (owner_id, name, description, is_private, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return r.db.QueryRowContext(
+		ctx,
+		query,
+		list.OwnerID,
+		list.Name,
+		list.Description,
+		list.Private,
+		time.Now().UTC(),
+	).Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt)
+}
+
+func (r *PostgreSQLListRepository) GetByID(ctx context.Context, id int) (*domain.List, error) {
+	query := `
+		SELECT id, owner_id, name, description, is_private, created_at, updated_at
+		FROM lists
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var list domain.List
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&list.ID,
+		&list.OwnerID,
+		&list.Name,
+		&list.Description,
+		&list.Private,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, application.NewErrListNotFound(id)
+		}
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+func (r *PostgreSQLListRepository) GetByOwnerID(ctx context.Context, ownerID int) ([]*domain.List, error) {
+	query := `
+		SELECT id, owner_id, name, description, is_private, created_at, updated_at
+		FROM lists
+		WHERE owner_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := make([]*domain.List, 0)
+	for rows.Next() {
+		var list domain.List
+		if err := rows.Scan(
+			&list.ID,
+			&list.OwnerID,
+			&list.Name,
+			&list.Description,
+			&list.Private,
+			&list.CreatedAt,
+			&list.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		lists = append(lists, &list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (r *PostgreSQLListRepository) Update(ctx context.Context, list *domain.List) error {
+	query := `
+		UPDATE lists
+		SET name = $2, description = $3, is_private = $4, updated_at = $5
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		list.ID,
+		list.Name,
+		list.Description,
+		list.Private,
+		time.Now().UTC(),
+	).Scan(&list.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return application.NewErrListNotFound(list.ID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLListRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM lists WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrListNotFound(id)
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLListRepository) AddMember(ctx context.Context, listID, userID int) error {
+	query := `
+		INSERT INTO list_members (list_id, user_id, created_at)
+		VALUES ($1, $2, $3)
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, listID, userID, time.Now().UTC())
+	if err != nil {
+		if err.Error() == "pq: duplicate key value violates unique constraint \"list_members_pkey\"" {
+			return application.NewErrAlreadyListMember(listID, userID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLListRepository) RemoveMember(ctx context.Context, listID, userID int) error {
+	query := `
+		DELETE FROM list_members
+		WHERE list_id = $1 AND user_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, listID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrNotListMember(listID, userID)
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLListRepository) GetMembers(ctx context.Context, listID int) ([]int, error) {
+	query := `
+		SELECT user_id
+		FROM list_members
+		WHERE list_id = $1
+		ORDER BY created_at, user_id
+	`
+	return r.queryIDs(ctx, query, listID)
+}
+
+func (r *PostgreSQLListRepository) GetListIDsByMember(ctx context.Context, userID int) ([]int, error) {
+	query := `SELECT list_id FROM list_members WHERE user_id = $1`
+	return r.queryIDs(ctx, query, userID)
+}
+
+func (r *PostgreSQLListRepository) queryIDs(ctx context.Context, query string, arg int) ([]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]int, 0)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLListRepository_CRUD(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	listRepo := NewPostgreSQLListRepository(db)
+
+	ownerID, _ := setupTestUsers(t, userRepo)
+
+	list := &domain.List{OwnerID: ownerID, Name: "Go devs", Description: "People writing Go", Private: true}
+	require.NoError(t, listRepo.Create(context.Background(), list))
+	assert.NotZero(t, list.ID)
+
+	found, err := listRepo.GetByID(context.Background(), list.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Go devs", found.Name)
+	assert.True(t, found.Private)
+
+	found.Name = "Gophers"
+	found.Private = false
+	require.NoError(t, listRepo.Update(context.Background(), found))
+
+	lists, err := listRepo.GetByOwnerID(context.Background(), ownerID)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	assert.Equal(t, "Gophers", lists[0].Name)
+	assert.False(t, lists[0].Private)
+
+	require.NoError(t, listRepo.Delete(context.Background(), list.ID))
+
+	_, err = listRepo.GetByID(context.Background(), list.ID)
+	assert.IsType(t, &application.ErrListNotFound{}, err)
+
+	err = listRepo.Delete(context.Background(), list.ID)
+	assert.IsType(t, &application.ErrListNotFound{}, err)
+}
+
+func TestPostgreSQLListRepository_Members(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	listRepo := NewPostgreSQLListRepository(db)
+
+	ownerID, memberID := setupTestUsers(t, userRepo)
+
+	list := &domain.List{OwnerID: ownerID, Name: "Friends"}
+	require.NoError(t, listRepo.Create(context.Background(), list))
+
+	require.NoError(t, listRepo.AddMember(context.Background(), list.ID, memberID))
+
+	err := listRepo.AddMember(context.Background(), list.ID, memberID)
+	assert.IsType(t, &application.ErrAlreadyListMember{}, err)
+
+	members, err := listRepo.GetMembers(context.Background(), list.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{memberID}, members)
+
+	listIDs, err := listRepo.GetListIDsByMember(context.Background(), memberID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{list.ID}, listIDs)
+
+	require.NoError(t, listRepo.RemoveMember(context.Background(), list.ID, memberID))
+
+	err = listRepo.RemoveMember(context.Background(), list.ID, memberID)
+	assert.IsType(t, &application.ErrNotListMember{}, err)
+
+	listIDs, err = listRepo.GetListIDsByMember(context.Background(), memberID)
+	require.NoError(t, err)
+	assert.Empty(t, listIDs)
+}
@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the PostgreSQL error code of a duplicate key
+const uniqueViolation = "23505"
+
+type PostgreSQLMuteRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLMuteRepository(db *sql.DB) *PostgreSQLMuteRepository {
+	return &PostgreSQLMuteRepository{db: db}
+}
+
+func (r *PostgreSQLMuteRepository) Create(ctx context.Context, mute *domain.Mute) error {
+	query := `
+		INSERT INTO mutes (user_id, mute_type, muted_user_id, keyword, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var mutedUserID sql.NullInt64
+	var keyword sql.NullString
+	switch mute.Type {
+	case domain.MuteTypeUser:
+		mutedUserID = sql.NullInt64{Int64: int64(mute.MutedUserID), Valid: true}
+	case domain.MuteTypeKeyword:
+		keyword = sql.NullString{String: mute.Keyword, Valid: true}
+	}
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		mute.UserID,
+		string(mute.Type),
+		mutedUserID,
+		keyword,
+		mute.ExpiresAt,
+		time.Now().UTC(),
+	).Scan(&mute.ID, &mute.CreatedAt)
+
+	// The unique indexes allow one mute per user and target
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return application.NewErrAlreadyMuted(mute.UserID, mute.Target())
+	}
+	return err
+}
+
+func (r *PostgreSQLMuteRepository) UpdateExpiry(ctx context.Context, userID int, muteID int64, expiresAt *time.Time) error {
+	query := `
+		UPDATE mutes
+		SET expires_at = $3
+		WHERE id = $1 AND user_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, muteID, userID, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrMuteNotFound(muteID)
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLMuteRepository) Delete(ctx context.Context, userID int, muteID int64) error {
+	query := `
+		DELETE FROM mutes
+		WHERE id = $1 AND user_id = $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, muteID, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrMuteNotFound(muteID)
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLMuteRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Mute, error) {
+	query := `
+		SELECT id, user_id, mute_type, muted_user_id, keyword, expires_at, created_at
+		FROM mutes
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	mutes := make([]*domain.Mute, 0)
+	for rows.Next() {
+		var (
+			mute        domain.Mute
+			muteType    string
+			mutedUserID sql.NullInt64
+			keyword     sql.NullString
+			expiresAt   sql.NullTime
+		)
+		if err := rows.Scan(
+			&mute.ID,
+			&mute.UserID,
+			&muteType,
+			&mutedUserID,
+			&keyword,
+			&expiresAt,
+			&mute.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		mute.Type = domain.MuteType(muteType)
+		mute.MutedUserID = int(mutedUserID.Int64)
+		mute.Keyword = keyword.String
+		if expiresAt.Valid {
+			mute.ExpiresAt = &expiresAt.Time
+		}
+		mutes = append(mutes, &mute)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mutes, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLMuteRepository_CreateAndList(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	muteRepo := NewPostgreSQLMuteRepository(db)
+
+	userID, mutedUserID := setupTestUsers(t, userRepo)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Microsecond)
+
+	userMute := &domain.Mute{UserID: userID, Type: domain.MuteTypeUser, MutedUserID: mutedUserID}
+	require.NoError(t, muteRepo.Create(context.Background(), userMute))
+	assert.NotZero(t, userMute.ID)
+
+	keywordMute := &domain.Mute{UserID: userID, Type: domain.MuteTypeKeyword, Keyword: "#spoilers", ExpiresAt: &expiresAt}
+	require.NoError(t, muteRepo.Create(context.Background(), keywordMute))
+
+	// Muting the same keyword twice violates the unique index
+	duplicate := &domain.Mute{UserID: userID, Type: domain.MuteTypeKeyword, Keyword: "#SPOILERS"}
+	assert.IsType(t, &application.ErrAlreadyMuted{}, muteRepo.Create(context.Background(), duplicate))
+
+	mutes, err := muteRepo.GetByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, mutes, 2)
+
+	byType := map[domain.MuteType]*domain.Mute{}
+	for _, m := range mutes {
+		byType[m.Type] = m
+	}
+	assert.Equal(t, mutedUserID, byType[domain.MuteTypeUser].MutedUserID)
+	assert.Nil(t, byType[domain.MuteTypeUser].ExpiresAt)
+	assert.Equal(t, "#spoilers", byType[domain.MuteTypeKeyword].Keyword)
+	require.NotNil(t, byType[domain.MuteTypeKeyword].ExpiresAt)
+	assert.True(t, expiresAt.Equal(*byType[domain.MuteTypeKeyword].ExpiresAt))
+}
+
+func TestPostgreSQLMuteRepository_UpdateAndDelete(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	muteRepo := NewPostgreSQLMuteRepository(db)
+
+	userID, mutedUserID := setupTestUsers(t, userRepo)
+
+	mute := &domain.Mute{UserID: userID, Type: domain.MuteTypeUser, MutedUserID: mutedUserID}
+	require.NoError(t, muteRepo.Create(context.Background(), mute))
+
+	expiresAt := time.Now().Add(time.Hour)
+	require.NoError(t, muteRepo.UpdateExpiry(context.Background(), userID, mute.ID, &expiresAt))
+
+	// Another user cannot modify or remove the mute
+	err := muteRepo.Delete(context.Background(), mutedUserID, mute.ID)
+	assert.IsType(t, &application.ErrMuteNotFound{}, err)
+
+	require.NoError(t, muteRepo.Delete(context.Background(), userID, mute.ID))
+
+	mutes, err := muteRepo.GetByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Empty(t, mutes)
+
+	err = muteRepo.UpdateExpiry(context.Background(), userID, mute.ID, nil)
+	assert.IsType(t, &application.ErrMuteNotFound{}, err)
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLRefreshTokenRepository(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	tokenRepo := NewPostgreSQLRefreshTokenRepository(db)
+
+	userID, _ := setupTestUsers(t, userRepo)
+
+	first := &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  "family-1",
+		TokenHash: "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8",
+		ExpiresAt: time.Now().Add(time.Hour).UTC(),
+	}
+	second := &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  "family-1",
+		TokenHash: "6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b",
+		ExpiresAt: time.Now().Add(time.Hour).UTC(),
+	}
+	require.NoError(t, tokenRepo.Create(context.Background(), first))
+	require.NoError(t, tokenRepo.Create(context.Background(), second))
+	assert.NotZero(t, first.ID)
+
+	found, err := tokenRepo.GetByHash(context.Background(), first.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, found.ID)
+	assert.Nil(t, found.RevokedAt)
+
+	_, err = tokenRepo.GetByHash(context.Background(), "unknown")
+	assert.IsType(t, &application.ErrUnauthorized{}, err)
+
+	revoked, err := tokenRepo.Revoke(context.Background(), first.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = tokenRepo.Revoke(context.Background(), first.ID)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, tokenRepo.RevokeFamily(context.Background(), "family-1"))
+	found, err = tokenRepo.GetByHash(context.Background(), second.TokenHash)
+	require.NoError(t, err)
+	assert.NotNil(t, found.RevokedAt)
+}
@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+type PostgreSQLReportRepository struct {
+	db *sql.DB
+}
+
+func NewPostgreSQLReportRepository(db *sql.DB) *PostgreSQLReportRepository {
+	return &PostgreSQLReportRepository{db: db}
+}
+
+const reportColumns = `id, tweet_id, reporter_id, reason, status, resolved_by, resolved_at, created_at`
+
+func (r *PostgreSQLReportRepository) Create(ctx context.Context, report *domain.Report) error {
+	query := `
+		INSERT INTO tweet_reports (tweet_id, reporter_id, reason, status, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	report.Status = domain.ReportStatusOpen
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		report.TweetID,
+		report.ReporterID,
+		report.Reason,
+		report.Status,
+		time.Now().UTC(),
+	).Scan(&report.ID, &report.CreatedAt)
+	if err != nil {
+		if err.Error() == "pq: duplicate key value violates unique constraint \"tweet_reports_tweet_id_reporter_id_key\"" {
+			return application.NewErrAlreadyReported(report.TweetID, report.ReporterID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLReportRepository) GetByID(ctx context.Context, id int64) (*domain.Report, error) {
+	query := `SELECT ` + reportColumns + ` FROM tweet_reports WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	report, err := scanReport(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, application.NewErrReportNotFound(id)
+		}
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (r *PostgreSQLReportRepository) GetOpen(ctx context.Context, limit int) ([]*domain.Report, error) {
+	query := `
+		SELECT ` + reportColumns + `
+		FROM tweet_reports
+		WHERE status = 'open'
+		ORDER BY created_at
+		LIMIT $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]*domain.Report, 0)
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+func (r *PostgreSQLReportRepository) Resolve(ctx context.Context, id int64, status domain.ReportStatus, resolvedBy int) error {
+	query := `
+		UPDATE tweet_reports
+		SET status = $2, resolved_by = $3, resolved_at = $4
+		WHERE id = $1 AND status = 'open'
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id, status, resolvedBy, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrReportNotFound(id)
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLReportRepository) ResolveByTweet(ctx context.Context, tweetID int64, status domain.ReportStatus, resolvedBy int) error {
+	query := `
+		UPDATE tweet_reports
+		SET status = $2, resolved_by = $3, resolved_at = $4
+		WHERE tweet_id = $1 AND status = 'open'
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, query, tweetID, status, resolvedBy, time.Now().UTC())
+	return err
+}
+
+func scanReport(row interface{ Scan(...any) error }) (*domain.Report, error) {
+	var report domain.Report
+	var resolvedBy sql.NullInt64
+	err := row.Scan(
+		&report.ID,
+		&report.TweetID,
+		&report.ReporterID,
+		&report.Reason,
+		&report.Status,
+		&resolvedBy,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedBy.Valid {
+		id := int(resolvedBy.Int64)
+		report.ResolvedBy = &id
+	}
+	return &report, nil
+}
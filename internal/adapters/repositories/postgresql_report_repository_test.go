@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgreSQLReportRepository(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	reportRepo := NewPostgreSQLReportRepository(db)
+
+	reporterID, moderatorID := setupTestUsers(t, userRepo)
+
+	report := &domain.Report{TweetID: 42, ReporterID: reporterID, Reason: "spam"}
+	require.NoError(t, reportRepo.Create(context.Background(), report))
+	assert.NotZero(t, report.ID)
+	assert.Equal(t, domain.ReportStatusOpen, report.Status)
+
+	// A user can only report a tweet once
+	err := reportRepo.Create(context.Background(), &domain.Report{TweetID: 42, ReporterID: reporterID})
+	assert.IsType(t, &application.ErrAlreadyReported{}, err)
+
+	open, err := reportRepo.GetOpen(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, open, 1)
+	assert.Equal(t, "spam", open[0].Reason)
+
+	require.NoError(t, reportRepo.Resolve(context.Background(), report.ID, domain.ReportStatusDismissed, moderatorID))
+	err = reportRepo.Resolve(context.Background(), report.ID, domain.ReportStatusActioned, moderatorID)
+	assert.IsType(t, &application.ErrReportNotFound{}, err)
+
+	found, err := reportRepo.GetByID(context.Background(), report.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReportStatusDismissed, found.Status)
+	require.NotNil(t, found.ResolvedBy)
+	assert.Equal(t, moderatorID, *found.ResolvedBy)
+	assert.NotNil(t, found.ResolvedAt)
+
+	open, err = reportRepo.GetOpen(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+
+	_, err = reportRepo.GetByID(context.Background(), 9999)
+	assert.IsType(t, &application.ErrReportNotFound{}, err)
+}
@@ -1,9 +1,13 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"time"
+	"uala-tweets/internal/application"
 	"uala-tweets/internal/domain"
+
+	"github.com/lib/pq"
 )
 
 type PostgreSQLTweetRepository struct {
@@ -14,26 +18,35 @@ func NewPostgreSQLTweetRepository(db *sql.DB) *PostgreSQLTweetRepository {
 	return &PostgreSQLTweetRepository{db: db}
 }
 
-func (r *PostgreSQLTweetRepository) Create(tweet *domain.Tweet) error {
+// Create inserts a tweet. Creating a tweet whose event ID is already stored
+// inserts nothing and fills in the tweet created the first time instead.
+func (r *PostgreSQLTweetRepository) Create(ctx context.Context, tweet *domain.Tweet) error {
 	query := `
-		INSERT INTO tweets (user_id, content, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO tweets (user_id, content, created_at, updated_at, event_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''))
+		ON CONFLICT (event_id) DO NOTHING
 		RETURNING id, created_at, updated_at
 	`
 
 	now := time.Now().UTC()
-	err := r.db.QueryRow(
+	err := r.db.QueryRowContext(ctx,
 		query,
 		tweet.UserID,
 		tweet.Content,
 		now,
 		now,
+		tweet.EventID,
 	).Scan(&tweet.ID, &tweet.CreatedAt, &tweet.UpdatedAt)
 
+	if err == sql.ErrNoRows {
+		query = `SELECT id, created_at, updated_at FROM tweets WHERE event_id = $1`
+		err = r.db.QueryRowContext(ctx, query, tweet.EventID).Scan(&tweet.ID, &tweet.CreatedAt, &tweet.UpdatedAt)
+	}
+
 	return err
 }
 
-func (r *PostgreSQLTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
+func (r *PostgreSQLTweetRepository) GetByID(ctx context.Context, id int64) (*domain.Tweet, error) {
 	query := `
 		SELECT id, user_id, content, created_at, updated_at
 		FROM tweets
@@ -41,7 +54,7 @@ func (r *PostgreSQLTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
 	`
 
 	tweet := &domain.Tweet{}
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&tweet.ID,
 		&tweet.UserID,
 		&tweet.Content,
@@ -50,13 +63,50 @@ func (r *PostgreSQLTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, sql.ErrNoRows
+		return nil, application.NewErrTweetNotFound(id)
 	}
 
 	return tweet, err
 }
 
-func (r *PostgreSQLTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet, error) {
+func (r *PostgreSQLTweetRepository) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Tweet, error) {
+	query := `
+		SELECT t.id, t.user_id, t.content, t.created_at, t.updated_at
+		FROM tweets t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.id = ANY($1) AND u.deactivated_at IS NULL
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tweets := make([]*domain.Tweet, 0, len(ids))
+	for rows.Next() {
+		tweet := &domain.Tweet{}
+		err := rows.Scan(
+			&tweet.ID,
+			&tweet.UserID,
+			&tweet.Content,
+			&tweet.CreatedAt,
+			&tweet.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tweets = append(tweets, tweet)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tweets, nil
+}
+
+func (r *PostgreSQLTweetRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Tweet, error) {
 	query := `
 		SELECT id, user_id, content, created_at, updated_at
 		FROM tweets
@@ -64,7 +114,7 @@ func (r *PostgreSQLTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet,
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +143,7 @@ func (r *PostgreSQLTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet,
 	return tweets, nil
 }
 
-func (r *PostgreSQLTweetRepository) GetTweetIDsByUser(userID int) ([]int64, error) {
+func (r *PostgreSQLTweetRepository) GetTweetIDsByUser(ctx context.Context, userID int) ([]int64, error) {
 	query := `
 		SELECT id
 		FROM tweets
@@ -101,7 +151,7 @@ func (r *PostgreSQLTweetRepository) GetTweetIDsByUser(userID int) ([]int64, erro
 		ORDER BY created_at DESC
 	`
 
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -122,3 +172,39 @@ func (r *PostgreSQLTweetRepository) GetTweetIDsByUser(userID int) ([]int64, erro
 
 	return tweetIDs, nil
 }
+
+func (r *PostgreSQLTweetRepository) Update(ctx context.Context, tweet *domain.Tweet) error {
+	query := `
+		UPDATE tweets
+		SET content = $2, updated_at = $3
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, tweet.ID, tweet.Content, time.Now().UTC()).Scan(&tweet.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return application.NewErrTweetNotFound(tweet.ID)
+	}
+
+	return err
+}
+
+func (r *PostgreSQLTweetRepository) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM tweets WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrTweetNotFound(id)
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"uala-tweets/internal/application"
 	"uala-tweets/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -21,7 +23,7 @@ func TestPostgreSQLTweetRepository_CreateAndGet(t *testing.T) {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
-	err := userRepo.Create(user)
+	err := userRepo.Create(context.Background(), user)
 	require.NoError(t, err)
 
 	repo := NewPostgreSQLTweetRepository(db)
@@ -44,7 +46,7 @@ func TestPostgreSQLTweetRepository_CreateAndGet(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Test Create
-			err := repo.Create(tt.tweet)
+			err := repo.Create(context.Background(), tt.tweet)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -55,7 +57,7 @@ func TestPostgreSQLTweetRepository_CreateAndGet(t *testing.T) {
 			assert.False(t, tt.tweet.UpdatedAt.IsZero())
 
 			// Test GetByID
-			found, err := repo.GetByID(tt.tweet.ID)
+			found, err := repo.GetByID(context.Background(), tt.tweet.ID)
 			require.NoError(t, err)
 			require.NotNil(t, found)
 			assert.Equal(t, tt.tweet.UserID, found.UserID)
@@ -75,7 +77,7 @@ func TestPostgreSQLTweetRepository_GetByUserID(t *testing.T) {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
-	err := userRepo.Create(user)
+	err := userRepo.Create(context.Background(), user)
 	require.NoError(t, err)
 
 	repo := NewPostgreSQLTweetRepository(db)
@@ -87,12 +89,12 @@ func TestPostgreSQLTweetRepository_GetByUserID(t *testing.T) {
 	}
 
 	for _, tweet := range tweets {
-		err := repo.Create(tweet)
+		err := repo.Create(context.Background(), tweet)
 		require.NoError(t, err)
 	}
 
 	// Test GetByUserID
-	found, err := repo.GetByUserID(int64(user.ID))
+	found, err := repo.GetByUserID(context.Background(), int64(user.ID))
 	require.NoError(t, err)
 	assert.Len(t, found, 2)
 
@@ -116,7 +118,7 @@ func TestPostgreSQLTweetRepository_GetTweetIDsByUser(t *testing.T) {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
-	err := userRepo.Create(user)
+	err := userRepo.Create(context.Background(), user)
 	require.NoError(t, err)
 
 	repo := NewPostgreSQLTweetRepository(db)
@@ -129,13 +131,13 @@ func TestPostgreSQLTweetRepository_GetTweetIDsByUser(t *testing.T) {
 
 	var tweetIDs []int64
 	for _, tweet := range tweets {
-		err := repo.Create(tweet)
+		err := repo.Create(context.Background(), tweet)
 		require.NoError(t, err)
 		tweetIDs = append(tweetIDs, tweet.ID)
 	}
 
 	// Test GetTweetIDsByUser
-	foundIDs, err := repo.GetTweetIDsByUser(user.ID)
+	foundIDs, err := repo.GetTweetIDsByUser(context.Background(), user.ID)
 	require.NoError(t, err)
 	assert.ElementsMatch(t, tweetIDs, foundIDs)
 }
@@ -147,17 +149,111 @@ func TestPostgreSQLTweetRepository_NonExistentTweet(t *testing.T) {
 	repo := NewPostgreSQLTweetRepository(db)
 
 	// Test GetByID with non-existent tweet
-	tweet, err := repo.GetByID(999)
+	tweet, err := repo.GetByID(context.Background(), 999)
 	require.Error(t, err)
 	assert.Nil(t, tweet)
 
 	// Test GetByUserID with no tweets
-	tweets, err := repo.GetByUserID(999)
+	tweets, err := repo.GetByUserID(context.Background(), 999)
 	require.NoError(t, err)
 	assert.Empty(t, tweets)
 
 	// Test GetTweetIDsByUser with no tweets
-	tweetIDs, err := repo.GetTweetIDsByUser(999)
+	tweetIDs, err := repo.GetTweetIDsByUser(context.Background(), 999)
 	require.NoError(t, err)
 	assert.Empty(t, tweetIDs)
 }
+
+func TestPostgreSQLTweetRepository_CreateIsIdempotentPerEvent(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	userID, _ := setupTestUsers(t, userRepo)
+
+	repo := NewPostgreSQLTweetRepository(db)
+	first := &domain.Tweet{UserID: int64(userID), Content: "hello", EventID: "7d8f3b5e-0c1a-4f7e-9b2d-3e4f5a6b7c8d"}
+	require.NoError(t, repo.Create(context.Background(), first))
+
+	redelivered := &domain.Tweet{UserID: int64(userID), Content: "hello", EventID: first.EventID}
+	require.NoError(t, repo.Create(context.Background(), redelivered))
+	assert.Equal(t, first.ID, redelivered.ID)
+
+	ids, err := repo.GetTweetIDsByUser(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Len(t, ids, 1)
+
+	// Tweets without an event ID are never considered duplicates
+	require.NoError(t, repo.Create(context.Background(), &domain.Tweet{UserID: int64(userID), Content: "hello"}))
+	require.NoError(t, repo.Create(context.Background(), &domain.Tweet{UserID: int64(userID), Content: "hello"}))
+	ids, err = repo.GetTweetIDsByUser(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Len(t, ids, 3)
+}
+
+func TestPostgreSQLTweetRepository_UpdateAndDelete(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	userID, _ := setupTestUsers(t, userRepo)
+
+	repo := NewPostgreSQLTweetRepository(db)
+	tweet := &domain.Tweet{
+		UserID:    int64(userID),
+		Content:   "original",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, repo.Create(context.Background(), tweet))
+
+	tweet.Content = "edited"
+	require.NoError(t, repo.Update(context.Background(), tweet))
+
+	found, err := repo.GetByID(context.Background(), tweet.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "edited", found.Content)
+
+	require.NoError(t, repo.Delete(context.Background(), tweet.ID))
+	_, err = repo.GetByID(context.Background(), tweet.ID)
+	assert.IsType(t, &application.ErrTweetNotFound{}, err)
+
+	err = repo.Delete(context.Background(), tweet.ID)
+	assert.IsType(t, &application.ErrTweetNotFound{}, err)
+	err = repo.Update(context.Background(), tweet)
+	assert.IsType(t, &application.ErrTweetNotFound{}, err)
+}
+
+func TestPostgreSQLTweetRepository_GetByIDs(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	userRepo := NewPostgreSQLUserRepository(db)
+	user := &domain.User{
+		Username:  "testuser",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	err := userRepo.Create(context.Background(), user)
+	require.NoError(t, err)
+
+	repo := NewPostgreSQLTweetRepository(db)
+
+	first := &domain.Tweet{UserID: int64(user.ID), Content: "First tweet"}
+	second := &domain.Tweet{UserID: int64(user.ID), Content: "Second tweet"}
+	require.NoError(t, repo.Create(context.Background(), first))
+	require.NoError(t, repo.Create(context.Background(), second))
+
+	// Unknown IDs are skipped rather than reported as errors
+	found, err := repo.GetByIDs(context.Background(), []int64{second.ID, 999})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "Second tweet", found[0].Content)
+
+	// Tweets of deactivated accounts are skipped too
+	now := time.Now().UTC()
+	require.NoError(t, userRepo.SetDeactivatedAt(context.Background(), user.ID, &now))
+	found, err = repo.GetByIDs(context.Background(), []int64{first.ID, second.ID})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -8,6 +8,8 @@ import (
 
 	"uala-tweets/internal/application"
 	"uala-tweets/internal/domain"
+
+	"github.com/lib/pq"
 )
 
 type PostgreSQLUserRepository struct {
@@ -18,27 +20,43 @@ func NewPostgreSQLUserRepository(db *sql.DB) *PostgreSQLUserRepository {
 	return &PostgreSQLUserRepository{db: db}
 }
 
-func (r *PostgreSQLUserRepository) Create(user *domain.User) error {
+const userColumns = `id, username, password_hash, role, display_name, bio, location, website, avatar_url, is_protected, deactivated_at, created_at, updated_at`
+
+func (r *PostgreSQLUserRepository) Create(ctx context.Context, user *domain.User) error {
+	// Usernames in the history still resolve to their previous owner
 	query := `
-		INSERT INTO users (username, created_at, updated_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO users (username, password_hash, role, display_name, bio, location, website, avatar_url, is_protected, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		WHERE NOT EXISTS (SELECT 1 FROM username_history WHERE username = $1)
 		RETURNING id
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	err := r.db.QueryRowContext(
 		ctx,
 		query,
 		user.Username,
+		user.PasswordHash,
+		user.Role,
+		user.DisplayName,
+		user.Bio,
+		user.Location,
+		user.Website,
+		user.AvatarURL,
+		user.Protected,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID)
 
 	if err != nil {
-		// Check for unique violation (duplicate username)
-		if err.Error() == "pq: duplicate key value violates unique constraint \"users_username_key\"" {
+		// Check for unique violation (duplicate username) or a reserved username
+		if isDuplicateUsername(err) || errors.Is(err, sql.ErrNoRows) {
 			return application.NewErrUserAlreadyExists(user.Username)
 		}
 		return err
@@ -47,42 +65,158 @@ func (r *PostgreSQLUserRepository) Create(user *domain.User) error {
 	return nil
 }
 
-func (r *PostgreSQLUserRepository) GetByID(id int) (*domain.User, error) {
+func (r *PostgreSQLUserRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, application.NewErrUserNotFound(id)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *PostgreSQLUserRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ANY($1)`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*domain.User, 0, len(ids))
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *PostgreSQLUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, username, created_at, updated_at
+		SELECT ` + userColumns + `
 		FROM users
-		WHERE id = $1
+		WHERE id = COALESCE(
+			(SELECT id FROM users WHERE username = $1),
+			(SELECT user_id FROM username_history WHERE username = $1)
+		)
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	var user domain.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
-
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, username))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, application.NewErrUserNotFound(id)
+			return nil, application.NewErrUsernameNotFound(username)
 		}
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
-func (r *PostgreSQLUserRepository) Exists(id int) (bool, error) {
+// Update saves the profile of a user. When the username changes, the old
+// one is recorded in the history so it keeps resolving to this user.
+func (r *PostgreSQLUserRepository) Update(ctx context.Context, user *domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var currentUsername string
+	err = tx.QueryRowContext(ctx, `SELECT username FROM users WHERE id = $1 FOR UPDATE`, user.ID).Scan(&currentUsername)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return application.NewErrUserNotFound(user.ID)
+		}
+		return err
+	}
+
+	if currentUsername != user.Username {
+		var previousOwner int
+		err = tx.QueryRowContext(ctx, `SELECT user_id FROM username_history WHERE username = $1`, user.Username).Scan(&previousOwner)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return err
+		case previousOwner != user.ID:
+			return application.NewErrUserAlreadyExists(user.Username)
+		default:
+			// Taking back one of your own previous usernames
+			if _, err := tx.ExecContext(ctx, `DELETE FROM username_history WHERE username = $1`, user.Username); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO username_history (username, user_id, changed_at) VALUES ($1, $2, $3)`,
+			currentUsername,
+			user.ID,
+			time.Now().UTC(),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE users
+		SET username = $2, display_name = $3, bio = $4, location = $5, website = $6, avatar_url = $7, updated_at = $8
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		user.ID,
+		user.Username,
+		user.DisplayName,
+		user.Bio,
+		user.Location,
+		user.Website,
+		user.AvatarURL,
+		time.Now().UTC(),
+	).Scan(&user.UpdatedAt)
+	if err != nil {
+		if isDuplicateUsername(err) {
+			return application.NewErrUserAlreadyExists(user.Username)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgreSQLUserRepository) Exists(ctx context.Context, id int) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM users WHERE id = $1
 		)
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var exists bool
@@ -93,3 +227,219 @@ func (r *PostgreSQLUserRepository) Exists(id int) (bool, error) {
 
 	return exists, nil
 }
+
+func (r *PostgreSQLUserRepository) SetProtected(ctx context.Context, id int, protected bool) error {
+	query := `
+		UPDATE users
+		SET is_protected = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id, protected, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrUserNotFound(id)
+	}
+
+	return nil
+}
+
+func (r *PostgreSQLUserRepository) SetRole(ctx context.Context, id int, role domain.Role) error {
+	query := `
+		UPDATE users
+		SET role = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id, role, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrUserNotFound(id)
+	}
+
+	return nil
+}
+
+// ListIDs returns up to limit user IDs greater than afterID in ascending
+// order, so callers can page through every user with keyset pagination.
+func (r *PostgreSQLUserRepository) ListIDs(ctx context.Context, afterID int, limit int) ([]int, error) {
+	query := `SELECT id FROM users WHERE id > $1 ORDER BY id LIMIT $2`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SetDeactivatedAt marks a user as deactivated at the given time, or
+// reactivates them when at is nil.
+func (r *PostgreSQLUserRepository) SetDeactivatedAt(ctx context.Context, id int, at *time.Time) error {
+	query := `
+		UPDATE users
+		SET deactivated_at = $2, updated_at = $3
+		WHERE id = $1
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id, at, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrUserNotFound(id)
+	}
+
+	return nil
+}
+
+// GetDeactivatedBefore returns up to limit users deactivated before cutoff.
+func (r *PostgreSQLUserRepository) GetDeactivatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]int, error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE deactivated_at IS NOT NULL AND deactivated_at < $1
+		ORDER BY deactivated_at
+		LIMIT $2
+	`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete removes a user. Their tweets, follows, mutes, lists and follow
+// requests go with them through ON DELETE CASCADE.
+func (r *PostgreSQLUserRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM users WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return application.NewErrUserNotFound(id)
+	}
+
+	return nil
+}
+
+// DeleteDeactivated checks the deactivation in the same statement as the
+// delete, so a user reactivating in the meantime is never deleted.
+func (r *PostgreSQLUserRepository) DeleteDeactivated(ctx context.Context, id int, cutoff time.Time) (bool, error) {
+	query := `DELETE FROM users WHERE id = $1 AND deactivated_at IS NOT NULL AND deactivated_at < $2`
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, id, cutoff)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func scanUser(row interface{ Scan(dest ...any) error }) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Role,
+		&user.DisplayName,
+		&user.Bio,
+		&user.Location,
+		&user.Website,
+		&user.AvatarURL,
+		&user.Protected,
+		&user.DeactivatedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func isDuplicateUsername(err error) bool {
+	return err.Error() == "pq: duplicate key value violates unique constraint \"users_username_key\""
+}
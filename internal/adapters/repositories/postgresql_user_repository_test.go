@@ -1,9 +1,11 @@
 package repositories
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"uala-tweets/internal/application"
 	"uala-tweets/internal/domain"
 
 	"github.com/stretchr/testify/assert"
@@ -35,7 +37,7 @@ func TestPostgreSQLUserRepository_CreateAndGet(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Test Create
-			err := repo.Create(tt.user)
+			err := repo.Create(context.Background(), tt.user)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -44,13 +46,13 @@ func TestPostgreSQLUserRepository_CreateAndGet(t *testing.T) {
 			assert.NotZero(t, tt.user.ID)
 
 			// Test GetByID
-			found, err := repo.GetByID(tt.user.ID)
+			found, err := repo.GetByID(context.Background(), tt.user.ID)
 			require.NoError(t, err)
 			require.NotNil(t, found)
 			assert.Equal(t, tt.user.Username, found.Username)
 
 			// Test Exists
-			exists, err := repo.Exists(tt.user.ID)
+			exists, err := repo.Exists(context.Background(), tt.user.ID)
 			require.NoError(t, err)
 			assert.True(t, exists)
 		})
@@ -71,7 +73,7 @@ func TestPostgreSQLUserRepository_NonExistentUser(t *testing.T) {
 		{
 			name: "get non-existent user",
 			testFunc: func(t *testing.T, r *PostgreSQLUserRepository, id int) {
-				user, err := r.GetByID(id)
+				user, err := r.GetByID(context.Background(), id)
 				require.Error(t, err)
 				assert.Nil(t, user)
 			},
@@ -79,7 +81,7 @@ func TestPostgreSQLUserRepository_NonExistentUser(t *testing.T) {
 		{
 			name: "check non-existent user exists",
 			testFunc: func(t *testing.T, r *PostgreSQLUserRepository, id int) {
-				exists, err := r.Exists(id)
+				exists, err := r.Exists(context.Background(), id)
 				require.NoError(t, err)
 				assert.False(t, exists)
 			},
@@ -92,3 +94,190 @@ func TestPostgreSQLUserRepository_NonExistentUser(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgreSQLUserRepository_SetProtected(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLUserRepository(db)
+
+	user := &domain.User{
+		Username:  "testuser",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, repo.Create(context.Background(), user))
+
+	require.NoError(t, repo.SetProtected(context.Background(), user.ID, true))
+
+	found, err := repo.GetByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.True(t, found.Protected)
+
+	err = repo.SetProtected(context.Background(), 999, true)
+	assert.Error(t, err)
+}
+
+func TestPostgreSQLUserRepository_SetRole(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLUserRepository(db)
+
+	user := &domain.User{
+		Username:  "testuser",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	require.NoError(t, repo.Create(context.Background(), user))
+	assert.Equal(t, domain.RoleUser, user.Role)
+
+	require.NoError(t, repo.SetRole(context.Background(), user.ID, domain.RoleModerator))
+
+	found, err := repo.GetByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RoleModerator, found.Role)
+
+	err = repo.SetRole(context.Background(), 999, domain.RoleAdmin)
+	assert.Error(t, err)
+}
+
+func TestPostgreSQLUserRepository_ListIDs(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLUserRepository(db)
+
+	firstID, secondID := setupTestUsers(t, repo)
+
+	ids, err := repo.ListIDs(context.Background(), 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{firstID}, ids)
+
+	ids, err = repo.ListIDs(context.Background(), firstID, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{secondID}, ids)
+
+	ids, err = repo.ListIDs(context.Background(), secondID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestPostgreSQLUserRepository_GetByIDs(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLUserRepository(db)
+
+	firstID, secondID := setupTestUsers(t, repo)
+
+	users, err := repo.GetByIDs(context.Background(), []int{firstID, secondID, secondID + 1000})
+	require.NoError(t, err)
+	ids := make([]int, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	assert.ElementsMatch(t, []int{firstID, secondID}, ids)
+}
+
+func TestPostgreSQLUserRepository_UpdateAndUsernameHistory(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLUserRepository(db)
+
+	firstID, secondID := setupTestUsers(t, repo)
+
+	user, err := repo.GetByID(context.Background(), firstID)
+	require.NoError(t, err)
+
+	user.Username = "renamed"
+	user.DisplayName = "Renamed User"
+	user.Bio = "Now with a bio"
+	require.NoError(t, repo.Update(context.Background(), user))
+
+	found, err := repo.GetByUsername(context.Background(), "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, firstID, found.ID)
+	assert.Equal(t, "Renamed User", found.DisplayName)
+	assert.Equal(t, "Now with a bio", found.Bio)
+
+	// The old username still resolves to the same user
+	found, err = repo.GetByUsername(context.Background(), "follower")
+	require.NoError(t, err)
+	assert.Equal(t, firstID, found.ID)
+	assert.Equal(t, "renamed", found.Username)
+
+	// Nobody else can take the old username
+	other, err := repo.GetByID(context.Background(), secondID)
+	require.NoError(t, err)
+	other.Username = "follower"
+	assert.IsType(t, &application.ErrUserAlreadyExists{}, repo.Update(context.Background(), other))
+
+	err = repo.Create(context.Background(), &domain.User{Username: "follower", CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()})
+	assert.IsType(t, &application.ErrUserAlreadyExists{}, err)
+
+	// Nor a username that is currently in use
+	other.Username = "renamed"
+	assert.IsType(t, &application.ErrUserAlreadyExists{}, repo.Update(context.Background(), other))
+
+	// The user can take their old username back
+	user.Username = "follower"
+	require.NoError(t, repo.Update(context.Background(), user))
+
+	found, err = repo.GetByUsername(context.Background(), "renamed")
+	require.NoError(t, err)
+	assert.Equal(t, firstID, found.ID)
+	assert.Equal(t, "follower", found.Username)
+
+	_, err = repo.GetByUsername(context.Background(), "nobody")
+	assert.IsType(t, &application.ErrUsernameNotFound{}, err)
+}
+
+func TestPostgreSQLUserRepository_DeactivateAndDelete(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	repo := NewPostgreSQLUserRepository(db)
+	followRepo := NewPostgreSQLFollowRepository(db)
+
+	userID, otherID := setupTestUsers(t, repo)
+	require.NoError(t, followRepo.Follow(context.Background(), otherID, userID))
+
+	deactivatedAt := time.Now().Add(-2 * time.Hour).UTC()
+	require.NoError(t, repo.SetDeactivatedAt(context.Background(), userID, &deactivatedAt))
+
+	user, err := repo.GetByID(context.Background(), userID)
+	require.NoError(t, err)
+	require.NotNil(t, user.DeactivatedAt)
+
+	ids, err := repo.GetDeactivatedBefore(context.Background(), time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{userID}, ids)
+
+	ids, err = repo.GetDeactivatedBefore(context.Background(), time.Now().Add(-3*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	// Only users deactivated before the cutoff are deleted
+	deleted, err := repo.DeleteDeactivated(context.Background(), otherID, time.Now())
+	require.NoError(t, err)
+	assert.False(t, deleted)
+	deleted, err = repo.DeleteDeactivated(context.Background(), userID, time.Now().Add(-3*time.Hour))
+	require.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = repo.DeleteDeactivated(context.Background(), userID, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	_, err = repo.GetByID(context.Background(), userID)
+	assert.IsType(t, &application.ErrUserNotFound{}, err)
+
+	// Follows of the deleted user are removed by the cascade
+	following, err := followRepo.GetFollowing(context.Background(), otherID)
+	require.NoError(t, err)
+	assert.Empty(t, following)
+
+	assert.IsType(t, &application.ErrUserNotFound{}, repo.Delete(context.Background(), userID))
+}
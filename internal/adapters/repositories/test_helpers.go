@@ -117,7 +117,7 @@ func runMigrations(dbURL string) error {
 func truncateTables(t *testing.T, db *sql.DB) {
 	t.Helper()
 
-	tables := []string{"follows", "users"}
+	tables := []string{"follows", "users", "dead_letters"}
 	for _, table := range tables {
 		_, err := db.Exec(fmt.Sprintf("TRUNCATE TABLE %s CASCADE", table))
 		if err != nil {
@@ -0,0 +1,40 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaHealthCheck checks that the brokers answer with the metadata of the
+// topics the service uses
+type KafkaHealthCheck struct {
+	admin  KafkaAdmin
+	topics []string
+}
+
+func NewKafkaHealthCheck(admin KafkaAdmin, topics []string) *KafkaHealthCheck {
+	return &KafkaHealthCheck{admin: admin, topics: topics}
+}
+
+func (c *KafkaHealthCheck) Name() string { return "kafka" }
+
+func (c *KafkaHealthCheck) Check(ctx context.Context) error {
+	metadata, err := c.admin.Metadata(ctx, &kafka.MetadataRequest{Topics: c.topics})
+	if err != nil {
+		return fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+	if len(metadata.Brokers) == 0 {
+		return errors.New("no brokers in the cluster metadata")
+	}
+
+	var errs []error
+	for _, topic := range metadata.Topics {
+		if topic.Error != nil {
+			errs = append(errs, fmt.Errorf("topic %s: %w", topic.Name, topic.Error))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,45 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaHealthCheck(t *testing.T) {
+	topics := []string{"tweets.created", "timeline.fanout"}
+
+	testCases := []struct {
+		name    string
+		admin   *mockKafkaAdmin
+		wantErr string
+	}{
+		{
+			name:  "topics exist",
+			admin: &mockKafkaAdmin{topics: map[string]int{"tweets.created": 6, "timeline.fanout": 6}},
+		},
+		{
+			name:    "topic missing",
+			admin:   &mockKafkaAdmin{topics: map[string]int{"tweets.created": 6}},
+			wantErr: "topic timeline.fanout: [3] Unknown Topic Or Partition",
+		},
+		{
+			name:    "brokers unreachable",
+			admin:   &mockKafkaAdmin{metadataErr: errors.New("connection refused")},
+			wantErr: "failed to fetch metadata: connection refused",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewKafkaHealthCheck(tc.admin, topics).Check(context.Background())
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
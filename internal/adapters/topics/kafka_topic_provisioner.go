@@ -0,0 +1,145 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Backoff between attempts to reach the brokers, which may still be
+// starting up alongside the service
+const (
+	provisionBackoff    = time.Second
+	provisionMaxBackoff = 10 * time.Second
+)
+
+// TopicConfig is how a topic is created
+type TopicConfig struct {
+	Name              string
+	Partitions        int
+	ReplicationFactor int
+	// Retention is how long messages are kept, or the broker's default
+	// when zero
+	Retention time.Duration
+}
+
+// KafkaAdmin is the part of a Kafka client that manages topics
+type KafkaAdmin interface {
+	Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	CreateTopics(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error)
+}
+
+// KafkaTopicProvisioner creates the topics the service publishes to and
+// consumes from, rather than leaving them to be auto-created with the
+// broker's defaults
+type KafkaTopicProvisioner struct {
+	admin KafkaAdmin
+}
+
+func NewKafkaTopicProvisioner(admin KafkaAdmin) *KafkaTopicProvisioner {
+	return &KafkaTopicProvisioner{admin: admin}
+}
+
+// Provision creates the topics that do not exist yet, retrying until the
+// brokers answer or ctx is done; a topic the brokers refuse to create is an
+// error straight away. Existing topics are left as they are: adding
+// partitions would move keys to other partitions and break their ordering,
+// so a topic with fewer partitions than configured is only reported.
+func (p *KafkaTopicProvisioner) Provision(ctx context.Context, topics []TopicConfig) error {
+	backoff := provisionBackoff
+	for {
+		err := p.provision(ctx, topics)
+		if !errors.As(err, new(*unreachableError)) || ctx.Err() != nil {
+			return err
+		}
+
+		slog.ErrorContext(ctx, "Error provisioning Kafka topics, retrying", "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, provisionMaxBackoff)
+	}
+}
+
+func (p *KafkaTopicProvisioner) provision(ctx context.Context, topics []TopicConfig) error {
+	names := make([]string, len(topics))
+	for i, topic := range topics {
+		names[i] = topic.Name
+	}
+	metadata, err := p.admin.Metadata(ctx, &kafka.MetadataRequest{Topics: names})
+	if err != nil {
+		return &unreachableError{fmt.Errorf("failed to fetch topic metadata: %w", err)}
+	}
+
+	existing := make(map[string]int)
+	for _, topic := range metadata.Topics {
+		if topic.Error == nil {
+			existing[topic.Name] = len(topic.Partitions)
+		}
+	}
+
+	var missing []kafka.TopicConfig
+	for _, topic := range topics {
+		partitions, ok := existing[topic.Name]
+		if !ok {
+			missing = append(missing, kafkaTopicConfig(topic))
+			continue
+		}
+		if partitions < topic.Partitions {
+			slog.WarnContext(ctx, "Topic has fewer partitions than configured; add them by hand if its keys can be reordered",
+				"topic", topic.Name, "partitions", partitions, "configured", topic.Partitions)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	resp, err := p.admin.CreateTopics(ctx, &kafka.CreateTopicsRequest{Topics: missing})
+	if err != nil {
+		return &unreachableError{fmt.Errorf("failed to create topics: %w", err)}
+	}
+
+	var errs []error
+	for _, topic := range missing {
+		err := resp.Errors[topic.Topic]
+		switch {
+		case err == nil:
+			slog.InfoContext(ctx, "Created topic", "topic", topic.Topic, "partitions", topic.NumPartitions)
+		case errors.Is(err, kafka.TopicAlreadyExists):
+			// Created by another instance in the meantime
+		default:
+			errs = append(errs, fmt.Errorf("failed to create topic %s: %w", topic.Topic, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// unreachableError marks requests the brokers did not answer
+type unreachableError struct {
+	err error
+}
+
+func (e *unreachableError) Error() string { return e.err.Error() }
+func (e *unreachableError) Unwrap() error { return e.err }
+
+func kafkaTopicConfig(topic TopicConfig) kafka.TopicConfig {
+	config := kafka.TopicConfig{
+		Topic:             topic.Name,
+		NumPartitions:     topic.Partitions,
+		ReplicationFactor: topic.ReplicationFactor,
+	}
+	if topic.Retention > 0 {
+		config.ConfigEntries = append(config.ConfigEntries, kafka.ConfigEntry{
+			ConfigName:  "retention.ms",
+			ConfigValue: strconv.FormatInt(topic.Retention.Milliseconds(), 10),
+		})
+	}
+	return config
+}
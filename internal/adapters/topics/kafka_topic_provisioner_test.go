@@ -0,0 +1,120 @@
+package topics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockKafkaAdmin struct {
+	topics      map[string]int
+	metadataErr error
+	createErrs  map[string]error
+	created     []kafka.TopicConfig
+}
+
+func (a *mockKafkaAdmin) Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error) {
+	if a.metadataErr != nil {
+		err := a.metadataErr
+		a.metadataErr = nil
+		return nil, err
+	}
+	resp := &kafka.MetadataResponse{Brokers: []kafka.Broker{{ID: 1}}}
+	for _, name := range req.Topics {
+		partitions, ok := a.topics[name]
+		if !ok {
+			resp.Topics = append(resp.Topics, kafka.Topic{Name: name, Error: kafka.UnknownTopicOrPartition})
+			continue
+		}
+		resp.Topics = append(resp.Topics, kafka.Topic{Name: name, Partitions: make([]kafka.Partition, partitions)})
+	}
+	return resp, nil
+}
+
+func (a *mockKafkaAdmin) CreateTopics(ctx context.Context, req *kafka.CreateTopicsRequest) (*kafka.CreateTopicsResponse, error) {
+	a.created = append(a.created, req.Topics...)
+	return &kafka.CreateTopicsResponse{Errors: a.createErrs}, nil
+}
+
+func TestKafkaTopicProvisioner_Provision(t *testing.T) {
+	topics := []TopicConfig{
+		{Name: "tweets.created", Partitions: 6, ReplicationFactor: 3, Retention: 7 * 24 * time.Hour},
+		{Name: "tweets.created.dlq", Partitions: 6, ReplicationFactor: 3},
+		{Name: "timeline.fanout", Partitions: 12, ReplicationFactor: 3},
+	}
+
+	testCases := []struct {
+		name        string
+		admin       *mockKafkaAdmin
+		wantCreated []string
+		wantErr     bool
+	}{
+		{
+			name:        "creates missing topics",
+			admin:       &mockKafkaAdmin{topics: map[string]int{"timeline.fanout": 3}},
+			wantCreated: []string{"tweets.created", "tweets.created.dlq"},
+		},
+		{
+			name:  "leaves existing topics alone",
+			admin: &mockKafkaAdmin{topics: map[string]int{"tweets.created": 6, "tweets.created.dlq": 6, "timeline.fanout": 12}},
+		},
+		{
+			name:        "retries when the brokers are not up yet",
+			admin:       &mockKafkaAdmin{metadataErr: errors.New("connection refused")},
+			wantCreated: []string{"tweets.created", "tweets.created.dlq", "timeline.fanout"},
+		},
+		{
+			name: "topic created in the meantime",
+			admin: &mockKafkaAdmin{
+				topics:     map[string]int{"tweets.created.dlq": 6, "timeline.fanout": 12},
+				createErrs: map[string]error{"tweets.created": kafka.TopicAlreadyExists},
+			},
+			wantCreated: []string{"tweets.created"},
+		},
+		{
+			name: "creation rejected",
+			admin: &mockKafkaAdmin{
+				topics:     map[string]int{"tweets.created.dlq": 6, "timeline.fanout": 12},
+				createErrs: map[string]error{"tweets.created": kafka.InvalidReplicationFactor},
+			},
+			wantCreated: []string{"tweets.created"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			defer cancel()
+
+			err := NewKafkaTopicProvisioner(tc.admin).Provision(ctx, topics)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var created []string
+			for _, topic := range tc.admin.created {
+				created = append(created, topic.Topic)
+			}
+			assert.Equal(t, tc.wantCreated, created)
+		})
+	}
+}
+
+func TestKafkaTopicConfig(t *testing.T) {
+	config := kafkaTopicConfig(TopicConfig{Name: "tweets.created", Partitions: 6, ReplicationFactor: 3, Retention: 2 * time.Hour})
+
+	assert.Equal(t, "tweets.created", config.Topic)
+	assert.Equal(t, 6, config.NumPartitions)
+	assert.Equal(t, 3, config.ReplicationFactor)
+	assert.Equal(t, []kafka.ConfigEntry{{ConfigName: "retention.ms", ConfigValue: "7200000"}}, config.ConfigEntries)
+
+	assert.Empty(t, kafkaTopicConfig(TopicConfig{Name: "tweets.created"}).ConfigEntries)
+}
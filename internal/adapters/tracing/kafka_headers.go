@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// KafkaHeaders carries trace context in the headers of a Kafka message
+type KafkaHeaders struct {
+	Headers *[]kafka.Header
+}
+
+var _ propagation.TextMapCarrier = KafkaHeaders{}
+
+func (h KafkaHeaders) Get(key string) string {
+	for _, header := range *h.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the header named key, so a message republished in another
+// trace only carries the latest
+func (h KafkaHeaders) Set(key, value string) {
+	for i, header := range *h.Headers {
+		if header.Key == key {
+			(*h.Headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*h.Headers = append(*h.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (h KafkaHeaders) Keys() []string {
+	keys := make([]string, 0, len(*h.Headers))
+	for _, header := range *h.Headers {
+		keys = append(keys, header.Key)
+	}
+	return keys
+}
+
+// Inject adds the trace context of ctx to the headers of a message
+func Inject(ctx context.Context, headers *[]kafka.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, KafkaHeaders{Headers: headers})
+}
+
+// Extract returns ctx continuing the trace carried in the headers of a
+// message
+func Extract(ctx context.Context, headers []kafka.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, KafkaHeaders{Headers: &headers})
+}
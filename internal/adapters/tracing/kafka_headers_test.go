@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInjectExtract_CarriesTheTraceThroughHeaders(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+
+	m := kafka.Message{Headers: []kafka.Header{{Key: "content-type", Value: []byte("application/json")}}}
+	Inject(ctx, &m.Headers)
+
+	require.Len(t, m.Headers, 2)
+	assert.Equal(t, "content-type", m.Headers[0].Key)
+	assert.Equal(t, "traceparent", m.Headers[1].Key)
+
+	extracted := trace.SpanContextFromContext(Extract(context.Background(), m.Headers))
+	assert.True(t, extracted.IsRemote())
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+}
+
+func TestInject_ReplacesTheTraceOfARepublishedMessage(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	provider := sdktrace.NewTracerProvider()
+	first, firstSpan := provider.Tracer("test").Start(context.Background(), "first")
+	defer firstSpan.End()
+	second, secondSpan := provider.Tracer("test").Start(context.Background(), "second")
+	defer secondSpan.End()
+
+	var m kafka.Message
+	Inject(first, &m.Headers)
+	Inject(second, &m.Headers)
+
+	require.Len(t, m.Headers, 1)
+	assert.Equal(t, secondSpan.SpanContext().TraceID(), trace.SpanContextFromContext(Extract(context.Background(), m.Headers)).TraceID())
+}
+
+func TestExtract_WithoutTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctx := Extract(context.Background(), nil)
+
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+}
@@ -0,0 +1,278 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/publishers"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const purgeBatchSize = 100
+
+// Export record types, one per kind of line in a data export
+const (
+	ExportRecordProfile   = "profile"
+	ExportRecordTweet     = "tweet"
+	ExportRecordFollowing = "following"
+	ExportRecordFollower  = "follower"
+	// ExportRecordError ends an export that failed partway through
+	ExportRecordError = "error"
+)
+
+// ExportRecord is one line of a user's data export.
+type ExportRecord struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+type ExportedTweet struct {
+	ID        int64     `json:"id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExportedFollow struct {
+	UserID int `json:"user_id"`
+}
+
+// AccountService handles the lifecycle of accounts: deactivation, deletion
+// once the grace period has passed, and data export.
+type AccountService struct {
+	userRepo          repositories.UserRepository
+	followRepo        repositories.FollowRepository
+	tweetRepo         repositories.TweetRepository
+	listRepo          repositories.ListRepository
+	timelineCache     repositories.TimelineCache
+	listTimelineCache repositories.TimelineCache
+	userEventPub      publishers.UserEventPublisher
+	gracePeriod       time.Duration
+}
+
+func NewAccountService(
+	userRepo repositories.UserRepository,
+	followRepo repositories.FollowRepository,
+	tweetRepo repositories.TweetRepository,
+	listRepo repositories.ListRepository,
+	timelineCache repositories.TimelineCache,
+	listTimelineCache repositories.TimelineCache,
+	userEventPub publishers.UserEventPublisher,
+	gracePeriod time.Duration,
+) *AccountService {
+	return &AccountService{
+		userRepo:          userRepo,
+		followRepo:        followRepo,
+		tweetRepo:         tweetRepo,
+		listRepo:          listRepo,
+		timelineCache:     timelineCache,
+		listTimelineCache: listTimelineCache,
+		userEventPub:      userEventPub,
+		gracePeriod:       gracePeriod,
+	}
+}
+
+// Deactivate schedules an account for deletion and returns when it will be
+// deleted. Deactivating an already deactivated account changes nothing.
+func (s *AccountService) Deactivate(ctx context.Context, userID int) (time.Time, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.Deactivate")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if user.DeactivatedAt != nil {
+		return user.DeactivatedAt.Add(s.gracePeriod), nil
+	}
+
+	now := time.Now().UTC()
+	if err := s.userRepo.SetDeactivatedAt(ctx, userID, &now); err != nil {
+		return time.Time{}, err
+	}
+	s.publishUserEvent(ctx, domain.UserEventDeactivated, user)
+
+	return now.Add(s.gracePeriod), nil
+}
+
+// Reactivate cancels the pending deletion of an account.
+func (s *AccountService) Reactivate(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "AccountService.Reactivate")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeactivatedAt == nil {
+		return NewErrInvalidInput("account is not deactivated")
+	}
+
+	if err := s.userRepo.SetDeactivatedAt(ctx, userID, nil); err != nil {
+		return err
+	}
+	s.publishUserEvent(ctx, domain.UserEventReactivated, user)
+	return nil
+}
+
+// PurgeUser hard deletes an account deactivated before cutoff, reporting
+// whether it was purged; an account reactivated since is left alone. The
+// followers and list memberships needed to find the cached timelines with
+// the user's tweets are looked up first, since they are deleted with the
+// user, and the caches are cleaned once the user is gone. Cleaning them is
+// best effort: timeline reads already drop tweets that no longer exist, so
+// a cache failure is logged rather than failing the purge.
+func (s *AccountService) PurgeUser(ctx context.Context, userID int, cutoff time.Time) (bool, error) {
+	ctx, span := tracer.Start(ctx, "AccountService.PurgeUser")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	tweetIDs, err := s.tweetRepo.GetTweetIDsByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	followers, err := s.followRepo.GetFollowers(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	memberOf, err := s.listRepo.GetListIDsByMember(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	// Lists owned by the user are deleted with them
+	ownedLists, err := s.listRepo.GetByOwnerID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	deleted, err := s.userRepo.DeleteDeactivated(ctx, userID, cutoff)
+	if err != nil || !deleted {
+		return false, err
+	}
+
+	remover := timelineRemover{
+		timelineCache:     s.timelineCache,
+		listTimelineCache: s.listTimelineCache,
+	}
+	errs := []error{remover.removeFrom(ctx, followers, memberOf, tweetIDs)}
+	for _, list := range ownedLists {
+		errs = append(errs, s.listTimelineCache.ClearTimeline(ctx, list.ID))
+	}
+	errs = append(errs, s.timelineCache.ClearTimeline(ctx, userID))
+	if err := errors.Join(errs...); err != nil {
+		slog.ErrorContext(ctx, "Error cleaning cached timelines of purged user", "user_id", userID, "error", err)
+	}
+
+	s.publishUserEvent(ctx, domain.UserEventDeleted, user)
+	return true, nil
+}
+
+// PurgeDeactivated deletes every account whose grace period has passed. A
+// failure for one account does not stop the run; it is counted in failed
+// and retried on the next run.
+func (s *AccountService) PurgeDeactivated(ctx context.Context) (purged int, failed int, err error) {
+	ctx, span := tracer.Start(ctx, "AccountService.PurgeDeactivated")
+	defer span.End()
+
+	cutoff := time.Now().Add(-s.gracePeriod)
+	skip := make(map[int]bool)
+	for {
+		if err := ctx.Err(); err != nil {
+			return purged, failed, err
+		}
+
+		ids, err := s.userRepo.GetDeactivatedBefore(ctx, cutoff, purgeBatchSize+len(skip))
+		if err != nil {
+			return purged, failed, err
+		}
+
+		progress := false
+		for _, id := range ids {
+			if skip[id] {
+				continue
+			}
+			ok, err := s.PurgeUser(ctx, id, cutoff)
+			if err != nil {
+				skip[id] = true
+				failed++
+				continue
+			}
+			if ok {
+				purged++
+				progress = true
+			}
+		}
+
+		if !progress {
+			return purged, failed, nil
+		}
+	}
+}
+
+// Export streams a user's data to emit, one record at a time: the profile
+// first, then their tweets, the accounts they follow and their followers.
+func (s *AccountService) Export(ctx context.Context, userID int, emit func(ExportRecord) error) error {
+	ctx, span := tracer.Start(ctx, "AccountService.Export")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := emit(ExportRecord{Type: ExportRecordProfile, Data: user}); err != nil {
+		return err
+	}
+
+	tweets, err := s.tweetRepo.GetByUserID(ctx, int64(userID))
+	if err != nil {
+		return err
+	}
+	for _, tweet := range tweets {
+		exported := ExportedTweet{ID: tweet.ID, Content: tweet.Content, CreatedAt: tweet.CreatedAt, UpdatedAt: tweet.UpdatedAt}
+		if err := emit(ExportRecord{Type: ExportRecordTweet, Data: exported}); err != nil {
+			return err
+		}
+	}
+
+	following, err := s.followRepo.GetFollowing(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range following {
+		if err := emit(ExportRecord{Type: ExportRecordFollowing, Data: ExportedFollow{UserID: id}}); err != nil {
+			return err
+		}
+	}
+
+	followers, err := s.followRepo.GetFollowers(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, id := range followers {
+		if err := emit(ExportRecord{Type: ExportRecordFollower, Data: ExportedFollow{UserID: id}}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *AccountService) publishUserEvent(ctx context.Context, eventType domain.UserEventType, user *domain.User) {
+	event := domain.UserEvent{
+		EventID:    domain.NewEventID(),
+		Type:       eventType,
+		UserID:     user.ID,
+		Username:   user.Username,
+		OccurredAt: time.Now().UTC(),
+	}
+	if err := s.userEventPub.PublishUserEvent(ctx, event); err != nil {
+		// The account change has already been made; consumers catch up
+		// from the database state if the event is lost
+	}
+}
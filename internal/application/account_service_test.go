@@ -0,0 +1,228 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockUserEventPublisher) PublishUserEvent(ctx context.Context, event domain.UserEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+type accountTestMocks struct {
+	userRepo     *MockUserRepository
+	followRepo   *MockFollowRepository
+	tweetRepo    *MockTweetRepository
+	listRepo     *MockListRepository
+	timeline     *MockTimelineCache
+	listTimeline *MockTimelineCache
+	userEventPub *MockUserEventPublisher
+}
+
+func newAccountTestMocks() *accountTestMocks {
+	return &accountTestMocks{
+		userRepo:     new(MockUserRepository),
+		followRepo:   new(MockFollowRepository),
+		tweetRepo:    new(MockTweetRepository),
+		listRepo:     new(MockListRepository),
+		timeline:     new(MockTimelineCache),
+		listTimeline: new(MockTimelineCache),
+		userEventPub: new(MockUserEventPublisher),
+	}
+}
+
+func (m *accountTestMocks) newService() *AccountService {
+	return NewAccountService(m.userRepo, m.followRepo, m.tweetRepo, m.listRepo, m.timeline, m.listTimeline, m.userEventPub, 24*time.Hour)
+}
+
+func (m *accountTestMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.followRepo.AssertExpectations(t)
+	m.tweetRepo.AssertExpectations(t)
+	m.listRepo.AssertExpectations(t)
+	m.timeline.AssertExpectations(t)
+	m.listTimeline.AssertExpectations(t)
+	m.userEventPub.AssertExpectations(t)
+}
+
+func userEventOfType(eventType domain.UserEventType) interface{} {
+	return mock.MatchedBy(func(e domain.UserEvent) bool { return e.Type == eventType && e.UserID == 1 })
+}
+
+func TestAccountService_Deactivate(t *testing.T) {
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+	m.userRepo.On("SetDeactivatedAt", 1, mock.AnythingOfType("*time.Time")).Return(nil)
+	m.userEventPub.On("PublishUserEvent", mock.Anything, userEventOfType(domain.UserEventDeactivated)).Return(nil)
+
+	deletionAt, err := m.newService().Deactivate(context.Background(), 1)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), deletionAt, time.Minute)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_Deactivate_AlreadyDeactivated(t *testing.T) {
+	deactivatedAt := time.Now().Add(-time.Hour)
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, DeactivatedAt: &deactivatedAt}, nil)
+
+	deletionAt, err := m.newService().Deactivate(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, deactivatedAt.Add(24*time.Hour), deletionAt)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_Reactivate(t *testing.T) {
+	deactivatedAt := time.Now().Add(-time.Hour)
+
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, DeactivatedAt: &deactivatedAt}, nil)
+	m.userRepo.On("SetDeactivatedAt", 1, (*time.Time)(nil)).Return(nil)
+	m.userEventPub.On("PublishUserEvent", mock.Anything, userEventOfType(domain.UserEventReactivated)).Return(nil)
+
+	require.NoError(t, m.newService().Reactivate(context.Background(), 1))
+	m.assertExpectations(t)
+
+	m = newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1}, nil)
+
+	err := m.newService().Reactivate(context.Background(), 1)
+	assert.IsType(t, &ErrInvalidInput{}, err)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_PurgeUser(t *testing.T) {
+	cutoff := time.Now().Add(-24 * time.Hour)
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+	m.tweetRepo.On("GetTweetIDsByUser", 1).Return([]int64{10, 11}, nil)
+	m.followRepo.On("GetFollowers", 1).Return([]int{2, 3}, nil)
+	m.timeline.On("RemoveFromTimelines", []int{2, 3}, []int64{10, 11}).Return(nil)
+	m.listRepo.On("GetListIDsByMember", 1).Return([]int{7}, nil)
+	m.listTimeline.On("RemoveFromTimelines", []int{7}, []int64{10, 11}).Return(nil)
+	m.listRepo.On("GetByOwnerID", 1).Return([]*domain.List{{ID: 8, OwnerID: 1}}, nil)
+	m.listTimeline.On("ClearTimeline", 8).Return(nil)
+	m.timeline.On("ClearTimeline", 1).Return(nil)
+	m.userRepo.On("DeleteDeactivated", 1, cutoff).Return(true, nil)
+	m.userEventPub.On("PublishUserEvent", mock.Anything, userEventOfType(domain.UserEventDeleted)).Return(nil)
+
+	purged, err := m.newService().PurgeUser(context.Background(), 1, cutoff)
+	require.NoError(t, err)
+	assert.True(t, purged)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_PurgeUser_DeletesUserWhenCachePurgeFails(t *testing.T) {
+	cutoff := time.Now().Add(-24 * time.Hour)
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1}, nil)
+	m.tweetRepo.On("GetTweetIDsByUser", 1).Return([]int64{10}, nil)
+	m.followRepo.On("GetFollowers", 1).Return([]int{2}, nil)
+	m.timeline.On("RemoveFromTimelines", []int{2}, []int64{10}).Return(errors.New("redis down"))
+	// The list timelines are still cleaned after the home timelines fail
+	m.listRepo.On("GetListIDsByMember", 1).Return([]int{7}, nil)
+	m.listTimeline.On("RemoveFromTimelines", []int{7}, []int64{10}).Return(nil)
+	m.listRepo.On("GetByOwnerID", 1).Return([]*domain.List{}, nil)
+	m.timeline.On("ClearTimeline", 1).Return(errors.New("redis down"))
+	m.userRepo.On("DeleteDeactivated", 1, cutoff).Return(true, nil)
+	m.userEventPub.On("PublishUserEvent", mock.Anything, userEventOfType(domain.UserEventDeleted)).Return(nil)
+
+	purged, err := m.newService().PurgeUser(context.Background(), 1, cutoff)
+	require.NoError(t, err)
+	assert.True(t, purged)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_PurgeUser_KeepsUserWhenLookupFails(t *testing.T) {
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1}, nil)
+	m.tweetRepo.On("GetTweetIDsByUser", 1).Return(nil, errors.New("db error"))
+
+	_, err := m.newService().PurgeUser(context.Background(), 1, time.Now())
+	assert.Error(t, err)
+	m.userRepo.AssertNotCalled(t, "DeleteDeactivated", mock.Anything, mock.Anything)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_PurgeUser_SkipsReactivatedUser(t *testing.T) {
+	cutoff := time.Now().Add(-24 * time.Hour)
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1}, nil)
+	m.tweetRepo.On("GetTweetIDsByUser", 1).Return([]int64{10}, nil)
+	m.followRepo.On("GetFollowers", 1).Return([]int{2}, nil)
+	m.listRepo.On("GetListIDsByMember", 1).Return([]int{7}, nil)
+	m.listRepo.On("GetByOwnerID", 1).Return([]*domain.List{{ID: 8, OwnerID: 1}}, nil)
+	// The user reactivated after being selected for the purge
+	m.userRepo.On("DeleteDeactivated", 1, cutoff).Return(false, nil)
+
+	purged, err := m.newService().PurgeUser(context.Background(), 1, cutoff)
+	require.NoError(t, err)
+	assert.False(t, purged)
+	m.timeline.AssertNotCalled(t, "RemoveFromTimelines", mock.Anything, mock.Anything)
+	m.timeline.AssertNotCalled(t, "ClearTimeline", mock.Anything)
+	m.listTimeline.AssertNotCalled(t, "ClearTimeline", mock.Anything)
+	m.userEventPub.AssertNotCalled(t, "PublishUserEvent", mock.Anything, mock.Anything)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_PurgeDeactivated(t *testing.T) {
+	m := newAccountTestMocks()
+	// User 1 fails to purge and keeps being returned; user 2 is purged
+	m.userRepo.On("GetDeactivatedBefore", mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).Return([]int{1, 2}, nil).Once()
+	m.userRepo.On("GetDeactivatedBefore", mock.AnythingOfType("time.Time"), mock.AnythingOfType("int")).Return([]int{1}, nil).Once()
+	m.userRepo.On("GetByID", 1).Return(nil, errors.New("db error"))
+	m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2}, nil)
+	m.tweetRepo.On("GetTweetIDsByUser", 2).Return([]int64{}, nil)
+	m.followRepo.On("GetFollowers", 2).Return([]int{}, nil)
+	m.timeline.On("RemoveFromTimelines", []int{}, []int64{}).Return(nil)
+	m.listRepo.On("GetListIDsByMember", 2).Return([]int{}, nil)
+	m.listTimeline.On("RemoveFromTimelines", []int{}, []int64{}).Return(nil)
+	m.listRepo.On("GetByOwnerID", 2).Return([]*domain.List{}, nil)
+	m.timeline.On("ClearTimeline", 2).Return(nil)
+	m.userRepo.On("DeleteDeactivated", 2, mock.AnythingOfType("time.Time")).Return(true, nil)
+	m.userEventPub.On("PublishUserEvent", mock.Anything, mock.Anything).Return(nil)
+
+	purged, failed, err := m.newService().PurgeDeactivated(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, 1, failed)
+	m.assertExpectations(t)
+}
+
+func TestAccountService_Export(t *testing.T) {
+	m := newAccountTestMocks()
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+	m.tweetRepo.On("GetByUserID", int64(1)).Return([]*domain.Tweet{{ID: 10, UserID: 1, Content: "hi", EventID: "event-10"}, {ID: 11, UserID: 1}}, nil)
+	m.followRepo.On("GetFollowing", 1).Return([]int{2}, nil)
+	m.followRepo.On("GetFollowers", 1).Return([]int{3, 4}, nil)
+
+	var types []string
+	var records []ExportRecord
+	err := m.newService().Export(context.Background(), 1, func(record ExportRecord) error {
+		types = append(types, record.Type)
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		ExportRecordProfile,
+		ExportRecordTweet, ExportRecordTweet,
+		ExportRecordFollowing,
+		ExportRecordFollower, ExportRecordFollower,
+	}, types)
+	assert.Equal(t, ExportedTweet{ID: 10, Content: "hi"}, records[1].Data)
+	m.assertExpectations(t)
+}
@@ -0,0 +1,148 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+// AdminService runs maintenance tasks on demand and manages roles. Every
+// operation requires the admin role.
+type AdminService struct {
+	userRepo    repositories.UserRepository
+	suggestions *SuggestionService
+	accounts    *AccountService
+	deadLetters *DeadLetterService
+	policy      *Policy
+}
+
+func NewAdminService(
+	userRepo repositories.UserRepository,
+	suggestions *SuggestionService,
+	accounts *AccountService,
+	deadLetters *DeadLetterService,
+	policy *Policy,
+) *AdminService {
+	return &AdminService{
+		userRepo:    userRepo,
+		suggestions: suggestions,
+		accounts:    accounts,
+		deadLetters: deadLetters,
+		policy:      policy,
+	}
+}
+
+// RefreshSuggestions recomputes the follow suggestions of every user without
+// waiting for the next scheduled run.
+func (s *AdminService) RefreshSuggestions(ctx context.Context, actorID int) (refreshed int, failed int, err error) {
+	ctx, span := tracer.Start(ctx, "AdminService.RefreshSuggestions")
+	defer span.End()
+
+	if err := s.requireMaintenance(ctx, actorID); err != nil {
+		return 0, 0, err
+	}
+	return s.suggestions.RefreshAll(ctx)
+}
+
+// PurgeDeactivatedAccounts deletes the accounts whose grace period has
+// passed without waiting for the next scheduled run.
+func (s *AdminService) PurgeDeactivatedAccounts(ctx context.Context, actorID int) (purged int, failed int, err error) {
+	ctx, span := tracer.Start(ctx, "AdminService.PurgeDeactivatedAccounts")
+	defer span.End()
+
+	if err := s.requireMaintenance(ctx, actorID); err != nil {
+		return 0, 0, err
+	}
+	return s.accounts.PurgeDeactivated(ctx)
+}
+
+// ListDeadLetters returns the most recently failed dead letters matching filter
+func (s *AdminService) ListDeadLetters(ctx context.Context, actorID int, filter domain.DeadLetterFilter) ([]*domain.DeadLetter, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ListDeadLetters")
+	defer span.End()
+
+	if err := s.requireMaintenance(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return s.deadLetters.List(ctx, filter)
+}
+
+// GetDeadLetter returns a dead letter and its replays
+func (s *AdminService) GetDeadLetter(ctx context.Context, actorID int, id int64) (*domain.DeadLetter, []*domain.DeadLetterReplay, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.GetDeadLetter")
+	defer span.End()
+
+	if err := s.requireMaintenance(ctx, actorID); err != nil {
+		return nil, nil, err
+	}
+	return s.deadLetters.Get(ctx, id)
+}
+
+// ReplayDeadLetters sends the given dead letters back onto their original
+// topics, recording the admin as the one who replayed them.
+func (s *AdminService) ReplayDeadLetters(ctx context.Context, actorID int, ids []int64) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ReplayDeadLetters")
+	defer span.End()
+
+	if err := s.requireMaintenance(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return s.deadLetters.Replay(ctx, ids, replayedByUser(actorID))
+}
+
+// ReplayDeadLettersBetween replays every dead letter that failed in the time
+// range of filter, recording the admin as the one who replayed them.
+func (s *AdminService) ReplayDeadLettersBetween(ctx context.Context, actorID int, filter domain.DeadLetterFilter) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.ReplayDeadLettersBetween")
+	defer span.End()
+
+	if err := s.requireMaintenance(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return s.deadLetters.ReplayBetween(ctx, filter, replayedByUser(actorID))
+}
+
+// SetRole changes the role of a user. Admins cannot change their own role,
+// so there is always at least one admin left.
+func (s *AdminService) SetRole(ctx context.Context, actorID int, userID int, role domain.Role) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "AdminService.SetRole")
+	defer span.End()
+
+	if !role.Valid() {
+		return nil, NewErrInvalidInput("role must be user, moderator or admin")
+	}
+
+	actor, err := loadActor(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.policy.CanAssignRoles(actor) {
+		return nil, NewErrForbidden("admin role required")
+	}
+	if actorID == userID {
+		return nil, NewErrInvalidInput("cannot change your own role")
+	}
+
+	if err := s.userRepo.SetRole(ctx, userID, role); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+func (s *AdminService) requireMaintenance(ctx context.Context, actorID int) error {
+	actor, err := loadActor(ctx, s.userRepo, actorID)
+	if err != nil {
+		return err
+	}
+	if !s.policy.CanRunMaintenance(actor) {
+		return NewErrForbidden("admin role required")
+	}
+	return nil
+}
+
+func replayedByUser(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
@@ -0,0 +1,120 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminService_SetRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorID   int
+		userID    int
+		role      domain.Role
+		setup     func(*application.MockUserRepository)
+		expectErr any
+	}{
+		{
+			name:    "admin promotes a moderator",
+			actorID: 1,
+			userID:  2,
+			role:    domain.RoleModerator,
+			setup: func(users *application.MockUserRepository) {
+				users.On("SetRole", 2, domain.RoleModerator).Return(nil)
+				users.On("GetByID", 2).Return(&domain.User{ID: 2, Role: domain.RoleModerator}, nil)
+			},
+		},
+		{
+			name:      "moderators cannot assign roles",
+			actorID:   3,
+			userID:    2,
+			role:      domain.RoleAdmin,
+			setup:     func(users *application.MockUserRepository) {},
+			expectErr: new(*application.ErrForbidden),
+		},
+		{
+			name:      "admins cannot change their own role",
+			actorID:   1,
+			userID:    1,
+			role:      domain.RoleUser,
+			setup:     func(users *application.MockUserRepository) {},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+		{
+			name:      "unknown role",
+			actorID:   1,
+			userID:    2,
+			role:      "owner",
+			setup:     func(users *application.MockUserRepository) {},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := new(application.MockUserRepository)
+			users.On("GetByID", 1).Return(&domain.User{ID: 1, Role: domain.RoleAdmin}, nil).Maybe()
+			users.On("GetByID", 3).Return(&domain.User{ID: 3, Role: domain.RoleModerator}, nil).Maybe()
+			tt.setup(users)
+
+			service := application.NewAdminService(users, nil, nil, nil, application.NewPolicy())
+			user, err := service.SetRole(context.Background(), tt.actorID, tt.userID, tt.role)
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				users.AssertNotCalled(t, "SetRole", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.role, user.Role)
+			}
+		})
+	}
+}
+
+func TestAdminService_Maintenance_RequiresAdmin(t *testing.T) {
+	users := new(application.MockUserRepository)
+	users.On("GetByID", 3).Return(&domain.User{ID: 3, Role: domain.RoleModerator}, nil)
+
+	// The services behind the maintenance tasks are never reached
+	service := application.NewAdminService(users, nil, nil, nil, application.NewPolicy())
+
+	_, _, err := service.RefreshSuggestions(context.Background(), 3)
+	assert.ErrorAs(t, err, new(*application.ErrForbidden))
+
+	_, _, err = service.PurgeDeactivatedAccounts(context.Background(), 3)
+	assert.ErrorAs(t, err, new(*application.ErrForbidden))
+
+	_, err = service.ListDeadLetters(context.Background(), 3, domain.DeadLetterFilter{})
+	assert.ErrorAs(t, err, new(*application.ErrForbidden))
+
+	_, err = service.ReplayDeadLetters(context.Background(), 3, []int64{1})
+	assert.ErrorAs(t, err, new(*application.ErrForbidden))
+}
+
+func TestAdminService_ReplayDeadLetters_RecordsAdmin(t *testing.T) {
+	users := new(application.MockUserRepository)
+	users.On("GetByID", 1).Return(&domain.User{ID: 1, Role: domain.RoleAdmin}, nil)
+	repo := new(application.MockDeadLetterRepository)
+	repo.On("GetByID", int64(5)).Return(&domain.DeadLetter{ID: 5}, nil)
+	repo.On("RecordReplay", mock.Anything).Return(nil)
+	replayer := new(application.MockDeadLetterReplayer)
+	replayer.On("Replay", mock.Anything, mock.Anything).Return(nil)
+
+	deadLetters := application.NewDeadLetterService(repo, replayer)
+	service := application.NewAdminService(users, nil, nil, deadLetters, application.NewPolicy())
+
+	replayed, err := service.ReplayDeadLetters(context.Background(), 1, []int64{5})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5}, replayed)
+	repo.AssertCalled(t, "RecordReplay", mock.MatchedBy(func(r *domain.DeadLetterReplay) bool {
+		return r.DeadLetterID == 5 && r.ReplayedBy == "user:1"
+	}))
+}
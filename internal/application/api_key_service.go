@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const (
+	maxAPIKeyNameLength = 50
+	maxAPIKeysPerUser   = 10
+	// apiKeyDisplayLength is how much of a key is kept to identify it
+	apiKeyDisplayLength = len(domain.APIKeyPrefix) + 6
+	// lastUsedResolution limits how often using a key is written back
+	lastUsedResolution = time.Minute
+)
+
+type APIKeyService struct {
+	apiKeyRepo repositories.APIKeyRepository
+	userRepo   repositories.UserRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repositories.APIKeyRepository, userRepo repositories.UserRepository) *APIKeyService {
+	return &APIKeyService{apiKeyRepo: apiKeyRepo, userRepo: userRepo}
+}
+
+type CreateAPIKeyInput struct {
+	Name   string
+	Scopes []domain.APIKeyScope
+}
+
+// CreateAPIKey mints a key for userID. The returned secret is the key
+// itself; only its hash is stored, so it cannot be shown again.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID int, input CreateAPIKeyInput) (*domain.APIKey, string, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.CreateAPIKey")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", NewErrInvalidInput("name cannot be empty")
+	}
+	if len(name) > maxAPIKeyNameLength {
+		return nil, "", NewErrInvalidInput(fmt.Sprintf("name is too long (max %d characters)", maxAPIKeyNameLength))
+	}
+
+	if len(input.Scopes) == 0 {
+		return nil, "", NewErrInvalidInput("at least one scope is required")
+	}
+	var scopes []domain.APIKeyScope
+	for _, scope := range input.Scopes {
+		if !slices.Contains(domain.APIKeyScopes, scope) {
+			return nil, "", NewErrInvalidInput(fmt.Sprintf("invalid scope: %s", scope))
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	existing, err := s.apiKeyRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return nil, "", NewErrInvalidInput(fmt.Sprintf("cannot have more than %d API keys", maxAPIKeysPerUser))
+	}
+
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	secret := domain.APIKeyPrefix + token
+
+	key := &domain.APIKey{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  secret[:apiKeyDisplayLength],
+		KeyHash: hashSecret(secret),
+		Scopes:  scopes,
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return key, secret, nil
+}
+
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID int) ([]*domain.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.ListAPIKeys")
+	defer span.End()
+
+	return s.apiKeyRepo.GetByUserID(ctx, userID)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID int, keyID int64) error {
+	ctx, span := tracer.Start(ctx, "APIKeyService.RevokeAPIKey")
+	defer span.End()
+
+	return s.apiKeyRepo.Revoke(ctx, userID, keyID)
+}
+
+// AuthenticateAPIKey returns the key a secret belongs to, unless it has been
+// revoked or its account is deactivated.
+func (s *APIKeyService) AuthenticateAPIKey(ctx context.Context, secret string) (*domain.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyService.AuthenticateAPIKey")
+	defer span.End()
+
+	key, err := s.apiKeyRepo.GetByHash(ctx, hashSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, NewErrUnauthorized("API key has been revoked")
+	}
+
+	owner, err := s.userRepo.GetByID(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if owner.DeactivatedAt != nil {
+		return nil, NewErrUnauthorized("account is deactivated")
+	}
+
+	// Best effort: failing to record usage should not fail the request
+	now := time.Now().UTC()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		_ = s.apiKeyRepo.SetLastUsedAt(ctx, key.ID, now)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,164 @@
+package application_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     application.CreateAPIKeyInput
+		setupMock func(*application.MockAPIKeyRepository)
+		expectErr bool
+		expected  []domain.APIKeyScope
+	}{
+		{
+			name: "creates a key with deduplicated scopes",
+			input: application.CreateAPIKeyInput{
+				Name:   "scheduler",
+				Scopes: []domain.APIKeyScope{domain.APIKeyScopeTweetsWrite, domain.APIKeyScopeTweetsWrite, domain.APIKeyScopeTimelineRead},
+			},
+			setupMock: func(repo *application.MockAPIKeyRepository) {
+				repo.On("GetByUserID", 1).Return([]*domain.APIKey{}, nil)
+				repo.On("Create", mock.AnythingOfType("*domain.APIKey")).Return(nil)
+			},
+			expected: []domain.APIKeyScope{domain.APIKeyScopeTweetsWrite, domain.APIKeyScopeTimelineRead},
+		},
+		{
+			name:      "empty name",
+			input:     application.CreateAPIKeyInput{Name: " ", Scopes: []domain.APIKeyScope{domain.APIKeyScopeTweetsWrite}},
+			setupMock: func(repo *application.MockAPIKeyRepository) {},
+			expectErr: true,
+		},
+		{
+			name:      "no scopes",
+			input:     application.CreateAPIKeyInput{Name: "scheduler"},
+			setupMock: func(repo *application.MockAPIKeyRepository) {},
+			expectErr: true,
+		},
+		{
+			name:      "unknown scope",
+			input:     application.CreateAPIKeyInput{Name: "scheduler", Scopes: []domain.APIKeyScope{"admin"}},
+			setupMock: func(repo *application.MockAPIKeyRepository) {},
+			expectErr: true,
+		},
+		{
+			name:  "too many keys",
+			input: application.CreateAPIKeyInput{Name: "scheduler", Scopes: []domain.APIKeyScope{domain.APIKeyScopeTweetsWrite}},
+			setupMock: func(repo *application.MockAPIKeyRepository) {
+				repo.On("GetByUserID", 1).Return(make([]*domain.APIKey, 10), nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &application.MockAPIKeyRepository{}
+			tt.setupMock(repo)
+			service := application.NewAPIKeyService(repo, &application.MockUserRepository{})
+
+			key, secret, err := service.CreateAPIKey(context.Background(), 1, tt.input)
+
+			if tt.expectErr {
+				assert.IsType(t, &application.ErrInvalidInput{}, err)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(secret, domain.APIKeyPrefix))
+				assert.True(t, strings.HasPrefix(secret, key.Prefix))
+				assert.Equal(t, sha256Hex(secret), key.KeyHash)
+				assert.Equal(t, tt.expected, key.Scopes)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyService_AuthenticateAPIKey(t *testing.T) {
+	recently := time.Now().Add(-10 * time.Second)
+	revokedAt := time.Now().Add(-time.Hour)
+	active := &domain.User{ID: 1}
+
+	tests := []struct {
+		name      string
+		key       *domain.APIKey
+		owner     *domain.User
+		setupMock func(*application.MockAPIKeyRepository)
+		expectErr bool
+	}{
+		{
+			name:  "records first use",
+			key:   &domain.APIKey{ID: 3, UserID: 1},
+			owner: active,
+			setupMock: func(repo *application.MockAPIKeyRepository) {
+				repo.On("SetLastUsedAt", int64(3), mock.AnythingOfType("time.Time")).Return(nil)
+			},
+		},
+		{
+			name:      "recently used key is not written back",
+			key:       &domain.APIKey{ID: 3, UserID: 1, LastUsedAt: &recently},
+			owner:     active,
+			setupMock: func(repo *application.MockAPIKeyRepository) {},
+		},
+		{
+			name:  "failing to record use does not fail",
+			key:   &domain.APIKey{ID: 3, UserID: 1},
+			owner: active,
+			setupMock: func(repo *application.MockAPIKeyRepository) {
+				repo.On("SetLastUsedAt", int64(3), mock.AnythingOfType("time.Time")).Return(assert.AnError)
+			},
+		},
+		{
+			name:      "revoked key",
+			key:       &domain.APIKey{ID: 3, UserID: 1, RevokedAt: &revokedAt},
+			owner:     active,
+			setupMock: func(repo *application.MockAPIKeyRepository) {},
+			expectErr: true,
+		},
+		{
+			name:      "key of a deactivated account",
+			key:       &domain.APIKey{ID: 3, UserID: 1},
+			owner:     &domain.User{ID: 1, DeactivatedAt: &revokedAt},
+			setupMock: func(repo *application.MockAPIKeyRepository) {},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &application.MockAPIKeyRepository{}
+			repo.On("GetByHash", sha256Hex("uala_secret")).Return(tt.key, nil)
+			tt.setupMock(repo)
+			users := &application.MockUserRepository{}
+			users.On("GetByID", 1).Return(tt.owner, nil).Maybe()
+			service := application.NewAPIKeyService(repo, users)
+
+			key, err := service.AuthenticateAPIKey(context.Background(), "uala_secret")
+
+			if tt.expectErr {
+				assert.IsType(t, &application.ErrUnauthorized{}, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.key, key)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
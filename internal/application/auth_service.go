@@ -0,0 +1,272 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/auth"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt ignores everything past 72 bytes
+	maxPasswordLength = 72
+	// dummyPassword is hashed to have something to compare against when a
+	// login has no stored hash
+	dummyPassword = "uala-tweets dummy password"
+)
+
+type AuthService struct {
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	hasher           auth.PasswordHasher
+	tokens           auth.TokenIssuer
+	refreshTokenTTL  time.Duration
+
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewAuthService(userRepo repositories.UserRepository, refreshTokenRepo repositories.RefreshTokenRepository, hasher auth.PasswordHasher, tokens auth.TokenIssuer, refreshTokenTTL time.Duration) *AuthService {
+	return &AuthService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		hasher:           hasher,
+		tokens:           tokens,
+		refreshTokenTTL:  refreshTokenTTL,
+	}
+}
+
+type RegisterInput struct {
+	Username string
+	Password string
+}
+
+// TokenPair is what a client receives after logging in: a short-lived
+// access token for the Authorization header and a refresh token to get
+// the next pair.
+type TokenPair struct {
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+// Register creates a user with a hashed password and logs them in.
+func (s *AuthService) Register(ctx context.Context, input RegisterInput) (*domain.User, *TokenPair, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Register")
+	defer span.End()
+
+	if !usernamePattern.MatchString(input.Username) {
+		return nil, nil, NewErrInvalidInput("username must be 3-50 letters, digits or underscores")
+	}
+	if err := validatePassword(input.Password); err != nil {
+		return nil, nil, err
+	}
+
+	hash, err := s.hasher.Hash(input.Password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now().UTC()
+	user := &domain.User{
+		Username:     input.Username,
+		PasswordHash: hash,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	if user.ID == 0 {
+		return nil, nil, errors.New("user created but ID not set")
+	}
+
+	tokens, err := s.issueTokens(ctx, user.ID, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// Login checks a username and password and starts a new refresh token
+// family. Deactivated accounts may log in so they can reactivate.
+func (s *AuthService) Login(ctx context.Context, username, password string) (*TokenPair, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer span.End()
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.As(err, new(*ErrUsernameNotFound)) {
+			s.compareDummyHash(password)
+			return nil, NewErrInvalidCredentials()
+		}
+		return nil, err
+	}
+
+	// Old usernames resolve to their new owner, but only the current one
+	// logs in; accounts created before passwords existed have no hash.
+	if user.Username != username || user.PasswordHash == "" {
+		s.compareDummyHash(password)
+		return nil, NewErrInvalidCredentials()
+	}
+	if err := s.hasher.Compare(user.PasswordHash, password); err != nil {
+		return nil, NewErrInvalidCredentials()
+	}
+
+	return s.issueTokens(ctx, user.ID, "")
+}
+
+// Refresh trades a refresh token for a new token pair. The presented token
+// is revoked; presenting it again revokes every token of its family, since
+// either the client or an attacker holds a stolen copy.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Refresh")
+	defer span.End()
+
+	token, err := s.refreshTokenRepo.GetByHash(ctx, hashSecret(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RevokedAt != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, NewErrUnauthorized("refresh token has been revoked")
+	}
+	if !time.Now().Before(token.ExpiresAt) {
+		return nil, NewErrUnauthorized("refresh token has expired")
+	}
+
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		// Lost a race with another refresh of the same token
+		if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, NewErrUnauthorized("refresh token has been revoked")
+	}
+
+	return s.issueTokens(ctx, token.UserID, token.FamilyID)
+}
+
+// Logout revokes the refresh token family the token belongs to. Access
+// tokens already issued stay valid until they expire.
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	ctx, span := tracer.Start(ctx, "AuthService.Logout")
+	defer span.End()
+
+	token, err := s.refreshTokenRepo.GetByHash(ctx, hashSecret(refreshToken))
+	if err != nil {
+		return err
+	}
+	return s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
+}
+
+// Authenticate returns the user an access token was issued to. Tokens of
+// deleted accounts are rejected; deactivated accounts are returned as they
+// are, so callers can still let them reactivate.
+func (s *AuthService) Authenticate(ctx context.Context, accessToken string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Authenticate")
+	defer span.End()
+
+	userID, err := s.tokens.Parse(accessToken)
+	if err != nil {
+		return nil, NewErrUnauthorized("invalid or expired access token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.As(err, new(*ErrUserNotFound)) {
+			return nil, NewErrUnauthorized("invalid or expired access token")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// compareDummyHash checks password against a throwaway hash, whatever the
+// outcome. Failed logins without a stored hash to check then take as long
+// as those with one, so response times do not tell which usernames exist.
+func (s *AuthService) compareDummyHash(password string) {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = s.hasher.Hash(dummyPassword)
+	})
+	_ = s.hasher.Compare(s.dummyHash, password)
+}
+
+// issueTokens creates an access token and a refresh token in familyID, or in
+// a new family when familyID is empty.
+func (s *AuthService) issueTokens(ctx context.Context, userID int, familyID string) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := s.tokens.Issue(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if familyID == "" {
+		familyID, err = randomToken(16)
+		if err != nil {
+			return nil, err
+		}
+	}
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashSecret(refreshToken),
+		ExpiresAt: time.Now().UTC().Add(s.refreshTokenTTL),
+	}
+	if err := s.refreshTokenRepo.Create(ctx, stored); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return NewErrInvalidInput(fmt.Sprintf("password is too short (min %d characters)", minPasswordLength))
+	}
+	if len(password) > maxPasswordLength {
+		return NewErrInvalidInput(fmt.Sprintf("password is too long (max %d bytes)", maxPasswordLength))
+	}
+	return nil
+}
+
+func randomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret returns the SHA-256 stored in place of a refresh token or an
+// API key secret. Both are random, so a fast unsalted hash is enough.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,283 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+type authMocks struct {
+	userRepo   *application.MockUserRepository
+	tokenRepo  *application.MockRefreshTokenRepository
+	hasher     *application.MockPasswordHasher
+	tokens     *application.MockTokenIssuer
+	accessTime time.Time
+}
+
+func newAuthMocks() *authMocks {
+	return &authMocks{
+		userRepo:   &application.MockUserRepository{},
+		tokenRepo:  &application.MockRefreshTokenRepository{},
+		hasher:     &application.MockPasswordHasher{},
+		tokens:     &application.MockTokenIssuer{},
+		accessTime: time.Now().Add(15 * time.Minute),
+	}
+}
+
+func (m *authMocks) service() *application.AuthService {
+	return application.NewAuthService(m.userRepo, m.tokenRepo, m.hasher, m.tokens, 24*time.Hour)
+}
+
+func (m *authMocks) expectTokens(userID int, familyID string) {
+	m.tokens.On("Issue", userID).Return("access-token", m.accessTime, nil).Once()
+	m.tokenRepo.On("Create", mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.UserID == userID &&
+			(familyID == "" || token.FamilyID == familyID) &&
+			len(token.TokenHash) == 64
+	})).Return(nil).Once()
+}
+
+// expectDummyCompare expects a login without a stored hash to still spend
+// a password comparison
+func (m *authMocks) expectDummyCompare(password string) {
+	m.hasher.On("Hash", mock.AnythingOfType("string")).Return("dummy-hash", nil).Once()
+	m.hasher.On("Compare", "dummy-hash", password).Return(errors.New("mismatch")).Once()
+}
+
+func (m *authMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.tokenRepo.AssertExpectations(t)
+	m.hasher.AssertExpectations(t)
+	m.tokens.AssertExpectations(t)
+}
+
+func TestAuthService_Register(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     application.RegisterInput
+		setup     func(*authMocks)
+		expectErr any
+	}{
+		{
+			name:  "registers and logs in",
+			input: application.RegisterInput{Username: "johndoe", Password: "correct horse"},
+			setup: func(m *authMocks) {
+				m.hasher.On("Hash", "correct horse").Return("hashed", nil)
+				m.userRepo.On("Create", mock.MatchedBy(func(user *domain.User) bool {
+					user.ID = 7
+					return user.Username == "johndoe" && user.PasswordHash == "hashed"
+				})).Return(nil)
+				m.expectTokens(7, "")
+			},
+		},
+		{
+			name:      "invalid username",
+			input:     application.RegisterInput{Username: "john doe", Password: "correct horse"},
+			setup:     func(m *authMocks) {},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+		{
+			name:      "short password",
+			input:     application.RegisterInput{Username: "johndoe", Password: "short"},
+			setup:     func(m *authMocks) {},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+		{
+			name:  "username taken",
+			input: application.RegisterInput{Username: "johndoe", Password: "correct horse"},
+			setup: func(m *authMocks) {
+				m.hasher.On("Hash", "correct horse").Return("hashed", nil)
+				m.userRepo.On("Create", mock.Anything).Return(application.NewErrUserAlreadyExists("johndoe"))
+			},
+			expectErr: new(*application.ErrUserAlreadyExists),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newAuthMocks()
+			tt.setup(m)
+
+			user, tokens, err := m.service().Register(context.Background(), tt.input)
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, 7, user.ID)
+				assert.Equal(t, "access-token", tokens.AccessToken)
+				assert.NotEmpty(t, tokens.RefreshToken)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Login(t *testing.T) {
+	user := &domain.User{ID: 7, Username: "johndoe", PasswordHash: "hashed"}
+
+	tests := []struct {
+		name      string
+		username  string
+		password  string
+		setup     func(*authMocks)
+		expectErr bool
+	}{
+		{
+			name:     "valid credentials",
+			username: "johndoe",
+			password: "correct horse",
+			setup: func(m *authMocks) {
+				m.userRepo.On("GetByUsername", "johndoe").Return(user, nil)
+				m.hasher.On("Compare", "hashed", "correct horse").Return(nil)
+				m.expectTokens(7, "")
+			},
+		},
+		{
+			name:     "wrong password",
+			username: "johndoe",
+			password: "wrong",
+			setup: func(m *authMocks) {
+				m.userRepo.On("GetByUsername", "johndoe").Return(user, nil)
+				m.hasher.On("Compare", "hashed", "wrong").Return(errors.New("mismatch"))
+			},
+			expectErr: true,
+		},
+		{
+			name:     "unknown user",
+			username: "nobody",
+			password: "correct horse",
+			setup: func(m *authMocks) {
+				m.userRepo.On("GetByUsername", "nobody").Return(nil, application.NewErrUsernameNotFound("nobody"))
+				m.expectDummyCompare("correct horse")
+			},
+			expectErr: true,
+		},
+		{
+			name:     "previous username",
+			username: "oldname",
+			password: "correct horse",
+			setup: func(m *authMocks) {
+				m.userRepo.On("GetByUsername", "oldname").Return(user, nil)
+				m.expectDummyCompare("correct horse")
+			},
+			expectErr: true,
+		},
+		{
+			name:     "account without password",
+			username: "legacy",
+			password: "correct horse",
+			setup: func(m *authMocks) {
+				m.userRepo.On("GetByUsername", "legacy").Return(&domain.User{ID: 8, Username: "legacy"}, nil)
+				m.expectDummyCompare("correct horse")
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newAuthMocks()
+			tt.setup(m)
+
+			tokens, err := m.service().Login(context.Background(), tt.username, tt.password)
+
+			if tt.expectErr {
+				assert.IsType(t, &application.ErrInvalidCredentials{}, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "access-token", tokens.AccessToken)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		token     *domain.RefreshToken
+		setup     func(*authMocks)
+		expectErr bool
+	}{
+		{
+			name:  "rotates the token",
+			token: &domain.RefreshToken{ID: 1, UserID: 7, FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour)},
+			setup: func(m *authMocks) {
+				m.tokenRepo.On("Revoke", int64(1)).Return(true, nil)
+				m.expectTokens(7, "fam")
+			},
+		},
+		{
+			name:  "reused token revokes the family",
+			token: &domain.RefreshToken{ID: 1, UserID: 7, FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt},
+			setup: func(m *authMocks) {
+				m.tokenRepo.On("RevokeFamily", "fam").Return(nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:  "concurrent refresh revokes the family",
+			token: &domain.RefreshToken{ID: 1, UserID: 7, FamilyID: "fam", ExpiresAt: time.Now().Add(time.Hour)},
+			setup: func(m *authMocks) {
+				m.tokenRepo.On("Revoke", int64(1)).Return(false, nil)
+				m.tokenRepo.On("RevokeFamily", "fam").Return(nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:      "expired token",
+			token:     &domain.RefreshToken{ID: 1, UserID: 7, FamilyID: "fam", ExpiresAt: time.Now().Add(-time.Second)},
+			setup:     func(m *authMocks) {},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newAuthMocks()
+			m.tokenRepo.On("GetByHash", mock.Anything).Return(tt.token, nil)
+			tt.setup(m)
+
+			tokens, err := m.service().Refresh(context.Background(), "refresh-token")
+
+			if tt.expectErr {
+				assert.IsType(t, &application.ErrUnauthorized{}, err)
+			} else {
+				require.NoError(t, err)
+				assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestAuthService_Authenticate(t *testing.T) {
+	m := newAuthMocks()
+	m.tokens.On("Parse", "good").Return(7, nil)
+	m.tokens.On("Parse", "deleted").Return(8, nil)
+	m.tokens.On("Parse", "bad").Return(0, errors.New("signature is invalid"))
+	m.userRepo.On("GetByID", 7).Return(&domain.User{ID: 7}, nil)
+	m.userRepo.On("GetByID", 8).Return(nil, application.NewErrUserNotFound(8))
+	service := m.service()
+
+	user, err := service.Authenticate(context.Background(), "good")
+	require.NoError(t, err)
+	assert.Equal(t, 7, user.ID)
+
+	_, err = service.Authenticate(context.Background(), "deleted")
+	assert.IsType(t, &application.ErrUnauthorized{}, err)
+
+	_, err = service.Authenticate(context.Background(), "bad")
+	assert.IsType(t, &application.ErrUnauthorized{}, err)
+}
@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/publishers"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const (
+	DefaultDeadLetterLimit = 50
+	MaxDeadLetterLimit     = 500
+)
+
+// DeadLetterService lets operators look into the messages consumers gave up
+// on and, once the cause is fixed, replay them onto their original topics.
+// Every replay is recorded with who asked for it.
+type DeadLetterService struct {
+	repo     repositories.DeadLetterRepository
+	replayer publishers.DeadLetterReplayer
+}
+
+func NewDeadLetterService(repo repositories.DeadLetterRepository, replayer publishers.DeadLetterReplayer) *DeadLetterService {
+	return &DeadLetterService{
+		repo:     repo,
+		replayer: replayer,
+	}
+}
+
+// List returns the most recently failed dead letters matching filter
+func (s *DeadLetterService) List(ctx context.Context, filter domain.DeadLetterFilter) ([]*domain.DeadLetter, error) {
+	ctx, span := tracer.Start(ctx, "DeadLetterService.List")
+	defer span.End()
+
+	if err := validateDeadLetterRange(filter); err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultDeadLetterLimit
+	}
+	if filter.Limit > MaxDeadLetterLimit {
+		filter.Limit = MaxDeadLetterLimit
+	}
+	return s.repo.List(ctx, filter)
+}
+
+// Get returns a dead letter and its replays
+func (s *DeadLetterService) Get(ctx context.Context, id int64) (*domain.DeadLetter, []*domain.DeadLetterReplay, error) {
+	ctx, span := tracer.Start(ctx, "DeadLetterService.Get")
+	defer span.End()
+
+	deadLetter, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replays, err := s.repo.GetReplays(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return deadLetter, replays, nil
+}
+
+// Replay sends the given dead letters back onto their original topics. They
+// must all exist, otherwise none is replayed. It returns the IDs replayed,
+// which on an error are the ones replayed before it.
+func (s *DeadLetterService) Replay(ctx context.Context, ids []int64, replayedBy string) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "DeadLetterService.Replay")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, NewErrInvalidInput("no dead letters to replay")
+	}
+	if len(ids) > MaxDeadLetterLimit {
+		return nil, NewErrInvalidInput("too many dead letters to replay at once")
+	}
+
+	deadLetters := make([]*domain.DeadLetter, 0, len(ids))
+	for _, id := range ids {
+		deadLetter, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+
+	return s.replay(ctx, deadLetters, replayedBy)
+}
+
+// ReplayBetween replays every dead letter that failed in the time range of
+// filter, optionally only those of a consumer or topic. Both ends of the
+// range are required so a replay never covers more than intended.
+func (s *DeadLetterService) ReplayBetween(ctx context.Context, filter domain.DeadLetterFilter, replayedBy string) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "DeadLetterService.ReplayBetween")
+	defer span.End()
+
+	if filter.From.IsZero() || filter.To.IsZero() {
+		return nil, NewErrInvalidInput("from and to are required")
+	}
+	if err := validateDeadLetterRange(filter); err != nil {
+		return nil, err
+	}
+
+	filter.Limit = 0
+	deadLetters, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replay in the order the messages failed
+	slices.Reverse(deadLetters)
+	return s.replay(ctx, deadLetters, replayedBy)
+}
+
+func (s *DeadLetterService) replay(ctx context.Context, deadLetters []*domain.DeadLetter, replayedBy string) ([]int64, error) {
+	replayed := make([]int64, 0, len(deadLetters))
+	for _, deadLetter := range deadLetters {
+		if err := s.replayer.Replay(ctx, deadLetter); err != nil {
+			return replayed, err
+		}
+
+		replay := &domain.DeadLetterReplay{DeadLetterID: deadLetter.ID, ReplayedBy: replayedBy}
+		if err := s.repo.RecordReplay(ctx, replay); err != nil {
+			slog.ErrorContext(ctx, "Error recording replay of dead letter", "dead_letter_id", deadLetter.ID, "replayed_by", replayedBy, "error", err)
+			return replayed, err
+		}
+		replayed = append(replayed, deadLetter.ID)
+	}
+
+	slog.InfoContext(ctx, "Replayed dead letters", "replayed_by", replayedBy, "dead_letters", len(replayed))
+	return replayed, nil
+}
+
+func validateDeadLetterRange(filter domain.DeadLetterFilter) error {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return NewErrInvalidInput("from must be before to")
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadLetterService_List(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		filter    domain.DeadLetterFilter
+		wantLimit int
+		expectErr any
+	}{
+		{
+			name:      "defaults the limit",
+			filter:    domain.DeadLetterFilter{Consumer: "tweet-consumer"},
+			wantLimit: application.DefaultDeadLetterLimit,
+		},
+		{
+			name:      "caps the limit",
+			filter:    domain.DeadLetterFilter{Limit: 10000},
+			wantLimit: application.MaxDeadLetterLimit,
+		},
+		{
+			name:      "from after to",
+			filter:    domain.DeadLetterFilter{From: now, To: now.Add(-time.Hour)},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(application.MockDeadLetterRepository)
+			repo.On("List", mock.Anything).Return([]*domain.DeadLetter{}, nil)
+
+			service := application.NewDeadLetterService(repo, new(application.MockDeadLetterReplayer))
+			_, err := service.List(context.Background(), tt.filter)
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				repo.AssertNotCalled(t, "List", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			repo.AssertCalled(t, "List", mock.MatchedBy(func(f domain.DeadLetterFilter) bool {
+				return f.Limit == tt.wantLimit && f.Consumer == tt.filter.Consumer
+			}))
+		})
+	}
+}
+
+func TestDeadLetterService_Replay(t *testing.T) {
+	tests := []struct {
+		name         string
+		ids          []int64
+		setup        func(*application.MockDeadLetterRepository, *application.MockDeadLetterReplayer)
+		wantReplayed []int64
+		expectErr    any
+	}{
+		{
+			name: "replays and records each dead letter",
+			ids:  []int64{1, 2},
+			setup: func(repo *application.MockDeadLetterRepository, replayer *application.MockDeadLetterReplayer) {
+				repo.On("GetByID", int64(1)).Return(&domain.DeadLetter{ID: 1, Topic: "tweets.created"}, nil)
+				repo.On("GetByID", int64(2)).Return(&domain.DeadLetter{ID: 2, Topic: "timeline.fanout"}, nil)
+				replayer.On("Replay", mock.Anything, mock.Anything).Return(nil)
+				repo.On("RecordReplay", mock.MatchedBy(func(r *domain.DeadLetterReplay) bool {
+					return r.ReplayedBy == "cli:ops"
+				})).Return(nil)
+			},
+			wantReplayed: []int64{1, 2},
+		},
+		{
+			name: "unknown dead letter replays nothing",
+			ids:  []int64{1, 99},
+			setup: func(repo *application.MockDeadLetterRepository, replayer *application.MockDeadLetterReplayer) {
+				repo.On("GetByID", int64(1)).Return(&domain.DeadLetter{ID: 1}, nil)
+				repo.On("GetByID", int64(99)).Return(nil, application.NewErrDeadLetterNotFound(99))
+			},
+			expectErr: new(*application.ErrDeadLetterNotFound),
+		},
+		{
+			name: "publish failure stops the replay",
+			ids:  []int64{1, 2},
+			setup: func(repo *application.MockDeadLetterRepository, replayer *application.MockDeadLetterReplayer) {
+				first, second := &domain.DeadLetter{ID: 1}, &domain.DeadLetter{ID: 2}
+				repo.On("GetByID", int64(1)).Return(first, nil)
+				repo.On("GetByID", int64(2)).Return(second, nil)
+				replayer.On("Replay", mock.Anything, first).Return(nil)
+				replayer.On("Replay", mock.Anything, second).Return(errors.New("kafka down"))
+				repo.On("RecordReplay", mock.Anything).Return(nil)
+			},
+			wantReplayed: []int64{1},
+			expectErr:    new(error),
+		},
+		{
+			name:      "nothing to replay",
+			setup:     func(*application.MockDeadLetterRepository, *application.MockDeadLetterReplayer) {},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(application.MockDeadLetterRepository)
+			replayer := new(application.MockDeadLetterReplayer)
+			tt.setup(repo, replayer)
+
+			service := application.NewDeadLetterService(repo, replayer)
+			replayed, err := service.Replay(context.Background(), tt.ids, "cli:ops")
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, len(tt.wantReplayed), len(replayed))
+			for i, id := range tt.wantReplayed {
+				assert.Equal(t, id, replayed[i])
+			}
+			if len(tt.wantReplayed) == 0 {
+				replayer.AssertNotCalled(t, "Replay", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeadLetterService_ReplayBetween(t *testing.T) {
+	from := time.Now().Add(-time.Hour)
+	to := time.Now()
+
+	t.Run("replays oldest failure first", func(t *testing.T) {
+		repo := new(application.MockDeadLetterRepository)
+		replayer := new(application.MockDeadLetterReplayer)
+		repo.On("List", domain.DeadLetterFilter{Topic: "tweets.created", From: from, To: to}).
+			Return([]*domain.DeadLetter{{ID: 7}, {ID: 3}}, nil)
+		replayer.On("Replay", mock.Anything, mock.Anything).Return(nil)
+		repo.On("RecordReplay", mock.Anything).Return(nil)
+
+		service := application.NewDeadLetterService(repo, replayer)
+		replayed, err := service.ReplayBetween(context.Background(), domain.DeadLetterFilter{Topic: "tweets.created", From: from, To: to, Limit: 5}, "user:1")
+
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 7}, replayed)
+	})
+
+	t.Run("requires both ends of the range", func(t *testing.T) {
+		repo := new(application.MockDeadLetterRepository)
+		service := application.NewDeadLetterService(repo, new(application.MockDeadLetterReplayer))
+
+		_, err := service.ReplayBetween(context.Background(), domain.DeadLetterFilter{From: from}, "user:1")
+
+		assert.ErrorAs(t, err, new(*application.ErrInvalidInput))
+		repo.AssertNotCalled(t, "List", mock.Anything)
+	})
+}
@@ -11,6 +11,10 @@ type (
 		Username string
 	}
 
+	ErrUsernameNotFound struct {
+		Username string
+	}
+
 	ErrAlreadyFollowing struct {
 		FollowerID int
 		FollowedID int
@@ -24,12 +28,84 @@ type (
 	ErrInvalidInput struct {
 		Message string
 	}
+
+	ErrMuteNotFound struct {
+		MuteID int64
+	}
+
+	ErrAlreadyMuted struct {
+		UserID int
+		Target string
+	}
+
+	ErrFollowRequestAlreadyExists struct {
+		RequesterID int
+		TargetID    int
+	}
+
+	ErrFollowRequestNotFound struct {
+		RequesterID int
+		TargetID    int
+	}
+
+	ErrProtectedAccount struct {
+		UserID int
+	}
+
+	ErrListNotFound struct {
+		ListID int
+	}
+
+	ErrAlreadyListMember struct {
+		ListID int
+		UserID int
+	}
+
+	ErrNotListMember struct {
+		ListID int
+		UserID int
+	}
+
+	ErrInvalidCredentials struct{}
+
+	ErrUnauthorized struct {
+		Message string
+	}
+
+	ErrAPIKeyNotFound struct {
+		KeyID int64
+	}
+
+	ErrForbidden struct {
+		Message string
+	}
+
+	ErrTweetNotFound struct {
+		TweetID int64
+	}
+
+	ErrReportNotFound struct {
+		ReportID int64
+	}
+
+	ErrAlreadyReported struct {
+		TweetID    int64
+		ReporterID int
+	}
+
+	ErrDeadLetterNotFound struct {
+		DeadLetterID int64
+	}
 )
 
 func (e ErrUserNotFound) Error() string {
 	return fmt.Sprintf("user not found with id: %d", e.UserID)
 }
 
+func (e ErrUsernameNotFound) Error() string {
+	return fmt.Sprintf("user not found with username: %s", e.Username)
+}
+
 func (e ErrUserAlreadyExists) Error() string {
 	return fmt.Sprintf("user with username '%s' already exists", e.Username)
 }
@@ -50,6 +126,10 @@ func NewErrUserAlreadyExists(username string) error {
 	return &ErrUserAlreadyExists{Username: username}
 }
 
+func NewErrUsernameNotFound(username string) error {
+	return &ErrUsernameNotFound{Username: username}
+}
+
 func NewErrAlreadyFollowing(followerID, followedID int) error {
 	return &ErrAlreadyFollowing{
 		FollowerID: followerID,
@@ -71,3 +151,149 @@ func (e ErrInvalidInput) Error() string {
 func NewErrInvalidInput(message string) error {
 	return &ErrInvalidInput{Message: message}
 }
+
+func (e ErrMuteNotFound) Error() string {
+	return fmt.Sprintf("mute not found with id: %d", e.MuteID)
+}
+
+func (e ErrAlreadyMuted) Error() string {
+	return fmt.Sprintf("user %d has already muted %s", e.UserID, e.Target)
+}
+
+func NewErrMuteNotFound(muteID int64) error {
+	return &ErrMuteNotFound{MuteID: muteID}
+}
+
+func NewErrAlreadyMuted(userID int, target string) error {
+	return &ErrAlreadyMuted{
+		UserID: userID,
+		Target: target,
+	}
+}
+
+func (e ErrFollowRequestAlreadyExists) Error() string {
+	return fmt.Sprintf("user %d has already requested to follow user %d", e.RequesterID, e.TargetID)
+}
+
+func (e ErrFollowRequestNotFound) Error() string {
+	return fmt.Sprintf("no pending follow request from user %d to user %d", e.RequesterID, e.TargetID)
+}
+
+func (e ErrProtectedAccount) Error() string {
+	return fmt.Sprintf("tweets of user %d are protected", e.UserID)
+}
+
+func (e ErrListNotFound) Error() string {
+	return fmt.Sprintf("list not found with id: %d", e.ListID)
+}
+
+func (e ErrAlreadyListMember) Error() string {
+	return fmt.Sprintf("user %d is already a member of list %d", e.UserID, e.ListID)
+}
+
+func (e ErrNotListMember) Error() string {
+	return fmt.Sprintf("user %d is not a member of list %d", e.UserID, e.ListID)
+}
+
+func NewErrFollowRequestAlreadyExists(requesterID, targetID int) error {
+	return &ErrFollowRequestAlreadyExists{
+		RequesterID: requesterID,
+		TargetID:    targetID,
+	}
+}
+
+func NewErrFollowRequestNotFound(requesterID, targetID int) error {
+	return &ErrFollowRequestNotFound{
+		RequesterID: requesterID,
+		TargetID:    targetID,
+	}
+}
+
+func NewErrProtectedAccount(userID int) error {
+	return &ErrProtectedAccount{UserID: userID}
+}
+
+func NewErrListNotFound(listID int) error {
+	return &ErrListNotFound{ListID: listID}
+}
+
+func NewErrAlreadyListMember(listID, userID int) error {
+	return &ErrAlreadyListMember{
+		ListID: listID,
+		UserID: userID,
+	}
+}
+
+func NewErrNotListMember(listID, userID int) error {
+	return &ErrNotListMember{
+		ListID: listID,
+		UserID: userID,
+	}
+}
+
+func (e ErrInvalidCredentials) Error() string {
+	return "invalid username or password"
+}
+
+func (e ErrUnauthorized) Error() string {
+	return e.Message
+}
+
+func NewErrInvalidCredentials() error {
+	return &ErrInvalidCredentials{}
+}
+
+func NewErrUnauthorized(message string) error {
+	return &ErrUnauthorized{Message: message}
+}
+
+func (e ErrAPIKeyNotFound) Error() string {
+	return fmt.Sprintf("API key not found with id: %d", e.KeyID)
+}
+
+func NewErrAPIKeyNotFound(keyID int64) error {
+	return &ErrAPIKeyNotFound{KeyID: keyID}
+}
+
+func (e ErrForbidden) Error() string {
+	return e.Message
+}
+
+func (e ErrTweetNotFound) Error() string {
+	return fmt.Sprintf("tweet not found with id: %d", e.TweetID)
+}
+
+func (e ErrReportNotFound) Error() string {
+	return fmt.Sprintf("open report not found with id: %d", e.ReportID)
+}
+
+func (e ErrAlreadyReported) Error() string {
+	return fmt.Sprintf("user %d has already reported tweet %d", e.ReporterID, e.TweetID)
+}
+
+func (e ErrDeadLetterNotFound) Error() string {
+	return fmt.Sprintf("dead letter not found with id: %d", e.DeadLetterID)
+}
+
+func NewErrForbidden(message string) error {
+	return &ErrForbidden{Message: message}
+}
+
+func NewErrTweetNotFound(tweetID int64) error {
+	return &ErrTweetNotFound{TweetID: tweetID}
+}
+
+func NewErrReportNotFound(reportID int64) error {
+	return &ErrReportNotFound{ReportID: reportID}
+}
+
+func NewErrAlreadyReported(tweetID int64, reporterID int) error {
+	return &ErrAlreadyReported{
+		TweetID:    tweetID,
+		ReporterID: reporterID,
+	}
+}
+
+func NewErrDeadLetterNotFound(deadLetterID int64) error {
+	return &ErrDeadLetterNotFound{DeadLetterID: deadLetterID}
+}
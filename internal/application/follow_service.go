@@ -1,78 +1,100 @@
 package application
 
 import (
+	"context"
 	"uala-tweets/internal/domain"
 	"uala-tweets/internal/ports/publishers"
 	"uala-tweets/internal/ports/repositories"
 )
 
+// FollowStatus describes the outcome of a follow attempt
+type FollowStatus string
+
+const (
+	FollowStatusFollowing FollowStatus = "following"
+	FollowStatusPending   FollowStatus = "pending"
+)
+
 type FollowService struct {
-	userRepo   repositories.UserRepository
-	followRepo repositories.FollowRepository
-	followPub  publishers.FollowPublisher
+	userRepo          repositories.UserRepository
+	followRepo        repositories.FollowRepository
+	followRequestRepo repositories.FollowRequestRepository
+	followPub         publishers.FollowPublisher
 }
 
 func NewFollowService(
 	userRepo repositories.UserRepository,
 	followRepo repositories.FollowRepository,
+	followRequestRepo repositories.FollowRequestRepository,
 	followPub publishers.FollowPublisher,
 ) *FollowService {
 	return &FollowService{
-		userRepo:   userRepo,
-		followRepo: followRepo,
-		followPub:  followPub,
+		userRepo:          userRepo,
+		followRepo:        followRepo,
+		followRequestRepo: followRequestRepo,
+		followPub:         followPub,
 	}
 }
 
-func (s *FollowService) Follow(followerID, followedID int) error {
+// Follow makes followerID follow followedID. Following a protected account
+// only creates a pending follow request, which takes effect once approved.
+func (s *FollowService) Follow(ctx context.Context, followerID, followedID int) (FollowStatus, error) {
+	ctx, span := tracer.Start(ctx, "FollowService.Follow")
+	defer span.End()
+
 	if followerID == followedID {
-		return NewErrAlreadyFollowing(followerID, followedID)
+		return "", NewErrAlreadyFollowing(followerID, followedID)
 	}
 
-	followerExists, err := s.userRepo.Exists(followerID)
+	followerExists, err := s.userRepo.Exists(ctx, followerID)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if !followerExists {
-		return NewErrUserNotFound(followerID)
+		return "", NewErrUserNotFound(followerID)
 	}
 
-	followedExists, err := s.userRepo.Exists(followedID)
+	followed, err := s.getUser(ctx, followedID)
 	if err != nil {
-		return err
-	}
-	if !followedExists {
-		return NewErrUserNotFound(followedID)
+		return "", err
 	}
 
-	isFollowing, err := s.followRepo.IsFollowing(followerID, followedID)
+	isFollowing, err := s.followRepo.IsFollowing(ctx, followerID, followedID)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if isFollowing {
-		return NewErrAlreadyFollowing(followerID, followedID)
+		return "", NewErrAlreadyFollowing(followerID, followedID)
 	}
 
-	if err := s.followRepo.Follow(followerID, followedID); err != nil {
-		return err
+	if followed.Protected {
+		pending, err := s.followRequestRepo.Exists(ctx, followerID, followedID)
+		if err != nil {
+			return "", err
+		}
+		if pending {
+			return "", NewErrFollowRequestAlreadyExists(followerID, followedID)
+		}
+		if err := s.followRequestRepo.Create(ctx, followerID, followedID); err != nil {
+			return "", err
+		}
+		return FollowStatusPending, nil
 	}
 
-	// Publish follow event
-	event := domain.FollowEvent{
-		FollowerID: followerID,
-		FollowedID: followedID,
-		Following:  true,
-	}
-	if err := s.followPub.PublishFollowEvent(event); err != nil {
-		// Log the error but don't fail the operation
-		// The system can still function without the event being published
+	if err := s.followRepo.Follow(ctx, followerID, followedID); err != nil {
+		return "", err
 	}
 
-	return nil
+	s.publishFollowEvent(ctx, followerID, followedID, true)
+
+	return FollowStatusFollowing, nil
 }
 
-func (s *FollowService) Unfollow(followerID, followedID int) error {
-	followerExists, err := s.userRepo.Exists(followerID)
+func (s *FollowService) Unfollow(ctx context.Context, followerID, followedID int) error {
+	ctx, span := tracer.Start(ctx, "FollowService.Unfollow")
+	defer span.End()
+
+	followerExists, err := s.userRepo.Exists(ctx, followerID)
 	if err != nil {
 		return err
 	}
@@ -80,7 +102,7 @@ func (s *FollowService) Unfollow(followerID, followedID int) error {
 		return NewErrUserNotFound(followerID)
 	}
 
-	followedExists, err := s.userRepo.Exists(followedID)
+	followedExists, err := s.userRepo.Exists(ctx, followedID)
 	if err != nil {
 		return err
 	}
@@ -88,34 +110,101 @@ func (s *FollowService) Unfollow(followerID, followedID int) error {
 		return NewErrUserNotFound(followedID)
 	}
 
-	isFollowing, err := s.followRepo.IsFollowing(followerID, followedID)
+	isFollowing, err := s.followRepo.IsFollowing(ctx, followerID, followedID)
 	if err != nil {
 		return err
 	}
 	if !isFollowing {
+		// Unfollowing a protected account before approval withdraws the request
+		pending, err := s.followRequestRepo.Exists(ctx, followerID, followedID)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return s.followRequestRepo.Delete(ctx, followerID, followedID)
+		}
 		return NewErrNotFollowing(followerID, followedID)
 	}
 
-	if err := s.followRepo.Unfollow(followerID, followedID); err != nil {
+	if err := s.followRepo.Unfollow(ctx, followerID, followedID); err != nil {
 		return err
 	}
 
-	// Publish unfollow event
-	event := domain.FollowEvent{
-		FollowerID: followerID,
-		FollowedID: followedID,
-		Following:  false,
+	s.publishFollowEvent(ctx, followerID, followedID, false)
+
+	return nil
+}
+
+// ApproveFollowRequest turns a pending request into a follow. The follow
+// event is only published once the follow is stored and the request gone,
+// so a failed approval can simply be retried.
+func (s *FollowService) ApproveFollowRequest(ctx context.Context, targetID, requesterID int) error {
+	ctx, span := tracer.Start(ctx, "FollowService.ApproveFollowRequest")
+	defer span.End()
+
+	if err := s.followRequestRepo.Approve(ctx, requesterID, targetID); err != nil {
+		return err
 	}
-	if err := s.followPub.PublishFollowEvent(event); err != nil {
-		// Log the error but don't fail the operation
-		// The system can still function without the event being published
+
+	s.publishFollowEvent(ctx, requesterID, targetID, true)
+
+	return nil
+}
+
+// RejectFollowRequest discards a pending request without notifying anyone
+func (s *FollowService) RejectFollowRequest(ctx context.Context, targetID, requesterID int) error {
+	ctx, span := tracer.Start(ctx, "FollowService.RejectFollowRequest")
+	defer span.End()
+
+	return s.followRequestRepo.Delete(ctx, requesterID, targetID)
+}
+
+func (s *FollowService) ListFollowRequests(ctx context.Context, targetID int) ([]*domain.FollowRequest, error) {
+	ctx, span := tracer.Start(ctx, "FollowService.ListFollowRequests")
+	defer span.End()
+
+	exists, err := s.userRepo.Exists(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewErrUserNotFound(targetID)
+	}
+
+	return s.followRequestRepo.GetByTargetID(ctx, targetID)
+}
+
+// SetProtected changes whether new followers need approval. Making an
+// account public approves every request that is still pending.
+func (s *FollowService) SetProtected(ctx context.Context, userID int, protected bool) error {
+	ctx, span := tracer.Start(ctx, "FollowService.SetProtected")
+	defer span.End()
+
+	if err := s.userRepo.SetProtected(ctx, userID, protected); err != nil {
+		return err
+	}
+	if protected {
+		return nil
+	}
+
+	requests, err := s.followRequestRepo.GetByTargetID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, request := range requests {
+		if err := s.ApproveFollowRequest(ctx, userID, request.RequesterID); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (s *FollowService) IsFollowing(followerID, followedID int) (bool, error) {
-	followerExists, err := s.userRepo.Exists(followerID)
+func (s *FollowService) IsFollowing(ctx context.Context, followerID, followedID int) (bool, error) {
+	ctx, span := tracer.Start(ctx, "FollowService.IsFollowing")
+	defer span.End()
+
+	followerExists, err := s.userRepo.Exists(ctx, followerID)
 	if err != nil {
 		return false, err
 	}
@@ -123,7 +212,7 @@ func (s *FollowService) IsFollowing(followerID, followedID int) (bool, error) {
 		return false, NewErrUserNotFound(followerID)
 	}
 
-	followedExists, err := s.userRepo.Exists(followedID)
+	followedExists, err := s.userRepo.Exists(ctx, followedID)
 	if err != nil {
 		return false, err
 	}
@@ -131,10 +220,34 @@ func (s *FollowService) IsFollowing(followerID, followedID int) (bool, error) {
 		return false, NewErrUserNotFound(followedID)
 	}
 
-	isFollowing, err := s.followRepo.IsFollowing(followerID, followedID)
+	isFollowing, err := s.followRepo.IsFollowing(ctx, followerID, followedID)
 	if err != nil {
 		return false, err
 	}
 
 	return isFollowing, nil
 }
+
+func (s *FollowService) getUser(ctx context.Context, id int) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, NewErrUserNotFound(id)
+	}
+	return user, nil
+}
+
+func (s *FollowService) publishFollowEvent(ctx context.Context, followerID, followedID int, following bool) {
+	event := domain.FollowEvent{
+		EventID:    domain.NewEventID(),
+		FollowerID: followerID,
+		FollowedID: followedID,
+		Following:  following,
+	}
+	if err := s.followPub.PublishFollowEvent(ctx, event); err != nil {
+		// Log the error but don't fail the operation
+		// The system can still function without the event being published
+	}
+}
@@ -1,6 +1,8 @@
 package application_test
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,31 +16,45 @@ type MockFollowPublisher struct {
 	mock.Mock
 }
 
-func (m *MockFollowPublisher) PublishFollowEvent(event domain.FollowEvent) error {
+func (m *MockFollowPublisher) PublishFollowEvent(ctx context.Context, event domain.FollowEvent) error {
 	args := m.Called(event)
 	return args.Error(0)
 }
 
 type testMocks struct {
-	userRepo   *application.MockUserRepository
-	followRepo *application.MockFollowRepository
-	followPub  *MockFollowPublisher
+	userRepo          *application.MockUserRepository
+	followRepo        *application.MockFollowRepository
+	followRequestRepo *application.MockFollowRequestRepository
+	followPub         *MockFollowPublisher
 }
 
 func newTestMocks() *testMocks {
 	return &testMocks{
-		userRepo:   &application.MockUserRepository{},
-		followRepo: &application.MockFollowRepository{},
-		followPub:  &MockFollowPublisher{},
+		userRepo:          &application.MockUserRepository{},
+		followRepo:        &application.MockFollowRepository{},
+		followRequestRepo: &application.MockFollowRequestRepository{},
+		followPub:         &MockFollowPublisher{},
 	}
 }
 
+func (m *testMocks) newService() *application.FollowService {
+	return application.NewFollowService(m.userRepo, m.followRepo, m.followRequestRepo, m.followPub)
+}
+
+func (m *testMocks) assertExpectations(t *testing.T) {
+	m.userRepo.AssertExpectations(t)
+	m.followRepo.AssertExpectations(t)
+	m.followRequestRepo.AssertExpectations(t)
+	m.followPub.AssertExpectations(t)
+}
+
 func TestFollowService_Follow(t *testing.T) {
 	tests := []struct {
 		name       string
 		setupMocks func(*testMocks)
 		followerID int
 		followedID int
+		expect     application.FollowStatus
 		expectErr  bool
 		errType    error
 	}{
@@ -46,7 +62,7 @@ func TestFollowService_Follow(t *testing.T) {
 			name: "successful follow",
 			setupMocks: func(m *testMocks) {
 				m.userRepo.On("Exists", 1).Return(true, nil)
-				m.userRepo.On("Exists", 2).Return(true, nil)
+				m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2}, nil)
 				m.followRepo.On("IsFollowing", 1, 2).Return(false, nil)
 				m.followRepo.On("Follow", 1, 2).Return(nil)
 				m.followPub.On("PublishFollowEvent", mock.MatchedBy(func(e domain.FollowEvent) bool {
@@ -55,8 +71,36 @@ func TestFollowService_Follow(t *testing.T) {
 			},
 			followerID: 1,
 			followedID: 2,
+			expect:     application.FollowStatusFollowing,
 			expectErr:  false,
 		},
+		{
+			name: "following a protected account creates a request",
+			setupMocks: func(m *testMocks) {
+				m.userRepo.On("Exists", 1).Return(true, nil)
+				m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2, Protected: true}, nil)
+				m.followRepo.On("IsFollowing", 1, 2).Return(false, nil)
+				m.followRequestRepo.On("Exists", 1, 2).Return(false, nil)
+				m.followRequestRepo.On("Create", 1, 2).Return(nil)
+			},
+			followerID: 1,
+			followedID: 2,
+			expect:     application.FollowStatusPending,
+			expectErr:  false,
+		},
+		{
+			name: "follow request already pending",
+			setupMocks: func(m *testMocks) {
+				m.userRepo.On("Exists", 1).Return(true, nil)
+				m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2, Protected: true}, nil)
+				m.followRepo.On("IsFollowing", 1, 2).Return(false, nil)
+				m.followRequestRepo.On("Exists", 1, 2).Return(true, nil)
+			},
+			followerID: 1,
+			followedID: 2,
+			expectErr:  true,
+			errType:    &application.ErrFollowRequestAlreadyExists{},
+		},
 		{
 			name: "cannot follow self",
 			setupMocks: func(m *testMocks) {
@@ -81,7 +125,7 @@ func TestFollowService_Follow(t *testing.T) {
 			name: "followed user not found",
 			setupMocks: func(m *testMocks) {
 				m.userRepo.On("Exists", 1).Return(true, nil)
-				m.userRepo.On("Exists", 999).Return(false, nil)
+				m.userRepo.On("GetByID", 999).Return(nil, application.NewErrUserNotFound(999))
 			},
 			followerID: 1,
 			followedID: 999,
@@ -92,7 +136,7 @@ func TestFollowService_Follow(t *testing.T) {
 			name: "already following",
 			setupMocks: func(m *testMocks) {
 				m.userRepo.On("Exists", 1).Return(true, nil)
-				m.userRepo.On("Exists", 2).Return(true, nil)
+				m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2}, nil)
 				m.followRepo.On("IsFollowing", 1, 2).Return(true, nil)
 			},
 			followerID: 1,
@@ -107,9 +151,9 @@ func TestFollowService_Follow(t *testing.T) {
 			m := newTestMocks()
 			tt.setupMocks(m)
 
-			service := application.NewFollowService(m.userRepo, m.followRepo, m.followPub)
+			service := m.newService()
 
-			err := service.Follow(tt.followerID, tt.followedID)
+			status, err := service.Follow(context.Background(), tt.followerID, tt.followedID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -118,11 +162,10 @@ func TestFollowService_Follow(t *testing.T) {
 				}
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.expect, status)
 			}
 
-			m.userRepo.AssertExpectations(t)
-			m.followRepo.AssertExpectations(t)
-			m.followPub.AssertExpectations(t)
+			m.assertExpectations(t)
 		})
 	}
 }
@@ -172,12 +215,26 @@ func TestFollowService_Unfollow(t *testing.T) {
 			expectErr:  true,
 			errType:    &application.ErrUserNotFound{},
 		},
+		{
+			name: "unfollow withdraws a pending request",
+			setupMocks: func(m *testMocks) {
+				m.userRepo.On("Exists", 1).Return(true, nil)
+				m.userRepo.On("Exists", 2).Return(true, nil)
+				m.followRepo.On("IsFollowing", 1, 2).Return(false, nil)
+				m.followRequestRepo.On("Exists", 1, 2).Return(true, nil)
+				m.followRequestRepo.On("Delete", 1, 2).Return(nil)
+			},
+			followerID: 1,
+			followedID: 2,
+			expectErr:  false,
+		},
 		{
 			name: "not following",
 			setupMocks: func(m *testMocks) {
 				m.userRepo.On("Exists", 1).Return(true, nil)
 				m.userRepo.On("Exists", 2).Return(true, nil)
 				m.followRepo.On("IsFollowing", 1, 2).Return(false, nil)
+				m.followRequestRepo.On("Exists", 1, 2).Return(false, nil)
 			},
 			followerID: 1,
 			followedID: 2,
@@ -191,9 +248,9 @@ func TestFollowService_Unfollow(t *testing.T) {
 			m := newTestMocks()
 			tt.setupMocks(m)
 
-			service := application.NewFollowService(m.userRepo, m.followRepo, m.followPub)
+			service := m.newService()
 
-			err := service.Unfollow(tt.followerID, tt.followedID)
+			err := service.Unfollow(context.Background(), tt.followerID, tt.followedID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -204,9 +261,7 @@ func TestFollowService_Unfollow(t *testing.T) {
 				assert.NoError(t, err)
 			}
 
-			m.userRepo.AssertExpectations(t)
-			m.followRepo.AssertExpectations(t)
-			m.followPub.AssertExpectations(t)
+			m.assertExpectations(t)
 		})
 	}
 }
@@ -272,9 +327,9 @@ func TestFollowService_IsFollowing(t *testing.T) {
 			m := newTestMocks()
 			tt.setupMocks(m)
 
-			service := application.NewFollowService(m.userRepo, m.followRepo, m.followPub)
+			service := m.newService()
 
-			result, err := service.IsFollowing(tt.followerID, tt.followedID)
+			result, err := service.IsFollowing(context.Background(), tt.followerID, tt.followedID)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -288,3 +343,94 @@ func TestFollowService_IsFollowing(t *testing.T) {
 		})
 	}
 }
+
+func TestFollowService_ApproveFollowRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(m *testMocks)
+		expectErr  bool
+		errType    error
+	}{
+		{
+			name: "successful approval",
+			setupMocks: func(m *testMocks) {
+				m.followRequestRepo.On("Approve", 1, 2).Return(nil)
+				m.followPub.On("PublishFollowEvent", mock.MatchedBy(func(e domain.FollowEvent) bool {
+					return e.FollowerID == 1 && e.FollowedID == 2 && e.Following
+				})).Return(nil)
+			},
+			expectErr: false,
+		},
+		{
+			name: "no pending request",
+			setupMocks: func(m *testMocks) {
+				m.followRequestRepo.On("Approve", 1, 2).Return(application.NewErrFollowRequestNotFound(1, 2))
+			},
+			expectErr: true,
+			errType:   &application.ErrFollowRequestNotFound{},
+		},
+		{
+			name: "failed approval publishes no event",
+			setupMocks: func(m *testMocks) {
+				m.followRequestRepo.On("Approve", 1, 2).Return(errors.New("connection reset"))
+			},
+			expectErr: true,
+			errType:   errors.New(""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMocks()
+			tt.setupMocks(m)
+
+			err := m.newService().ApproveFollowRequest(context.Background(), 2, 1)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.IsType(t, tt.errType, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestFollowService_RejectFollowRequest(t *testing.T) {
+	m := newTestMocks()
+	m.followRequestRepo.On("Delete", 1, 2).Return(nil)
+
+	err := m.newService().RejectFollowRequest(context.Background(), 2, 1)
+
+	assert.NoError(t, err)
+	m.assertExpectations(t)
+}
+
+func TestFollowService_SetProtected(t *testing.T) {
+	t.Run("protecting an account keeps existing followers", func(t *testing.T) {
+		m := newTestMocks()
+		m.userRepo.On("SetProtected", 2, true).Return(nil)
+
+		err := m.newService().SetProtected(context.Background(), 2, true)
+
+		assert.NoError(t, err)
+		m.assertExpectations(t)
+	})
+
+	t.Run("making an account public approves pending requests", func(t *testing.T) {
+		m := newTestMocks()
+		m.userRepo.On("SetProtected", 2, false).Return(nil)
+		m.followRequestRepo.On("GetByTargetID", 2).Return([]*domain.FollowRequest{
+			{RequesterID: 1, TargetID: 2},
+		}, nil)
+		m.followRequestRepo.On("Approve", 1, 2).Return(nil)
+		m.followPub.On("PublishFollowEvent", mock.Anything).Return(nil)
+
+		err := m.newService().SetProtected(context.Background(), 2, false)
+
+		assert.NoError(t, err)
+		m.assertExpectations(t)
+	})
+}
@@ -0,0 +1,115 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/health"
+)
+
+// HealthService answers the liveness and readiness probes. Liveness covers
+// what only a restart fixes, like a consumer that stopped; readiness covers
+// the dependencies requests and messages need. Each check gets its own
+// timeout, and reports are reused for a while so frequent probes do not
+// hammer the dependencies.
+type HealthService struct {
+	liveness  *healthProbe
+	readiness *healthProbe
+}
+
+func NewHealthService(liveness, readiness []health.Check, timeout, cacheTTL time.Duration) *HealthService {
+	return &HealthService{
+		liveness:  &healthProbe{checks: liveness, timeout: timeout, cacheTTL: cacheTTL, now: time.Now},
+		readiness: &healthProbe{checks: readiness, timeout: timeout, cacheTTL: cacheTTL, now: time.Now},
+	}
+}
+
+// Liveness reports whether the service works at all
+func (s *HealthService) Liveness(ctx context.Context) domain.HealthReport {
+	return s.liveness.report(ctx)
+}
+
+// Readiness reports whether the service can handle requests and messages
+func (s *HealthService) Readiness(ctx context.Context) domain.HealthReport {
+	return s.readiness.report(ctx)
+}
+
+type healthProbe struct {
+	checks   []health.Check
+	timeout  time.Duration
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	// mu is held while checking, so concurrent probes wait for one report
+	// instead of each checking the dependencies
+	mu     sync.Mutex
+	cached *domain.HealthReport
+}
+
+func (p *healthProbe) report(ctx context.Context) domain.HealthReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cached != nil && p.now().Sub(p.cached.CheckedAt) < p.cacheTTL {
+		return *p.cached
+	}
+
+	report := domain.HealthReport{
+		Status:     domain.HealthStatusUp,
+		Components: make(map[string]domain.ComponentHealth, len(p.checks)),
+		CheckedAt:  p.now(),
+	}
+	results := make([]domain.ComponentHealth, len(p.checks))
+	var wg sync.WaitGroup
+	for i, check := range p.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = p.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for i, check := range p.checks {
+		report.Components[check.Name()] = results[i]
+		if results[i].Status != domain.HealthStatusUp {
+			report.Status = domain.HealthStatusDown
+		}
+	}
+	// A probe cut short by its caller says nothing about the dependencies
+	if ctx.Err() == nil {
+		p.cached = &report
+	}
+	return report
+}
+
+// run runs check within the timeout, even if it does not give up once its
+// context is done
+func (p *healthProbe) run(ctx context.Context, check health.Check) domain.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := p.now()
+	done := make(chan error, 1)
+	go func() { done <- check.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("timed out after " + p.timeout.String())
+	}
+
+	component := domain.ComponentHealth{Status: domain.HealthStatusUp, Duration: p.now().Sub(start)}
+	if err != nil {
+		component.Status = domain.HealthStatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
@@ -0,0 +1,92 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCheck fails with err, or hangs until released when hang is set
+type fakeCheck struct {
+	name  string
+	err   error
+	hang  chan struct{}
+	calls atomic.Int32
+}
+
+func (c *fakeCheck) Name() string { return c.name }
+
+func (c *fakeCheck) Check(ctx context.Context) error {
+	c.calls.Add(1)
+	if c.hang != nil {
+		<-c.hang
+	}
+	return c.err
+}
+
+func TestHealthService_Readiness(t *testing.T) {
+	postgres := &fakeCheck{name: "postgres"}
+	redis := &fakeCheck{name: "redis", err: errors.New("connection refused")}
+	kafka := &fakeCheck{name: "kafka", hang: make(chan struct{})}
+	defer close(kafka.hang)
+
+	service := application.NewHealthService(nil, []health.Check{postgres, redis, kafka}, 20*time.Millisecond, time.Minute)
+	report := service.Readiness(context.Background())
+
+	assert.Equal(t, domain.HealthStatusDown, report.Status)
+	assert.Equal(t, domain.HealthStatusUp, report.Components["postgres"].Status)
+	assert.Equal(t, domain.ComponentHealth{Status: domain.HealthStatusDown, Error: "connection refused", Duration: report.Components["redis"].Duration}, report.Components["redis"])
+	assert.Equal(t, domain.HealthStatusDown, report.Components["kafka"].Status)
+	assert.Equal(t, "timed out after 20ms", report.Components["kafka"].Error)
+	assert.GreaterOrEqual(t, report.Components["kafka"].Duration, 20*time.Millisecond)
+}
+
+func TestHealthService_Liveness(t *testing.T) {
+	service := application.NewHealthService([]health.Check{&fakeCheck{name: "consumers"}}, nil, time.Second, time.Minute)
+
+	report := service.Liveness(context.Background())
+	assert.Equal(t, domain.HealthStatusUp, report.Status)
+	assert.Len(t, report.Components, 1)
+
+	// Without checks, there is nothing to be down
+	assert.Equal(t, domain.HealthStatusUp, application.NewHealthService(nil, nil, time.Second, time.Minute).Liveness(context.Background()).Status)
+}
+
+func TestHealthService_CachesReports(t *testing.T) {
+	check := &fakeCheck{name: "postgres"}
+	service := application.NewHealthService(nil, []health.Check{check}, time.Second, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.Readiness(context.Background())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), check.calls.Load(), "concurrent probes share one report")
+
+	time.Sleep(60 * time.Millisecond)
+	service.Readiness(context.Background())
+	assert.Equal(t, int32(2), check.calls.Load(), "the report expired")
+}
+
+func TestHealthService_DoesNotCacheCanceledProbes(t *testing.T) {
+	check := &fakeCheck{name: "postgres"}
+	service := application.NewHealthService(nil, []health.Check{check}, time.Second, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, domain.HealthStatusDown, service.Readiness(ctx).Status)
+	assert.Equal(t, domain.HealthStatusUp, service.Readiness(context.Background()).Status)
+}
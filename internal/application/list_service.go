@@ -0,0 +1,352 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const (
+	maxListNameLength        = 25
+	maxListDescriptionLength = 100
+	maxListMembers           = 5000
+)
+
+type ListService struct {
+	listRepo     repositories.ListRepository
+	userRepo     repositories.UserRepository
+	followRepo   repositories.FollowRepository
+	tweetRepo    repositories.TweetRepository
+	listTimeline repositories.TimelineCache
+}
+
+// NewListService creates a ListService. listTimeline is a TimelineCache keyed
+// by list ID rather than user ID.
+func NewListService(
+	listRepo repositories.ListRepository,
+	userRepo repositories.UserRepository,
+	followRepo repositories.FollowRepository,
+	tweetRepo repositories.TweetRepository,
+	listTimeline repositories.TimelineCache,
+) *ListService {
+	return &ListService{
+		listRepo:     listRepo,
+		userRepo:     userRepo,
+		followRepo:   followRepo,
+		tweetRepo:    tweetRepo,
+		listTimeline: listTimeline,
+	}
+}
+
+type ListInput struct {
+	Name        string
+	Description string
+	Private     bool
+}
+
+func (s *ListService) CreateList(ctx context.Context, ownerID int, input ListInput) (*domain.List, error) {
+	ctx, span := tracer.Start(ctx, "ListService.CreateList")
+	defer span.End()
+
+	exists, err := s.userRepo.Exists(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewErrUserNotFound(ownerID)
+	}
+
+	list := &domain.List{OwnerID: ownerID}
+	if err := applyListInput(list, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.listRepo.Create(ctx, list); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+	return list, nil
+}
+
+// GetList returns a list as seen by viewerID (0 for anonymous viewers).
+// Private lists are reported as not found to anyone but their owner.
+func (s *ListService) GetList(ctx context.Context, viewerID, listID int) (*domain.List, error) {
+	ctx, span := tracer.Start(ctx, "ListService.GetList")
+	defer span.End()
+
+	return s.getVisibleList(ctx, viewerID, listID)
+}
+
+// GetUserLists returns the lists owned by ownerID that viewerID can see.
+func (s *ListService) GetUserLists(ctx context.Context, viewerID, ownerID int) ([]*domain.List, error) {
+	ctx, span := tracer.Start(ctx, "ListService.GetUserLists")
+	defer span.End()
+
+	exists, err := s.userRepo.Exists(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewErrUserNotFound(ownerID)
+	}
+
+	lists, err := s.listRepo.GetByOwnerID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if viewerID == ownerID {
+		return lists, nil
+	}
+
+	visible := make([]*domain.List, 0, len(lists))
+	for _, list := range lists {
+		if !list.Private {
+			visible = append(visible, list)
+		}
+	}
+	return visible, nil
+}
+
+func (s *ListService) UpdateList(ctx context.Context, ownerID, listID int, input ListInput) (*domain.List, error) {
+	ctx, span := tracer.Start(ctx, "ListService.UpdateList")
+	defer span.End()
+
+	list, err := s.getOwnedList(ctx, ownerID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyListInput(list, input); err != nil {
+		return nil, err
+	}
+	if err := s.listRepo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *ListService) DeleteList(ctx context.Context, ownerID, listID int) error {
+	ctx, span := tracer.Start(ctx, "ListService.DeleteList")
+	defer span.End()
+
+	if _, err := s.getOwnedList(ctx, ownerID, listID); err != nil {
+		return err
+	}
+	if err := s.listRepo.Delete(ctx, listID); err != nil {
+		return err
+	}
+	return s.listTimeline.ClearTimeline(ctx, listID)
+}
+
+// AddMember adds memberID to a list and merges the member's existing tweets
+// into the list timeline, in order. Protected accounts can only be added by
+// owners who follow them.
+func (s *ListService) AddMember(ctx context.Context, ownerID, listID, memberID int) error {
+	ctx, span := tracer.Start(ctx, "ListService.AddMember")
+	defer span.End()
+
+	if _, err := s.getOwnedList(ctx, ownerID, listID); err != nil {
+		return err
+	}
+
+	member, err := s.userRepo.GetByID(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Protected && memberID != ownerID {
+		following, err := s.followRepo.IsFollowing(ctx, ownerID, memberID)
+		if err != nil {
+			return err
+		}
+		if !following {
+			return NewErrProtectedAccount(memberID)
+		}
+	}
+
+	members, err := s.listRepo.GetMembers(ctx, listID)
+	if err != nil {
+		return err
+	}
+	if len(members) >= maxListMembers {
+		return NewErrInvalidInput(fmt.Sprintf("list cannot have more than %d members", maxListMembers))
+	}
+
+	if err := s.listRepo.AddMember(ctx, listID, memberID); err != nil {
+		return err
+	}
+	tweetIDs, err := s.recentTweetIDs(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	return s.listTimeline.MergeIntoTimeline(ctx, listID, tweetIDs, repositories.TimelineLength)
+}
+
+// RemoveMember removes memberID from a list and drops their tweets from the
+// list timeline.
+func (s *ListService) RemoveMember(ctx context.Context, ownerID, listID, memberID int) error {
+	ctx, span := tracer.Start(ctx, "ListService.RemoveMember")
+	defer span.End()
+
+	if _, err := s.getOwnedList(ctx, ownerID, listID); err != nil {
+		return err
+	}
+	if err := s.listRepo.RemoveMember(ctx, listID, memberID); err != nil {
+		return err
+	}
+	tweetIDs, err := s.recentTweetIDs(ctx, memberID)
+	if err != nil {
+		return err
+	}
+	return s.listTimeline.RemoveFromTimelines(ctx, []int{listID}, tweetIDs)
+}
+
+func (s *ListService) GetMembers(ctx context.Context, viewerID, listID int) ([]int, error) {
+	ctx, span := tracer.Start(ctx, "ListService.GetMembers")
+	defer span.End()
+
+	if _, err := s.getVisibleList(ctx, viewerID, listID); err != nil {
+		return nil, err
+	}
+	return s.listRepo.GetMembers(ctx, listID)
+}
+
+// GetListTimeline returns the most recent tweet IDs of a list timeline.
+// Tweets that were deleted or whose author is deactivated are left out, and
+// for viewers other than the owner so are tweets of protected members the
+// viewer does not follow, so a page may come back short.
+func (s *ListService) GetListTimeline(ctx context.Context, viewerID, listID, limit int) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "ListService.GetListTimeline")
+	defer span.End()
+
+	list, err := s.getVisibleList(ctx, viewerID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.listTimeline.GetTimeline(ctx, listID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+	return s.filterVisible(ctx, viewerID, list, ids)
+}
+
+func (s *ListService) getVisibleList(ctx context.Context, viewerID, listID int) (*domain.List, error) {
+	list, err := s.listRepo.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.Private && list.OwnerID != viewerID {
+		return nil, NewErrListNotFound(listID)
+	}
+	return list, nil
+}
+
+// getOwnedList returns the list if ownerID owns it. Lists of other users are
+// reported as not found so their existence is not revealed.
+func (s *ListService) getOwnedList(ctx context.Context, ownerID, listID int) (*domain.List, error) {
+	list, err := s.listRepo.GetByID(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID != ownerID {
+		return nil, NewErrListNotFound(listID)
+	}
+	return list, nil
+}
+
+// recentTweetIDs returns the IDs of the newest tweets of a member that can
+// be in a list timeline, newest first. Older ones would not make the cut.
+func (s *ListService) recentTweetIDs(ctx context.Context, memberID int) ([]int64, error) {
+	ids, err := s.tweetRepo.GetTweetIDsByUser(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	return ids[:min(len(ids), repositories.TimelineLength)], nil
+}
+
+func (s *ListService) filterVisible(ctx context.Context, viewerID int, list *domain.List, ids []int64) ([]int64, error) {
+	tweets, err := s.tweetRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	authorOf := make(map[int64]int64, len(tweets))
+	for _, tweet := range tweets {
+		authorOf[tweet.ID] = tweet.UserID
+	}
+
+	hidden, err := s.hiddenAuthors(ctx, viewerID, list, tweets)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		authorID, ok := authorOf[id]
+		if !ok || hidden[authorID] {
+			continue
+		}
+		visible = append(visible, id)
+	}
+	return visible, nil
+}
+
+// hiddenAuthors returns the protected authors of tweets that viewerID does
+// not follow. The owner of the list sees every member.
+func (s *ListService) hiddenAuthors(ctx context.Context, viewerID int, list *domain.List, tweets []*domain.Tweet) (map[int64]bool, error) {
+	hidden := make(map[int64]bool)
+	if viewerID == list.OwnerID {
+		return hidden, nil
+	}
+
+	checked := make(map[int64]bool)
+	authorIDs := make([]int, 0, len(tweets))
+	for _, tweet := range tweets {
+		if checked[tweet.UserID] || tweet.UserID == int64(viewerID) {
+			continue
+		}
+		checked[tweet.UserID] = true
+		authorIDs = append(authorIDs, int(tweet.UserID))
+	}
+	authors, err := s.userRepo.GetByIDs(ctx, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, author := range authors {
+		if !author.Protected {
+			continue
+		}
+		following := false
+		if viewerID != 0 {
+			following, err = s.followRepo.IsFollowing(ctx, viewerID, author.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		hidden[int64(author.ID)] = !following
+	}
+	return hidden, nil
+}
+
+func applyListInput(list *domain.List, input ListInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return NewErrInvalidInput("list name cannot be empty")
+	}
+	if len(name) > maxListNameLength {
+		return NewErrInvalidInput(fmt.Sprintf("list name is too long (max %d characters)", maxListNameLength))
+	}
+	description := strings.TrimSpace(input.Description)
+	if len(description) > maxListDescriptionLength {
+		return NewErrInvalidInput(fmt.Sprintf("list description is too long (max %d characters)", maxListDescriptionLength))
+	}
+
+	list.Name = name
+	list.Description = description
+	list.Private = input.Private
+	return nil
+}
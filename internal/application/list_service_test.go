@@ -0,0 +1,233 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type listTestMocks struct {
+	listRepo   *MockListRepository
+	userRepo   *MockUserRepository
+	followRepo *MockFollowRepository
+	tweetRepo  *MockTweetRepository
+	timeline   *MockTimelineCache
+}
+
+func newListTestMocks() *listTestMocks {
+	return &listTestMocks{
+		listRepo:   new(MockListRepository),
+		userRepo:   new(MockUserRepository),
+		followRepo: new(MockFollowRepository),
+		tweetRepo:  new(MockTweetRepository),
+		timeline:   new(MockTimelineCache),
+	}
+}
+
+func (m *listTestMocks) newService() *ListService {
+	return NewListService(m.listRepo, m.userRepo, m.followRepo, m.tweetRepo, m.timeline)
+}
+
+func (m *listTestMocks) assertExpectations(t *testing.T) {
+	m.listRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.followRepo.AssertExpectations(t)
+	m.tweetRepo.AssertExpectations(t)
+	m.timeline.AssertExpectations(t)
+}
+
+func TestListService_CreateList(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      ListInput
+		setupMocks func(*listTestMocks)
+		errType    error
+	}{
+		{
+			name:  "successful creation",
+			input: ListInput{Name: " Go devs ", Description: "People writing Go", Private: true},
+			setupMocks: func(m *listTestMocks) {
+				m.userRepo.On("Exists", 1).Return(true, nil)
+				m.listRepo.On("Create", mock.MatchedBy(func(l *domain.List) bool {
+					return l.OwnerID == 1 && l.Name == "Go devs" && l.Private
+				})).Return(nil)
+			},
+		},
+		{
+			name:  "empty name",
+			input: ListInput{Name: "  "},
+			setupMocks: func(m *listTestMocks) {
+				m.userRepo.On("Exists", 1).Return(true, nil)
+			},
+			errType: &ErrInvalidInput{},
+		},
+		{
+			name:  "name too long",
+			input: ListInput{Name: "a list name that is way too long"},
+			setupMocks: func(m *listTestMocks) {
+				m.userRepo.On("Exists", 1).Return(true, nil)
+			},
+			errType: &ErrInvalidInput{},
+		},
+		{
+			name:  "owner not found",
+			input: ListInput{Name: "Go devs"},
+			setupMocks: func(m *listTestMocks) {
+				m.userRepo.On("Exists", 1).Return(false, nil)
+			},
+			errType: &ErrUserNotFound{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newListTestMocks()
+			tt.setupMocks(m)
+
+			list, err := m.newService().CreateList(context.Background(), 1, tt.input)
+
+			if tt.errType != nil {
+				assert.IsType(t, tt.errType, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, list)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestListService_PrivateListsAreHidden(t *testing.T) {
+	m := newListTestMocks()
+	m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 1, Private: true}, nil)
+	service := m.newService()
+
+	_, err := service.GetList(context.Background(), 2, 5)
+	assert.IsType(t, &ErrListNotFound{}, err)
+
+	list, err := service.GetList(context.Background(), 1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, list.ID)
+
+	m.userRepo.On("Exists", 1).Return(true, nil)
+	m.listRepo.On("GetByOwnerID", 1).Return([]*domain.List{
+		{ID: 5, OwnerID: 1, Private: true},
+		{ID: 6, OwnerID: 1},
+	}, nil)
+
+	lists, err := service.GetUserLists(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 1)
+	assert.Equal(t, 6, lists[0].ID)
+
+	lists, err = service.GetUserLists(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, lists, 2)
+}
+
+func TestListService_AddMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(*listTestMocks)
+		errType    error
+	}{
+		{
+			name: "adds member and merges their tweets into the timeline",
+			setupMocks: func(m *listTestMocks) {
+				m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 1}, nil)
+				m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2}, nil)
+				m.listRepo.On("GetMembers", 5).Return([]int{3}, nil)
+				m.listRepo.On("AddMember", 5, 2).Return(nil)
+				m.tweetRepo.On("GetTweetIDsByUser", 2).Return([]int64{20, 10}, nil)
+				m.timeline.On("MergeIntoTimeline", 5, []int64{20, 10}, repositories.TimelineLength).Return(nil)
+			},
+		},
+		{
+			name: "list owned by someone else",
+			setupMocks: func(m *listTestMocks) {
+				m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 9}, nil)
+			},
+			errType: &ErrListNotFound{},
+		},
+		{
+			name: "protected account the owner does not follow",
+			setupMocks: func(m *listTestMocks) {
+				m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 1}, nil)
+				m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2, Protected: true}, nil)
+				m.followRepo.On("IsFollowing", 1, 2).Return(false, nil)
+			},
+			errType: &ErrProtectedAccount{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newListTestMocks()
+			tt.setupMocks(m)
+
+			err := m.newService().AddMember(context.Background(), 1, 5, 2)
+
+			if tt.errType != nil {
+				assert.IsType(t, tt.errType, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.assertExpectations(t)
+		})
+	}
+}
+
+func TestListService_RemoveMember(t *testing.T) {
+	m := newListTestMocks()
+	m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 1}, nil)
+	m.listRepo.On("RemoveMember", 5, 2).Return(nil)
+	m.tweetRepo.On("GetTweetIDsByUser", 2).Return([]int64{20, 10}, nil)
+	m.timeline.On("RemoveFromTimelines", []int{5}, []int64{20, 10}).Return(nil)
+
+	err := m.newService().RemoveMember(context.Background(), 1, 5, 2)
+
+	assert.NoError(t, err)
+	m.assertExpectations(t)
+}
+
+func TestListService_GetListTimeline_HidesProtectedMembers(t *testing.T) {
+	m := newListTestMocks()
+	m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 1}, nil)
+	m.timeline.On("GetTimeline", 5, 10).Return([]int64{3, 2, 1}, nil)
+	m.tweetRepo.On("GetByIDs", []int64{3, 2, 1}).Return([]*domain.Tweet{
+		{ID: 3, UserID: 7},
+		{ID: 2, UserID: 8},
+		{ID: 1, UserID: 7},
+	}, nil)
+	m.userRepo.On("GetByIDs", []int{7, 8}).Return([]*domain.User{
+		{ID: 7, Protected: true},
+		{ID: 8},
+	}, nil)
+	m.followRepo.On("IsFollowing", 2, 7).Return(false, nil)
+
+	ids, err := m.newService().GetListTimeline(context.Background(), 2, 5, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids)
+	m.assertExpectations(t)
+}
+
+func TestListService_GetListTimeline_DropsUnreadableTweets(t *testing.T) {
+	m := newListTestMocks()
+	m.listRepo.On("GetByID", 5).Return(&domain.List{ID: 5, OwnerID: 1}, nil)
+	m.timeline.On("GetTimeline", 5, 10).Return([]int64{3, 2, 1}, nil)
+	// 2 was deleted or its author deactivated; the owner sees protected members
+	m.tweetRepo.On("GetByIDs", []int64{3, 2, 1}).Return([]*domain.Tweet{
+		{ID: 3, UserID: 7},
+		{ID: 1, UserID: 8},
+	}, nil)
+
+	ids, err := m.newService().GetListTimeline(context.Background(), 1, 5, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 1}, ids)
+	m.assertExpectations(t)
+}
@@ -0,0 +1,135 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const maxReportReasonLength = 280
+
+// Ways a moderator can resolve a report
+const (
+	ReportActionDismiss     = "dismiss"
+	ReportActionRemoveTweet = "remove_tweet"
+)
+
+// ModerationService lets users report tweets and moderators act on them.
+type ModerationService struct {
+	reportRepo repositories.ReportRepository
+	tweetRepo  repositories.TweetRepository
+	userRepo   repositories.UserRepository
+	tweets     *TweetService
+	policy     *Policy
+}
+
+func NewModerationService(
+	reportRepo repositories.ReportRepository,
+	tweetRepo repositories.TweetRepository,
+	userRepo repositories.UserRepository,
+	tweets *TweetService,
+	policy *Policy,
+) *ModerationService {
+	return &ModerationService{
+		reportRepo: reportRepo,
+		tweetRepo:  tweetRepo,
+		userRepo:   userRepo,
+		tweets:     tweets,
+		policy:     policy,
+	}
+}
+
+// ReportTweet flags a tweet for review. Users can only report tweets they
+// can see, and not their own.
+func (s *ModerationService) ReportTweet(ctx context.Context, reporterID int, tweetID int64, reason string) (*domain.Report, error) {
+	ctx, span := tracer.Start(ctx, "ModerationService.ReportTweet")
+	defer span.End()
+
+	if len(reason) > maxReportReasonLength {
+		return nil, NewErrInvalidInput("reason is too long (max 280 characters)")
+	}
+
+	tweet, err := s.tweets.GetTweet(ctx, reporterID, tweetID)
+	if err != nil {
+		return nil, err
+	}
+	if tweet.UserID == int64(reporterID) {
+		return nil, NewErrInvalidInput("cannot report your own tweet")
+	}
+
+	report := &domain.Report{
+		TweetID:    tweetID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// ListOpenReports returns the reports waiting for review, oldest first.
+func (s *ModerationService) ListOpenReports(ctx context.Context, actorID int, limit int) ([]*domain.Report, error) {
+	ctx, span := tracer.Start(ctx, "ModerationService.ListOpenReports")
+	defer span.End()
+
+	if err := s.requireModerator(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.GetOpen(ctx, limit)
+}
+
+// ResolveReport closes an open report. Removing the tweet also closes every
+// other open report against it.
+func (s *ModerationService) ResolveReport(ctx context.Context, actorID int, reportID int64, action string) error {
+	ctx, span := tracer.Start(ctx, "ModerationService.ResolveReport")
+	defer span.End()
+
+	if action != ReportActionDismiss && action != ReportActionRemoveTweet {
+		return NewErrInvalidInput("action must be dismiss or remove_tweet")
+	}
+
+	if err := s.requireModerator(ctx, actorID); err != nil {
+		return err
+	}
+
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status != domain.ReportStatusOpen {
+		return NewErrReportNotFound(reportID)
+	}
+
+	if action == ReportActionDismiss {
+		return s.reportRepo.Resolve(ctx, reportID, domain.ReportStatusDismissed, actorID)
+	}
+
+	tweet, err := s.tweetRepo.GetByID(ctx, report.TweetID)
+	switch {
+	case err == nil:
+		if err := s.tweets.removeTweet(ctx, tweet); err != nil {
+			return err
+		}
+	case errors.As(err, new(*ErrTweetNotFound)):
+		// The author deleted it already; the reports still need closing
+	default:
+		return err
+	}
+
+	return s.reportRepo.ResolveByTweet(ctx, report.TweetID, domain.ReportStatusActioned, actorID)
+}
+
+func (s *ModerationService) requireModerator(ctx context.Context, actorID int) error {
+	actor, err := loadActor(ctx, s.userRepo, actorID)
+	if err != nil {
+		return err
+	}
+	if !s.policy.CanModerate(actor) {
+		return NewErrForbidden("moderator role required")
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type moderationMocks struct {
+	reportRepo   *application.MockReportRepository
+	tweetRepo    *application.MockTweetRepository
+	userRepo     *application.MockUserRepository
+	followRepo   *application.MockFollowRepository
+	listRepo     *application.MockListRepository
+	timeline     *application.MockTimelineCache
+	listTimeline *application.MockTimelineCache
+}
+
+func newModerationMocks() *moderationMocks {
+	m := &moderationMocks{
+		reportRepo:   new(application.MockReportRepository),
+		tweetRepo:    new(application.MockTweetRepository),
+		userRepo:     new(application.MockUserRepository),
+		followRepo:   new(application.MockFollowRepository),
+		listRepo:     new(application.MockListRepository),
+		timeline:     new(application.MockTimelineCache),
+		listTimeline: new(application.MockTimelineCache),
+	}
+	m.userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Role: domain.RoleUser}, nil).Maybe()
+	m.userRepo.On("GetByID", 2).Return(&domain.User{ID: 2, Role: domain.RoleUser}, nil).Maybe()
+	m.userRepo.On("GetByID", 3).Return(&domain.User{ID: 3, Role: domain.RoleModerator}, nil).Maybe()
+	return m
+}
+
+func (m *moderationMocks) newService() *application.ModerationService {
+	policy := application.NewPolicy()
+	tweets := application.NewTweetService(m.tweetRepo, nil, m.userRepo, m.followRepo, m.listRepo, m.timeline, m.listTimeline, policy)
+	return application.NewModerationService(m.reportRepo, m.tweetRepo, m.userRepo, tweets, policy)
+}
+
+func TestModerationService_ReportTweet(t *testing.T) {
+	tests := []struct {
+		name       string
+		reporterID int
+		reason     string
+		setup      func(*moderationMocks)
+		expectErr  any
+	}{
+		{
+			name:       "user reports a tweet",
+			reporterID: 2,
+			reason:     "spam",
+			setup: func(m *moderationMocks) {
+				m.tweetRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1}, nil)
+				m.reportRepo.On("Create", mock.MatchedBy(func(r *domain.Report) bool {
+					return r.TweetID == 10 && r.ReporterID == 2 && r.Reason == "spam"
+				})).Return(nil)
+			},
+		},
+		{
+			name:       "authors cannot report their own tweet",
+			reporterID: 1,
+			setup: func(m *moderationMocks) {
+				m.tweetRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1}, nil)
+			},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+		{
+			name:       "tweet must exist",
+			reporterID: 2,
+			setup: func(m *moderationMocks) {
+				m.tweetRepo.On("GetByID", int64(10)).Return(nil, application.NewErrTweetNotFound(10))
+			},
+			expectErr: new(*application.ErrTweetNotFound),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModerationMocks()
+			tt.setup(m)
+
+			report, err := m.newService().ReportTweet(context.Background(), tt.reporterID, 10, tt.reason)
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				assert.Nil(t, report)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(10), report.TweetID)
+			}
+			m.reportRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestModerationService_ListOpenReports_RequiresModerator(t *testing.T) {
+	m := newModerationMocks()
+
+	_, err := m.newService().ListOpenReports(context.Background(), 2, 20)
+
+	assert.ErrorAs(t, err, new(*application.ErrForbidden))
+	m.reportRepo.AssertNotCalled(t, "GetOpen", mock.Anything)
+}
+
+func TestModerationService_ResolveReport(t *testing.T) {
+	openReport := func() *domain.Report {
+		return &domain.Report{ID: 5, TweetID: 10, ReporterID: 2, Status: domain.ReportStatusOpen}
+	}
+
+	tests := []struct {
+		name      string
+		actorID   int
+		action    string
+		setup     func(*moderationMocks)
+		expectErr any
+	}{
+		{
+			name:    "moderator dismisses a report",
+			actorID: 3,
+			action:  application.ReportActionDismiss,
+			setup: func(m *moderationMocks) {
+				m.reportRepo.On("GetByID", int64(5)).Return(openReport(), nil)
+				m.reportRepo.On("Resolve", int64(5), domain.ReportStatusDismissed, 3).Return(nil)
+			},
+		},
+		{
+			name:    "moderator removes the reported tweet",
+			actorID: 3,
+			action:  application.ReportActionRemoveTweet,
+			setup: func(m *moderationMocks) {
+				m.reportRepo.On("GetByID", int64(5)).Return(openReport(), nil)
+				m.tweetRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1}, nil)
+				// Cleaning the cached timelines runs in the background
+				m.followRepo.On("GetFollowers", 1).Return([]int{2}, nil).Maybe()
+				m.listRepo.On("GetListIDsByMember", 1).Return([]int{}, nil).Maybe()
+				m.timeline.On("RemoveFromTimelines", []int{2}, []int64{10}).Return(nil).Maybe()
+				m.listTimeline.On("RemoveFromTimelines", []int{}, []int64{10}).Return(nil).Maybe()
+				m.tweetRepo.On("Delete", int64(10)).Return(nil)
+				m.reportRepo.On("ResolveByTweet", int64(10), domain.ReportStatusActioned, 3).Return(nil)
+			},
+		},
+		{
+			name:    "reports of deleted tweets are still closed",
+			actorID: 3,
+			action:  application.ReportActionRemoveTweet,
+			setup: func(m *moderationMocks) {
+				m.reportRepo.On("GetByID", int64(5)).Return(openReport(), nil)
+				m.tweetRepo.On("GetByID", int64(10)).Return(nil, application.NewErrTweetNotFound(10))
+				m.reportRepo.On("ResolveByTweet", int64(10), domain.ReportStatusActioned, 3).Return(nil)
+			},
+		},
+		{
+			name:      "regular users cannot resolve reports",
+			actorID:   2,
+			action:    application.ReportActionRemoveTweet,
+			setup:     func(m *moderationMocks) {},
+			expectErr: new(*application.ErrForbidden),
+		},
+		{
+			name:    "resolved reports cannot be resolved again",
+			actorID: 3,
+			action:  application.ReportActionDismiss,
+			setup: func(m *moderationMocks) {
+				m.reportRepo.On("GetByID", int64(5)).Return(&domain.Report{ID: 5, Status: domain.ReportStatusDismissed}, nil)
+			},
+			expectErr: new(*application.ErrReportNotFound),
+		},
+		{
+			name:      "unknown action",
+			actorID:   3,
+			action:    "ban",
+			setup:     func(m *moderationMocks) {},
+			expectErr: new(*application.ErrInvalidInput),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModerationMocks()
+			tt.setup(m)
+
+			err := m.newService().ResolveReport(context.Background(), tt.actorID, 5, tt.action)
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			m.reportRepo.AssertExpectations(t)
+			m.tweetRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const maxMuteKeywordLength = 100
+
+type MuteService struct {
+	userRepo repositories.UserRepository
+	muteRepo repositories.MuteRepository
+}
+
+func NewMuteService(userRepo repositories.UserRepository, muteRepo repositories.MuteRepository) *MuteService {
+	return &MuteService{
+		userRepo: userRepo,
+		muteRepo: muteRepo,
+	}
+}
+
+type MuteInput struct {
+	Type        domain.MuteType
+	MutedUserID int
+	Keyword     string
+	ExpiresAt   *time.Time
+}
+
+func (s *MuteService) Mute(ctx context.Context, userID int, input MuteInput) (*domain.Mute, error) {
+	ctx, span := tracer.Start(ctx, "MuteService.Mute")
+	defer span.End()
+
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, NewErrInvalidInput("expires_at must be in the future")
+	}
+
+	mute := &domain.Mute{
+		UserID:    userID,
+		Type:      input.Type,
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	switch input.Type {
+	case domain.MuteTypeUser:
+		if input.MutedUserID == userID {
+			return nil, NewErrInvalidInput("cannot mute yourself")
+		}
+		if err := s.ensureUserExists(ctx, input.MutedUserID); err != nil {
+			return nil, err
+		}
+		mute.MutedUserID = input.MutedUserID
+	case domain.MuteTypeKeyword:
+		keyword := strings.TrimSpace(input.Keyword)
+		if keyword == "" || keyword == "#" {
+			return nil, NewErrInvalidInput("keyword cannot be empty")
+		}
+		if utf8.RuneCountInString(keyword) > maxMuteKeywordLength {
+			return nil, NewErrInvalidInput(fmt.Sprintf("keyword is too long (max %d characters)", maxMuteKeywordLength))
+		}
+		mute.Keyword = keyword
+	default:
+		return nil, NewErrInvalidInput(fmt.Sprintf("invalid mute type: %s", input.Type))
+	}
+
+	existing, err := s.muteRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range existing {
+		if !sameMuteTarget(m, mute) {
+			continue
+		}
+		if m.IsActive(time.Now()) {
+			return nil, NewErrAlreadyMuted(userID, mute.Target())
+		}
+		// An expired mute for the same target is replaced by the new one
+		if err := s.muteRepo.Delete(ctx, userID, m.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// A concurrent request may have muted the same target since the check
+	// above; the repository reports it as already muted too
+	if err := s.muteRepo.Create(ctx, mute); err != nil {
+		if errors.As(err, new(*ErrAlreadyMuted)) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create mute: %w", err)
+	}
+
+	return mute, nil
+}
+
+func (s *MuteService) UpdateExpiry(ctx context.Context, userID int, muteID int64, expiresAt *time.Time) error {
+	ctx, span := tracer.Start(ctx, "MuteService.UpdateExpiry")
+	defer span.End()
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return NewErrInvalidInput("expires_at must be in the future")
+	}
+	return s.muteRepo.UpdateExpiry(ctx, userID, muteID, expiresAt)
+}
+
+func (s *MuteService) Unmute(ctx context.Context, userID int, muteID int64) error {
+	ctx, span := tracer.Start(ctx, "MuteService.Unmute")
+	defer span.End()
+
+	return s.muteRepo.Delete(ctx, userID, muteID)
+}
+
+// ListMutes returns the mutes of a user that are still in effect.
+func (s *MuteService) ListMutes(ctx context.Context, userID int) ([]*domain.Mute, error) {
+	ctx, span := tracer.Start(ctx, "MuteService.ListMutes")
+	defer span.End()
+
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	mutes, err := s.muteRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return activeMutes(mutes, time.Now()), nil
+}
+
+func (s *MuteService) ensureUserExists(ctx context.Context, userID int) error {
+	exists, err := s.userRepo.Exists(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return NewErrUserNotFound(userID)
+	}
+	return nil
+}
+
+func activeMutes(mutes []*domain.Mute, now time.Time) []*domain.Mute {
+	active := make([]*domain.Mute, 0, len(mutes))
+	for _, m := range mutes {
+		if m.IsActive(now) {
+			active = append(active, m)
+		}
+	}
+	return active
+}
+
+func sameMuteTarget(a, b *domain.Mute) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	if a.Type == domain.MuteTypeUser {
+		return a.MutedUserID == b.MutedUserID
+	}
+	return strings.EqualFold(a.Keyword, b.Keyword)
+}
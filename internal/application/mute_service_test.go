@@ -0,0 +1,196 @@
+package application_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+func TestMuteService_Mute(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		input      application.MuteInput
+		setupMocks func(*application.MockUserRepository, *application.MockMuteRepository)
+		expectErr  bool
+		errType    error
+	}{
+		{
+			name:  "successful user mute",
+			input: application.MuteInput{Type: domain.MuteTypeUser, MutedUserID: 2, ExpiresAt: &future},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				users.On("Exists", 2).Return(true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				mutes.On("Create", mock.MatchedBy(func(m *domain.Mute) bool {
+					return m.UserID == 1 && m.MutedUserID == 2 && m.ExpiresAt == &future
+				})).Return(nil)
+			},
+		},
+		{
+			name:  "successful keyword mute",
+			input: application.MuteInput{Type: domain.MuteTypeKeyword, Keyword: "  #spoilers "},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				mutes.On("Create", mock.MatchedBy(func(m *domain.Mute) bool {
+					return m.Type == domain.MuteTypeKeyword && m.Keyword == "#spoilers"
+				})).Return(nil)
+			},
+		},
+		{
+			name:  "cannot mute yourself",
+			input: application.MuteInput{Type: domain.MuteTypeUser, MutedUserID: 1},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "muted user not found",
+			input: application.MuteInput{Type: domain.MuteTypeUser, MutedUserID: 999},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				users.On("Exists", 999).Return(false, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrUserNotFound{},
+		},
+		{
+			name:  "empty keyword",
+			input: application.MuteInput{Type: domain.MuteTypeKeyword, Keyword: "   "},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "keyword of multi-byte characters within the limit",
+			input: application.MuteInput{Type: domain.MuteTypeKeyword, Keyword: strings.Repeat("é", 100)},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				mutes.On("Create", mock.AnythingOfType("*domain.Mute")).Return(nil)
+			},
+		},
+		{
+			name:  "keyword too long",
+			input: application.MuteInput{Type: domain.MuteTypeKeyword, Keyword: strings.Repeat("é", 101)},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "expiry in the past",
+			input: application.MuteInput{Type: domain.MuteTypeKeyword, Keyword: "spoilers", ExpiresAt: &past},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "already muted",
+			input: application.MuteInput{Type: domain.MuteTypeKeyword, Keyword: "Spoilers"},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{
+					{ID: 7, UserID: 1, Type: domain.MuteTypeKeyword, Keyword: "spoilers"},
+				}, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrAlreadyMuted{},
+		},
+		{
+			name:  "muted concurrently by another request",
+			input: application.MuteInput{Type: domain.MuteTypeUser, MutedUserID: 2},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				users.On("Exists", 2).Return(true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				mutes.On("Create", mock.AnythingOfType("*domain.Mute")).Return(application.NewErrAlreadyMuted(1, "user 2"))
+			},
+			expectErr: true,
+			errType:   &application.ErrAlreadyMuted{},
+		},
+		{
+			name:  "expired mute for the same target is replaced",
+			input: application.MuteInput{Type: domain.MuteTypeUser, MutedUserID: 2},
+			setupMocks: func(users *application.MockUserRepository, mutes *application.MockMuteRepository) {
+				users.On("Exists", 1).Return(true, nil)
+				users.On("Exists", 2).Return(true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{
+					{ID: 7, UserID: 1, Type: domain.MuteTypeUser, MutedUserID: 2, ExpiresAt: &past},
+				}, nil)
+				mutes.On("Delete", 1, int64(7)).Return(nil)
+				mutes.On("Create", mock.AnythingOfType("*domain.Mute")).Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &application.MockUserRepository{}
+			muteRepo := &application.MockMuteRepository{}
+			tt.setupMocks(userRepo, muteRepo)
+
+			service := application.NewMuteService(userRepo, muteRepo)
+
+			mute, err := service.Mute(context.Background(), 1, tt.input)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.IsType(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, mute)
+			}
+
+			userRepo.AssertExpectations(t)
+			muteRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMuteService_ListMutes(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	userRepo := &application.MockUserRepository{}
+	muteRepo := &application.MockMuteRepository{}
+	userRepo.On("Exists", 1).Return(true, nil)
+	muteRepo.On("GetByUserID", 1).Return([]*domain.Mute{
+		{ID: 1, Type: domain.MuteTypeUser, MutedUserID: 2},
+		{ID: 2, Type: domain.MuteTypeKeyword, Keyword: "old", ExpiresAt: &past},
+	}, nil)
+
+	service := application.NewMuteService(userRepo, muteRepo)
+
+	mutes, err := service.ListMutes(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, mutes, 1)
+	assert.Equal(t, int64(1), mutes[0].ID)
+}
+
+func TestMuteService_Unmute(t *testing.T) {
+	muteRepo := &application.MockMuteRepository{}
+	muteRepo.On("Delete", 1, int64(5)).Return(application.NewErrMuteNotFound(5))
+
+	service := application.NewMuteService(&application.MockUserRepository{}, muteRepo)
+
+	err := service.Unmute(context.Background(), 1, 5)
+	assert.IsType(t, &application.ErrMuteNotFound{}, err)
+}
@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+// Actor is the user a request is made on behalf of.
+type Actor struct {
+	UserID int
+	Role   domain.Role
+}
+
+// Policy decides what an actor may do. Services load the actor and consult
+// the policy before acting, so the rules live in one place and can be tested
+// without HTTP or storage.
+type Policy struct{}
+
+func NewPolicy() *Policy {
+	return &Policy{}
+}
+
+// CanEditTweet reports whether actor may change the content of tweet. Only
+// the author can.
+func (p *Policy) CanEditTweet(actor Actor, tweet *domain.Tweet) bool {
+	return actor.UserID != 0 && int64(actor.UserID) == tweet.UserID
+}
+
+// CanDeleteTweet reports whether actor may delete tweet directly. Only the
+// author can; moderators remove tweets by resolving reports against them.
+func (p *Policy) CanDeleteTweet(actor Actor, tweet *domain.Tweet) bool {
+	return actor.UserID != 0 && int64(actor.UserID) == tweet.UserID
+}
+
+// CanModerate reports whether actor may review reports and remove reported
+// tweets.
+func (p *Policy) CanModerate(actor Actor) bool {
+	return actor.Role == domain.RoleModerator || actor.Role == domain.RoleAdmin
+}
+
+// CanRunMaintenance reports whether actor may trigger maintenance tasks such
+// as refreshing suggestions or purging deactivated accounts.
+func (p *Policy) CanRunMaintenance(actor Actor) bool {
+	return actor.Role == domain.RoleAdmin
+}
+
+// CanAssignRoles reports whether actor may change the role of other users.
+func (p *Policy) CanAssignRoles(actor Actor) bool {
+	return actor.Role == domain.RoleAdmin
+}
+
+func loadActor(ctx context.Context, userRepo repositories.UserRepository, userID int) (Actor, error) {
+	user, err := userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return Actor{}, err
+	}
+	if user == nil {
+		return Actor{}, NewErrUserNotFound(userID)
+	}
+	return Actor{UserID: user.ID, Role: user.Role}, nil
+}
@@ -0,0 +1,68 @@
+package application_test
+
+import (
+	"testing"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := application.NewPolicy()
+	tweet := &domain.Tweet{ID: 10, UserID: 1}
+
+	author := application.Actor{UserID: 1, Role: domain.RoleUser}
+	other := application.Actor{UserID: 2, Role: domain.RoleUser}
+	moderator := application.Actor{UserID: 3, Role: domain.RoleModerator}
+	admin := application.Actor{UserID: 4, Role: domain.RoleAdmin}
+	anonymous := application.Actor{}
+
+	tests := []struct {
+		name           string
+		actor          application.Actor
+		canEdit        bool
+		canDelete      bool
+		canModerate    bool
+		canMaintain    bool
+		canAssignRoles bool
+	}{
+		{
+			name:      "author",
+			actor:     author,
+			canEdit:   true,
+			canDelete: true,
+		},
+		{
+			name:  "other user",
+			actor: other,
+		},
+		{
+			name:        "moderator",
+			actor:       moderator,
+			canModerate: true,
+		},
+		{
+			name:           "admin",
+			actor:          admin,
+			canModerate:    true,
+			canMaintain:    true,
+			canAssignRoles: true,
+		},
+		{
+			name:  "anonymous",
+			actor: anonymous,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.canEdit, policy.CanEditTweet(tt.actor, tweet))
+			assert.Equal(t, tt.canDelete, policy.CanDeleteTweet(tt.actor, tweet))
+			assert.Equal(t, tt.canModerate, policy.CanModerate(tt.actor))
+			assert.Equal(t, tt.canMaintain, policy.CanRunMaintenance(tt.actor))
+			assert.Equal(t, tt.canAssignRoles, policy.CanAssignRoles(tt.actor))
+		})
+	}
+}
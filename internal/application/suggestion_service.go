@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/repositories"
+)
+
+const (
+	// suggestionPoolSize is how many candidates are precomputed per user, so
+	// a page can still be filled after dropping accounts followed or muted
+	// since the last refresh.
+	suggestionPoolSize = 100
+	refreshBatchSize   = 500
+)
+
+type SuggestionService struct {
+	userRepo   repositories.UserRepository
+	followRepo repositories.FollowRepository
+	muteRepo   repositories.MuteRepository
+	cache      repositories.SuggestionCache
+}
+
+func NewSuggestionService(
+	userRepo repositories.UserRepository,
+	followRepo repositories.FollowRepository,
+	muteRepo repositories.MuteRepository,
+	cache repositories.SuggestionCache,
+) *SuggestionService {
+	return &SuggestionService{
+		userRepo:   userRepo,
+		followRepo: followRepo,
+		muteRepo:   muteRepo,
+		cache:      cache,
+	}
+}
+
+// GetSuggestions returns up to limit accounts for the user to follow, best
+// first. Precomputed suggestions are served from the cache; users the
+// refresh job has not reached yet get theirs computed on demand. Followed
+// and muted accounts are excluded here too, as the cache may be stale.
+func (s *SuggestionService) GetSuggestions(ctx context.Context, userID int, limit int) ([]*domain.Suggestion, error) {
+	ctx, span := tracer.Start(ctx, "SuggestionService.GetSuggestions")
+	defer span.End()
+
+	exists, err := s.userRepo.Exists(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, NewErrUserNotFound(userID)
+	}
+
+	suggestions, found, err := s.cache.GetSuggestions(ctx, userID)
+	if err != nil || !found {
+		suggestions, err = s.followRepo.GetFriendsOfFriends(ctx, userID, suggestionPoolSize)
+		if err != nil {
+			return nil, err
+		}
+		// Caching is best effort; the next refresh run fills it in otherwise
+		_ = s.cache.SetSuggestions(ctx, userID, suggestions)
+	}
+
+	mutes, err := s.muteRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	excluded := make(map[int]bool)
+	for _, m := range activeMutes(mutes, time.Now()) {
+		if m.Type == domain.MuteTypeUser {
+			excluded[m.MutedUserID] = true
+		}
+	}
+	following, err := s.followRepo.GetFollowing(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range following {
+		excluded[id] = true
+	}
+
+	result := make([]*domain.Suggestion, 0, min(limit, len(suggestions)))
+	for _, suggestion := range suggestions {
+		if len(result) >= limit {
+			break
+		}
+		if excluded[suggestion.UserID] {
+			continue
+		}
+		result = append(result, suggestion)
+	}
+	return result, nil
+}
+
+// RefreshSuggestions recomputes and caches the suggestions of a user.
+func (s *SuggestionService) RefreshSuggestions(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "SuggestionService.RefreshSuggestions")
+	defer span.End()
+
+	suggestions, err := s.followRepo.GetFriendsOfFriends(ctx, userID, suggestionPoolSize)
+	if err != nil {
+		return err
+	}
+	return s.cache.SetSuggestions(ctx, userID, suggestions)
+}
+
+// RefreshAll recomputes the suggestions of every user. A failure for one user
+// does not stop the run; it is counted in failed instead. The returned error
+// is only set when the users cannot be listed or ctx is done.
+func (s *SuggestionService) RefreshAll(ctx context.Context) (refreshed int, failed int, err error) {
+	ctx, span := tracer.Start(ctx, "SuggestionService.RefreshAll")
+	defer span.End()
+
+	afterID := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return refreshed, failed, err
+		}
+
+		ids, err := s.userRepo.ListIDs(ctx, afterID, refreshBatchSize)
+		if err != nil {
+			return refreshed, failed, err
+		}
+		if len(ids) == 0 {
+			return refreshed, failed, nil
+		}
+
+		for _, id := range ids {
+			if err := s.RefreshSuggestions(ctx, id); err != nil {
+				failed++
+				continue
+			}
+			refreshed++
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
@@ -0,0 +1,151 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+)
+
+func TestSuggestionService_GetSuggestions(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	cached := []*domain.Suggestion{
+		{UserID: 2, Score: 5},
+		{UserID: 3, Score: 4},
+		{UserID: 4, Score: 2},
+		{UserID: 5, Score: 1},
+	}
+
+	tests := []struct {
+		name       string
+		limit      int
+		setupMocks func(*application.MockUserRepository, *application.MockFollowRepository, *application.MockMuteRepository, *application.MockSuggestionCache)
+		expectIDs  []int
+		expectErr  bool
+		errType    error
+	}{
+		{
+			name:  "served from cache",
+			limit: 2,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository, mutes *application.MockMuteRepository, cache *application.MockSuggestionCache) {
+				users.On("Exists", 1).Return(true, nil)
+				cache.On("GetSuggestions", 1).Return(cached, true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				follows.On("GetFollowing", 1).Return([]int{}, nil)
+			},
+			expectIDs: []int{2, 3},
+		},
+		{
+			name:  "muted and since followed accounts are skipped",
+			limit: 2,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository, mutes *application.MockMuteRepository, cache *application.MockSuggestionCache) {
+				users.On("Exists", 1).Return(true, nil)
+				cache.On("GetSuggestions", 1).Return(cached, true, nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{
+					{ID: 1, UserID: 1, Type: domain.MuteTypeUser, MutedUserID: 2},
+					{ID: 2, UserID: 1, Type: domain.MuteTypeUser, MutedUserID: 4, ExpiresAt: &past},
+				}, nil)
+				follows.On("GetFollowing", 1).Return([]int{3}, nil)
+			},
+			expectIDs: []int{4, 5},
+		},
+		{
+			name:  "computed and cached on miss",
+			limit: 10,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository, mutes *application.MockMuteRepository, cache *application.MockSuggestionCache) {
+				computed := []*domain.Suggestion{{UserID: 7, Score: 1}}
+				users.On("Exists", 1).Return(true, nil)
+				cache.On("GetSuggestions", 1).Return(nil, false, nil)
+				follows.On("GetFriendsOfFriends", 1, mock.AnythingOfType("int")).Return(computed, nil)
+				cache.On("SetSuggestions", 1, computed).Return(nil)
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				follows.On("GetFollowing", 1).Return([]int{}, nil)
+			},
+			expectIDs: []int{7},
+		},
+		{
+			name:  "cache errors fall back to the database",
+			limit: 10,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository, mutes *application.MockMuteRepository, cache *application.MockSuggestionCache) {
+				users.On("Exists", 1).Return(true, nil)
+				cache.On("GetSuggestions", 1).Return(nil, false, errors.New("redis down"))
+				follows.On("GetFriendsOfFriends", 1, mock.AnythingOfType("int")).Return([]*domain.Suggestion{}, nil)
+				cache.On("SetSuggestions", 1, mock.Anything).Return(errors.New("redis down"))
+				mutes.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+				follows.On("GetFollowing", 1).Return([]int{}, nil)
+			},
+			expectIDs: []int{},
+		},
+		{
+			name:  "user not found",
+			limit: 10,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository, mutes *application.MockMuteRepository, cache *application.MockSuggestionCache) {
+				users.On("Exists", 1).Return(false, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrUserNotFound{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &application.MockUserRepository{}
+			followRepo := &application.MockFollowRepository{}
+			muteRepo := &application.MockMuteRepository{}
+			cache := &application.MockSuggestionCache{}
+			tt.setupMocks(userRepo, followRepo, muteRepo, cache)
+
+			service := application.NewSuggestionService(userRepo, followRepo, muteRepo, cache)
+
+			suggestions, err := service.GetSuggestions(context.Background(), 1, tt.limit)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.IsType(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				ids := make([]int, len(suggestions))
+				for i, s := range suggestions {
+					ids[i] = s.UserID
+				}
+				assert.Equal(t, tt.expectIDs, ids)
+			}
+
+			userRepo.AssertExpectations(t)
+			followRepo.AssertExpectations(t)
+			muteRepo.AssertExpectations(t)
+			cache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSuggestionService_RefreshAll(t *testing.T) {
+	userRepo := &application.MockUserRepository{}
+	followRepo := &application.MockFollowRepository{}
+	cache := &application.MockSuggestionCache{}
+
+	userRepo.On("ListIDs", 0, mock.AnythingOfType("int")).Return([]int{1, 2}, nil)
+	userRepo.On("ListIDs", 2, mock.AnythingOfType("int")).Return([]int{}, nil)
+	followRepo.On("GetFriendsOfFriends", 1, mock.AnythingOfType("int")).Return([]*domain.Suggestion{{UserID: 3, Score: 1}}, nil)
+	followRepo.On("GetFriendsOfFriends", 2, mock.AnythingOfType("int")).Return(nil, errors.New("db error"))
+	cache.On("SetSuggestions", 1, mock.Anything).Return(nil)
+
+	service := application.NewSuggestionService(userRepo, followRepo, &application.MockMuteRepository{}, cache)
+
+	refreshed, failed, err := service.RefreshAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, refreshed)
+	assert.Equal(t, 1, failed)
+
+	userRepo.AssertExpectations(t)
+	followRepo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
@@ -1,6 +1,9 @@
 package application
 
 import (
+	"context"
+	"time"
+
 	"uala-tweets/internal/domain"
 
 	"github.com/stretchr/testify/mock"
@@ -10,12 +13,12 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) Create(user *domain.User) error {
+func (m *MockUserRepository) Create(ctx context.Context, user *domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetByID(id int) (*domain.User, error) {
+func (m *MockUserRepository) GetByID(ctx context.Context, id int) (*domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -23,34 +26,515 @@ func (m *MockUserRepository) GetByID(id int) (*domain.User, error) {
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Exists(id int) (bool, error) {
+func (m *MockUserRepository) GetByIDs(ctx context.Context, ids []int) ([]*domain.User, error) {
+	args := m.Called(ids)
+	if users, ok := args.Get(0).([]*domain.User); ok {
+		return users, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+	args := m.Called(username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Exists(ctx context.Context, id int) (bool, error) {
 	args := m.Called(id)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockUserRepository) SetProtected(ctx context.Context, id int, protected bool) error {
+	args := m.Called(id, protected)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetRole(ctx context.Context, id int, role domain.Role) error {
+	args := m.Called(id, role)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ListIDs(ctx context.Context, afterID int, limit int) ([]int, error) {
+	args := m.Called(afterID, limit)
+	if ids, ok := args.Get(0).([]int); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) SetDeactivatedAt(ctx context.Context, id int, at *time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetDeactivatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]int, error) {
+	args := m.Called(cutoff, limit)
+	if ids, ok := args.Get(0).([]int); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) DeleteDeactivated(ctx context.Context, id int, cutoff time.Time) (bool, error) {
+	args := m.Called(id, cutoff)
+	return args.Bool(0), args.Error(1)
+}
+
 type MockFollowRepository struct {
 	mock.Mock
 }
 
-func (m *MockFollowRepository) Follow(followerID, followedID int) error {
+func (m *MockFollowRepository) Follow(ctx context.Context, followerID, followedID int) error {
 	args := m.Called(followerID, followedID)
 	return args.Error(0)
 }
 
-func (m *MockFollowRepository) Unfollow(followerID, followedID int) error {
+func (m *MockFollowRepository) Unfollow(ctx context.Context, followerID, followedID int) error {
 	args := m.Called(followerID, followedID)
 	return args.Error(0)
 }
 
-func (m *MockFollowRepository) IsFollowing(followerID, followedID int) (bool, error) {
+func (m *MockFollowRepository) IsFollowing(ctx context.Context, followerID, followedID int) (bool, error) {
 	args := m.Called(followerID, followedID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockFollowRepository) GetFollowers(userID int) ([]int, error) {
+func (m *MockFollowRepository) GetFollowers(ctx context.Context, userID int) ([]int, error) {
+	args := m.Called(userID)
+	if ids, ok := args.Get(0).([]int); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFollowRepository) GetFollowing(ctx context.Context, userID int) ([]int, error) {
+	args := m.Called(userID)
+	if ids, ok := args.Get(0).([]int); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockFollowRepository) GetFriendsOfFriends(ctx context.Context, userID int, limit int) ([]*domain.Suggestion, error) {
+	args := m.Called(userID, limit)
+	if suggestions, ok := args.Get(0).([]*domain.Suggestion); ok {
+		return suggestions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockTweetRepository struct {
+	mock.Mock
+}
+
+func (m *MockTweetRepository) Create(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(tweet)
+	return args.Error(0)
+}
+
+func (m *MockTweetRepository) GetByID(ctx context.Context, id int64) (*domain.Tweet, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Tweet), args.Error(1)
+}
+
+func (m *MockTweetRepository) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Tweet, error) {
+	args := m.Called(ids)
+	if tweets, ok := args.Get(0).([]*domain.Tweet); ok {
+		return tweets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTweetRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Tweet, error) {
+	args := m.Called(userID)
+	if tweets, ok := args.Get(0).([]*domain.Tweet); ok {
+		return tweets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTweetRepository) GetTweetIDsByUser(ctx context.Context, userID int) ([]int64, error) {
+	args := m.Called(userID)
+	if ids, ok := args.Get(0).([]int64); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTweetRepository) Update(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(tweet)
+	return args.Error(0)
+}
+
+func (m *MockTweetRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type MockMuteRepository struct {
+	mock.Mock
+}
+
+func (m *MockMuteRepository) Create(ctx context.Context, mute *domain.Mute) error {
+	args := m.Called(mute)
+	return args.Error(0)
+}
+
+func (m *MockMuteRepository) UpdateExpiry(ctx context.Context, userID int, muteID int64, expiresAt *time.Time) error {
+	args := m.Called(userID, muteID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockMuteRepository) Delete(ctx context.Context, userID int, muteID int64) error {
+	args := m.Called(userID, muteID)
+	return args.Error(0)
+}
+
+func (m *MockMuteRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.Mute, error) {
+	args := m.Called(userID)
+	if mutes, ok := args.Get(0).([]*domain.Mute); ok {
+		return mutes, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockFollowRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockFollowRequestRepository) Create(ctx context.Context, requesterID, targetID int) error {
+	args := m.Called(requesterID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockFollowRequestRepository) Delete(ctx context.Context, requesterID, targetID int) error {
+	args := m.Called(requesterID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockFollowRequestRepository) Approve(ctx context.Context, requesterID, targetID int) error {
+	args := m.Called(requesterID, targetID)
+	return args.Error(0)
+}
+
+func (m *MockFollowRequestRepository) Exists(ctx context.Context, requesterID, targetID int) (bool, error) {
+	args := m.Called(requesterID, targetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockFollowRequestRepository) GetByTargetID(ctx context.Context, targetID int) ([]*domain.FollowRequest, error) {
+	args := m.Called(targetID)
+	if requests, ok := args.Get(0).([]*domain.FollowRequest); ok {
+		return requests, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockSuggestionCache struct {
+	mock.Mock
+}
+
+func (m *MockSuggestionCache) SetSuggestions(ctx context.Context, userID int, suggestions []*domain.Suggestion) error {
+	args := m.Called(userID, suggestions)
+	return args.Error(0)
+}
+
+func (m *MockSuggestionCache) GetSuggestions(ctx context.Context, userID int) ([]*domain.Suggestion, bool, error) {
+	args := m.Called(userID)
+	suggestions, _ := args.Get(0).([]*domain.Suggestion)
+	return suggestions, args.Bool(1), args.Error(2)
+}
+
+type MockListRepository struct {
+	mock.Mock
+}
+
+func (m *MockListRepository) Create(ctx context.Context, list *domain.List) error {
+	args := m.Called(list)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) GetByID(ctx context.Context, id int) (*domain.List, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.List), args.Error(1)
+}
+
+func (m *MockListRepository) GetByOwnerID(ctx context.Context, ownerID int) ([]*domain.List, error) {
+	args := m.Called(ownerID)
+	if lists, ok := args.Get(0).([]*domain.List); ok {
+		return lists, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListRepository) Update(ctx context.Context, list *domain.List) error {
+	args := m.Called(list)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) Delete(ctx context.Context, id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) AddMember(ctx context.Context, listID, userID int) error {
+	args := m.Called(listID, userID)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) RemoveMember(ctx context.Context, listID, userID int) error {
+	args := m.Called(listID, userID)
+	return args.Error(0)
+}
+
+func (m *MockListRepository) GetMembers(ctx context.Context, listID int) ([]int, error) {
+	args := m.Called(listID)
+	if ids, ok := args.Get(0).([]int); ok {
+		return ids, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockListRepository) GetListIDsByMember(ctx context.Context, userID int) ([]int, error) {
 	args := m.Called(userID)
 	if ids, ok := args.Get(0).([]int); ok {
 		return ids, args.Error(1)
 	}
 	return nil, args.Error(1)
 }
+
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *domain.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if token, ok := args.Get(0).(*domain.RefreshToken); ok {
+		return token, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+type MockPasswordHasher struct {
+	mock.Mock
+}
+
+func (m *MockPasswordHasher) Hash(password string) (string, error) {
+	args := m.Called(password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPasswordHasher) Compare(hash, password string) error {
+	args := m.Called(hash, password)
+	return args.Error(0)
+}
+
+type MockTokenIssuer struct {
+	mock.Mock
+}
+
+func (m *MockTokenIssuer) Issue(userID int) (string, time.Time, error) {
+	args := m.Called(userID)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockTokenIssuer) Parse(token string) (int, error) {
+	args := m.Called(token)
+	return args.Int(0), args.Error(1)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	args := m.Called(keyHash)
+	if key, ok := args.Get(0).(*domain.APIKey); ok {
+		return key, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByUserID(ctx context.Context, userID int) ([]*domain.APIKey, error) {
+	args := m.Called(userID)
+	if keys, ok := args.Get(0).([]*domain.APIKey); ok {
+		return keys, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, userID int, id int64) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) SetLastUsedAt(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+type MockTimelineCache struct {
+	mock.Mock
+}
+
+func (m *MockTimelineCache) AddToTimeline(ctx context.Context, userID int, tweetID int64) error {
+	args := m.Called(userID, tweetID)
+	return args.Error(0)
+}
+
+func (m *MockTimelineCache) GetTimeline(ctx context.Context, userID int, limit int) ([]int64, error) {
+	args := m.Called(userID, limit)
+	if timeline, ok := args.Get(0).([]int64); ok {
+		return timeline, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockTimelineCache) ClearTimeline(ctx context.Context, userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockTimelineCache) RemoveFromTimeline(ctx context.Context, userID int, tweetID int64) error {
+	args := m.Called(userID, tweetID)
+	return args.Error(0)
+}
+
+func (m *MockTimelineCache) MergeIntoTimeline(ctx context.Context, userID int, tweetIDs []int64, size int) error {
+	args := m.Called(userID, tweetIDs, size)
+	return args.Error(0)
+}
+
+func (m *MockTimelineCache) RemoveFromTimelines(ctx context.Context, userIDs []int, tweetIDs []int64) error {
+	args := m.Called(userIDs, tweetIDs)
+	return args.Error(0)
+}
+
+type MockTimelineMetrics struct {
+	mock.Mock
+}
+
+func (m *MockTimelineMetrics) TimelineRead(duration time.Duration, hit bool) {
+	m.Called(duration, hit)
+}
+
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) Create(ctx context.Context, report *domain.Report) error {
+	args := m.Called(report)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) GetByID(ctx context.Context, id int64) (*domain.Report, error) {
+	args := m.Called(id)
+	if report, ok := args.Get(0).(*domain.Report); ok {
+		return report, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepository) GetOpen(ctx context.Context, limit int) ([]*domain.Report, error) {
+	args := m.Called(limit)
+	if reports, ok := args.Get(0).([]*domain.Report); ok {
+		return reports, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockReportRepository) Resolve(ctx context.Context, id int64, status domain.ReportStatus, resolvedBy int) error {
+	args := m.Called(id, status, resolvedBy)
+	return args.Error(0)
+}
+
+func (m *MockReportRepository) ResolveByTweet(ctx context.Context, tweetID int64, status domain.ReportStatus, resolvedBy int) error {
+	args := m.Called(tweetID, status, resolvedBy)
+	return args.Error(0)
+}
+
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) Create(ctx context.Context, deadLetter *domain.DeadLetter) error {
+	args := m.Called(deadLetter)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) GetByID(ctx context.Context, id int64) (*domain.DeadLetter, error) {
+	args := m.Called(id)
+	if deadLetter, ok := args.Get(0).(*domain.DeadLetter); ok {
+		return deadLetter, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) List(ctx context.Context, filter domain.DeadLetterFilter) ([]*domain.DeadLetter, error) {
+	args := m.Called(filter)
+	if deadLetters, ok := args.Get(0).([]*domain.DeadLetter); ok {
+		return deadLetters, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) RecordReplay(ctx context.Context, replay *domain.DeadLetterReplay) error {
+	args := m.Called(replay)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) GetReplays(ctx context.Context, deadLetterID int64) ([]*domain.DeadLetterReplay, error) {
+	args := m.Called(deadLetterID)
+	if replays, ok := args.Get(0).([]*domain.DeadLetterReplay); ok {
+		return replays, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+type MockDeadLetterReplayer struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterReplayer) Replay(ctx context.Context, deadLetter *domain.DeadLetter) error {
+	args := m.Called(ctx, deadLetter)
+	return args.Error(0)
+}
@@ -1,25 +1,130 @@
 package application
 
 import (
+	"context"
+	"time"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/ports/metrics"
 	"uala-tweets/internal/ports/repositories"
 )
 
+// maxTimelineScan bounds how many cached entries are inspected when muted or
+// deleted content has to be skipped to fill a page.
+const maxTimelineScan = 1000
+
 type TimelineService struct {
-	cache repositories.TimelineCache
+	cache     repositories.TimelineCache
+	tweetRepo repositories.TweetRepository
+	muteRepo  repositories.MuteRepository
+	metrics   metrics.TimelineMetrics
 }
 
-func NewTimelineService(cache repositories.TimelineCache) *TimelineService {
-	return &TimelineService{cache: cache}
+func NewTimelineService(cache repositories.TimelineCache, tweetRepo repositories.TweetRepository, muteRepo repositories.MuteRepository, timelineMetrics metrics.TimelineMetrics) *TimelineService {
+	return &TimelineService{
+		cache:     cache,
+		tweetRepo: tweetRepo,
+		muteRepo:  muteRepo,
+		metrics:   timelineMetrics,
+	}
 }
 
-func (s *TimelineService) AddTweet(userID int, tweetID int64) error {
-	return s.cache.AddToTimeline(userID, tweetID)
+func (s *TimelineService) AddTweet(ctx context.Context, userID int, tweetID int64) error {
+	ctx, span := tracer.Start(ctx, "TimelineService.AddTweet")
+	defer span.End()
+
+	return s.cache.AddToTimeline(ctx, userID, tweetID)
 }
 
-func (s *TimelineService) GetTimeline(userID int, limit int) ([]int64, error) {
-	return s.cache.GetTimeline(userID, limit)
+// GetTimeline returns the most recent tweet IDs of a user's timeline.
+// Mutes are applied here rather than at fanout time, so removing a mute
+// restores the hidden tweets immediately. Cached tweets that were deleted
+// since, or whose author is deactivated, are left out.
+func (s *TimelineService) GetTimeline(ctx context.Context, userID int, limit int) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "TimelineService.GetTimeline")
+	defer span.End()
+
+	start := time.Now()
+	ids, cached, err := s.readTimeline(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.metrics.TimelineRead(time.Since(start), cached)
+	return ids, nil
+}
+
+// readTimeline reads a page of the timeline, also telling whether the cache
+// held any of it
+func (s *TimelineService) readTimeline(ctx context.Context, userID int, limit int) (ids []int64, cached bool, err error) {
+	mutes, err := s.muteRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	mutes = activeMutes(mutes, time.Now())
+
+	fetch := limit
+	for {
+		ids, err := s.cache.GetTimeline(ctx, userID, fetch)
+		if err != nil {
+			return nil, false, err
+		}
+		cached = cached || len(ids) > 0
+
+		visible, err := s.filterVisible(ctx, ids, mutes)
+		if err != nil {
+			return nil, false, err
+		}
+
+		// Stop once the page is full, the timeline is exhausted or the scan limit is hit
+		if len(visible) >= limit || len(ids) < fetch || fetch >= maxTimelineScan {
+			if len(visible) > limit {
+				visible = visible[:limit]
+			}
+			return visible, cached, nil
+		}
+
+		fetch = min(fetch*2, maxTimelineScan)
+	}
+}
+
+func (s *TimelineService) ClearTimeline(ctx context.Context, userID int) error {
+	ctx, span := tracer.Start(ctx, "TimelineService.ClearTimeline")
+	defer span.End()
+
+	return s.cache.ClearTimeline(ctx, userID)
+}
+
+// filterVisible drops the tweets that are muted or no longer readable
+func (s *TimelineService) filterVisible(ctx context.Context, ids []int64, mutes []*domain.Mute) ([]int64, error) {
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	tweets, err := s.tweetRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.Tweet, len(tweets))
+	for _, t := range tweets {
+		byID[t.ID] = t
+	}
+
+	visible := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		tweet, ok := byID[id]
+		if !ok || isMuted(tweet, mutes) {
+			continue
+		}
+		visible = append(visible, id)
+	}
+	return visible, nil
 }
 
-func (s *TimelineService) ClearTimeline(userID int) error {
-	return s.cache.ClearTimeline(userID)
+func isMuted(tweet *domain.Tweet, mutes []*domain.Mute) bool {
+	for _, m := range mutes {
+		if m.Matches(tweet) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,78 +1,189 @@
 package application
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
+
+	"uala-tweets/internal/domain"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
-type MockTimelineCache struct {
-	mock.Mock
-}
-
-func (m *MockTimelineCache) AddToTimeline(userID int, tweetID int64) error {
-	args := m.Called(userID, tweetID)
-	return args.Error(0)
-}
-func (m *MockTimelineCache) GetTimeline(userID int, limit int) ([]int64, error) {
-	args := m.Called(userID, limit)
-	if timeline, ok := args.Get(0).([]int64); ok {
-		return timeline, args.Error(1)
-	}
-	return nil, args.Error(1)
-}
-func (m *MockTimelineCache) ClearTimeline(userID int) error {
-	args := m.Called(userID)
-	return args.Error(0)
-}
-
-func (m *MockTimelineCache) RemoveFromTimeline(userID int, tweetID int64) error {
-	args := m.Called(userID, tweetID)
-	return args.Error(0)
+func newTestTimelineService(cache *MockTimelineCache) (*TimelineService, *MockTweetRepository, *MockMuteRepository) {
+	tweetRepo := new(MockTweetRepository)
+	muteRepo := new(MockMuteRepository)
+	metrics := new(MockTimelineMetrics)
+	metrics.On("TimelineRead", mock.Anything, mock.Anything).Maybe()
+	return NewTimelineService(cache, tweetRepo, muteRepo, metrics), tweetRepo, muteRepo
 }
 
 func TestTimelineService_AddTweet(t *testing.T) {
 	mockCache := new(MockTimelineCache)
-	service := NewTimelineService(mockCache)
+	service, _, _ := newTestTimelineService(mockCache)
 	mockCache.On("AddToTimeline", 1, int64(42)).Return(nil)
-	err := service.AddTweet(1, 42)
+	err := service.AddTweet(context.Background(), 1, 42)
 	assert.NoError(t, err)
 	mockCache.AssertCalled(t, "AddToTimeline", 1, int64(42))
 }
 
 func TestTimelineService_GetTimeline(t *testing.T) {
 	mockCache := new(MockTimelineCache)
-	service := NewTimelineService(mockCache)
+	service, tweetRepo, muteRepo := newTestTimelineService(mockCache)
+	muteRepo.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
 	mockCache.On("GetTimeline", 1, 10).Return([]int64{101, 102}, nil)
-	timeline, err := service.GetTimeline(1, 10)
+	tweetRepo.On("GetByIDs", []int64{101, 102}).Return([]*domain.Tweet{{ID: 101}, {ID: 102}}, nil)
+	timeline, err := service.GetTimeline(context.Background(), 1, 10)
 	assert.NoError(t, err)
 	assert.Equal(t, []int64{101, 102}, timeline)
 	mockCache.AssertCalled(t, "GetTimeline", 1, 10)
 }
 
+func TestTimelineService_GetTimeline_DropsUnreadableTweets(t *testing.T) {
+	mockCache := new(MockTimelineCache)
+	service, tweetRepo, muteRepo := newTestTimelineService(mockCache)
+	muteRepo.On("GetByUserID", 1).Return([]*domain.Mute{}, nil)
+	// 102 was deleted or its author deactivated after it was fanned out
+	mockCache.On("GetTimeline", 1, 2).Return([]int64{103, 102}, nil)
+	tweetRepo.On("GetByIDs", []int64{103, 102}).Return([]*domain.Tweet{{ID: 103}}, nil)
+	mockCache.On("GetTimeline", 1, 4).Return([]int64{103, 102, 101}, nil)
+	tweetRepo.On("GetByIDs", []int64{103, 102, 101}).Return([]*domain.Tweet{{ID: 103}, {ID: 101}}, nil)
+
+	timeline, err := service.GetTimeline(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{103, 101}, timeline)
+	mockCache.AssertExpectations(t)
+}
+
+func TestTimelineService_GetTimeline_RecordsCacheHits(t *testing.T) {
+	mockCache := new(MockTimelineCache)
+	muteRepo := new(MockMuteRepository)
+	metrics := new(MockTimelineMetrics)
+	tweetRepo := new(MockTweetRepository)
+	service := NewTimelineService(mockCache, tweetRepo, muteRepo, metrics)
+	muteRepo.On("GetByUserID", mock.Anything).Return([]*domain.Mute{}, nil)
+	mockCache.On("GetTimeline", 1, 10).Return([]int64{101}, nil)
+	tweetRepo.On("GetByIDs", []int64{101}).Return([]*domain.Tweet{{ID: 101}}, nil)
+	mockCache.On("GetTimeline", 2, 10).Return([]int64{}, nil)
+	mockCache.On("GetTimeline", 3, 10).Return(nil, errors.New("fail"))
+	metrics.On("TimelineRead", mock.Anything, true).Once()
+	metrics.On("TimelineRead", mock.Anything, false).Once()
+
+	_, err := service.GetTimeline(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	_, err = service.GetTimeline(context.Background(), 2, 10)
+	assert.NoError(t, err)
+	// Failed reads are not recorded
+	_, err = service.GetTimeline(context.Background(), 3, 10)
+	assert.Error(t, err)
+
+	metrics.AssertExpectations(t)
+}
+
 func TestTimelineService_ClearTimeline(t *testing.T) {
 	mockCache := new(MockTimelineCache)
-	service := NewTimelineService(mockCache)
+	service, _, _ := newTestTimelineService(mockCache)
 	mockCache.On("ClearTimeline", 1).Return(nil)
-	err := service.ClearTimeline(1)
+	err := service.ClearTimeline(context.Background(), 1)
 	assert.NoError(t, err)
 	mockCache.AssertCalled(t, "ClearTimeline", 1)
 }
 
 func TestTimelineService_Errors(t *testing.T) {
 	mockCache := new(MockTimelineCache)
-	service := NewTimelineService(mockCache)
+	service, _, muteRepo := newTestTimelineService(mockCache)
+	muteRepo.On("GetByUserID", 2).Return([]*domain.Mute{}, nil)
 	mockCache.On("AddToTimeline", 2, int64(43)).Return(errors.New("fail"))
-	err := service.AddTweet(2, 43)
+	err := service.AddTweet(context.Background(), 2, 43)
 	assert.Error(t, err)
 
 	mockCache.On("GetTimeline", 2, 5).Return(nil, errors.New("fail"))
-	_, err = service.GetTimeline(2, 5)
+	_, err = service.GetTimeline(context.Background(), 2, 5)
 	assert.Error(t, err)
 
 	mockCache.On("ClearTimeline", 2).Return(errors.New("fail"))
-	err = service.ClearTimeline(2)
+	err = service.ClearTimeline(context.Background(), 2)
 	assert.Error(t, err)
 }
+
+func TestTimelineService_GetTimeline_FiltersMutes(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	tweets := []*domain.Tweet{
+		{ID: 105, UserID: 3, Content: "hello from a muted user"},
+		{ID: 104, UserID: 4, Content: "Spoilers ahead!"},
+		{ID: 103, UserID: 4, Content: "shipping #golang today"},
+		{ID: 102, UserID: 4, Content: "talking about #go"},
+		{ID: 101, UserID: 5, Content: "from user 5, whose mute expired"},
+	}
+
+	tests := []struct {
+		name     string
+		mutes    []*domain.Mute
+		limit    int
+		expected []int64
+	}{
+		{
+			name:     "user mute hides the author's tweets",
+			mutes:    []*domain.Mute{{Type: domain.MuteTypeUser, MutedUserID: 3}},
+			limit:    10,
+			expected: []int64{104, 103, 102, 101},
+		},
+		{
+			name:     "keyword mute is case insensitive",
+			mutes:    []*domain.Mute{{Type: domain.MuteTypeKeyword, Keyword: "spoilers"}},
+			limit:    10,
+			expected: []int64{105, 103, 102, 101},
+		},
+		{
+			name:     "hashtag mute only matches the whole hashtag",
+			mutes:    []*domain.Mute{{Type: domain.MuteTypeKeyword, Keyword: "#Go"}},
+			limit:    10,
+			expected: []int64{105, 104, 103, 101},
+		},
+		{
+			name:     "expired mute is ignored",
+			mutes:    []*domain.Mute{{Type: domain.MuteTypeUser, MutedUserID: 5, ExpiresAt: &expired}},
+			limit:    10,
+			expected: []int64{105, 104, 103, 102, 101},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCache := new(MockTimelineCache)
+			service, tweetRepo, muteRepo := newTestTimelineService(mockCache)
+			muteRepo.On("GetByUserID", 1).Return(tt.mutes, nil)
+			mockCache.On("GetTimeline", 1, tt.limit).Return([]int64{105, 104, 103, 102, 101}, nil)
+			tweetRepo.On("GetByIDs", []int64{105, 104, 103, 102, 101}).Return(tweets, nil)
+
+			timeline, err := service.GetTimeline(context.Background(), 1, tt.limit)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, timeline)
+		})
+	}
+}
+
+func TestTimelineService_GetTimeline_FetchesMoreWhenMuted(t *testing.T) {
+	mockCache := new(MockTimelineCache)
+	service, tweetRepo, muteRepo := newTestTimelineService(mockCache)
+	muteRepo.On("GetByUserID", 1).Return([]*domain.Mute{{Type: domain.MuteTypeUser, MutedUserID: 3}}, nil)
+
+	mockCache.On("GetTimeline", 1, 2).Return([]int64{104, 103}, nil)
+	tweetRepo.On("GetByIDs", []int64{104, 103}).Return([]*domain.Tweet{
+		{ID: 104, UserID: 3},
+		{ID: 103, UserID: 4},
+	}, nil)
+	mockCache.On("GetTimeline", 1, 4).Return([]int64{104, 103, 102}, nil)
+	tweetRepo.On("GetByIDs", []int64{104, 103, 102}).Return([]*domain.Tweet{
+		{ID: 104, UserID: 3},
+		{ID: 103, UserID: 4},
+		{ID: 102, UserID: 4},
+	}, nil)
+
+	timeline, err := service.GetTimeline(context.Background(), 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{103, 102}, timeline)
+	mockCache.AssertExpectations(t)
+}
@@ -0,0 +1,6 @@
+package application
+
+import "go.opentelemetry.io/otel"
+
+// tracer starts a span for each call to a service, named after its method
+var tracer = otel.Tracer("uala-tweets/internal/application")
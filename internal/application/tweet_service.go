@@ -3,26 +3,53 @@ package application
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"time"
 	"uala-tweets/internal/domain"
 	"uala-tweets/internal/ports/publishers"
 	"uala-tweets/internal/ports/repositories"
 )
 
+// timelineCleanupTimeout bounds taking a deleted tweet out of the cached
+// timelines of its author's followers
+const timelineCleanupTimeout = time.Minute
+
 var (
 	ErrTweetContentEmpty   = errors.New("tweet content cannot be empty")
 	ErrTweetContentTooLong = errors.New("tweet content is too long (max 280 characters)")
 )
 
 type TweetService struct {
-	tweetRepo repositories.TweetRepository
-	tweetPub  publishers.TweetPublisher
+	tweetRepo  repositories.TweetRepository
+	tweetPub   publishers.TweetPublisher
+	userRepo   repositories.UserRepository
+	followRepo repositories.FollowRepository
+	timelines  timelineRemover
+	policy     *Policy
 }
 
-func NewTweetService(tweetRepo repositories.TweetRepository, tweetPub publishers.TweetPublisher) *TweetService {
+func NewTweetService(
+	tweetRepo repositories.TweetRepository,
+	tweetPub publishers.TweetPublisher,
+	userRepo repositories.UserRepository,
+	followRepo repositories.FollowRepository,
+	listRepo repositories.ListRepository,
+	timelineCache repositories.TimelineCache,
+	listTimelineCache repositories.TimelineCache,
+	policy *Policy,
+) *TweetService {
 	return &TweetService{
-		tweetRepo: tweetRepo,
-		tweetPub:  tweetPub,
+		tweetRepo:  tweetRepo,
+		tweetPub:   tweetPub,
+		userRepo:   userRepo,
+		followRepo: followRepo,
+		timelines: timelineRemover{
+			followRepo:        followRepo,
+			listRepo:          listRepo,
+			timelineCache:     timelineCache,
+			listTimelineCache: listTimelineCache,
+		},
+		policy: policy,
 	}
 }
 
@@ -32,23 +59,24 @@ type CreateTweetInput struct {
 }
 
 func (s *TweetService) CreateTweet(ctx context.Context, input CreateTweetInput) (*domain.Tweet, error) {
-	if input.Content == "" {
-		return nil, ErrTweetContentEmpty
-	}
+	ctx, span := tracer.Start(ctx, "TweetService.CreateTweet")
+	defer span.End()
 
-	if len(input.Content) > 280 {
-		return nil, ErrTweetContentTooLong
+	if err := validateTweetContent(input.Content); err != nil {
+		return nil, err
 	}
 
 	tweet := &domain.Tweet{
 		UserID:    input.UserID,
 		Content:   input.Content,
 		CreatedAt: time.Now(),
+		EventID:   domain.NewEventID(),
 	}
 
-	// Publish tweet asynchronously (event-driven persistence)
+	// Publish tweet asynchronously (event-driven persistence), outliving the
+	// request but keeping its trace
 	go func(tweet *domain.Tweet) {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
 		defer cancel()
 		_ = s.tweetPub.Publish(ctx, tweet)
 	}(tweet)
@@ -56,10 +84,181 @@ func (s *TweetService) CreateTweet(ctx context.Context, input CreateTweetInput)
 	return tweet, nil
 }
 
-func (s *TweetService) GetTweet(ctx context.Context, id int64) (*domain.Tweet, error) {
-	return s.tweetRepo.GetByID(id)
+// GetTweet returns a tweet as seen by viewerID (0 for anonymous viewers).
+// Tweets of protected accounts are only visible to the author and followers.
+func (s *TweetService) GetTweet(ctx context.Context, viewerID int, id int64) (*domain.Tweet, error) {
+	ctx, span := tracer.Start(ctx, "TweetService.GetTweet")
+	defer span.End()
+
+	tweet, err := s.tweetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkVisibility(ctx, viewerID, int(tweet.UserID)); err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+func (s *TweetService) GetUserTweets(ctx context.Context, viewerID int, userID int64) ([]*domain.Tweet, error) {
+	ctx, span := tracer.Start(ctx, "TweetService.GetUserTweets")
+	defer span.End()
+
+	if err := s.checkVisibility(ctx, viewerID, int(userID)); err != nil {
+		return nil, err
+	}
+
+	return s.tweetRepo.GetByUserID(ctx, userID)
+}
+
+// EditTweet replaces the content of a tweet. Only its author may edit it.
+func (s *TweetService) EditTweet(ctx context.Context, actorID int, id int64, content string) (*domain.Tweet, error) {
+	ctx, span := tracer.Start(ctx, "TweetService.EditTweet")
+	defer span.End()
+
+	if err := validateTweetContent(content); err != nil {
+		return nil, err
+	}
+
+	tweet, err := s.tweetRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := loadActor(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.policy.CanEditTweet(actor, tweet) {
+		return nil, NewErrForbidden("only the author can edit this tweet")
+	}
+
+	tweet.Content = content
+	if err := s.tweetRepo.Update(ctx, tweet); err != nil {
+		return nil, err
+	}
+
+	return tweet, nil
+}
+
+// DeleteTweet deletes a tweet and, in the background, takes it out of the
+// cached timelines it was fanned out to. Only its author may delete it.
+func (s *TweetService) DeleteTweet(ctx context.Context, actorID int, id int64) error {
+	ctx, span := tracer.Start(ctx, "TweetService.DeleteTweet")
+	defer span.End()
+
+	tweet, err := s.tweetRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	actor, err := loadActor(ctx, s.userRepo, actorID)
+	if err != nil {
+		return err
+	}
+	if !s.policy.CanDeleteTweet(actor, tweet) {
+		return NewErrForbidden("only the author can delete this tweet")
+	}
+
+	return s.removeTweet(ctx, tweet)
+}
+
+// removeTweet deletes a tweet without checking who asks for it. Taking it
+// out of cached timelines takes a round trip per hundred followers, so it
+// outlives the request instead of holding it up; timeline reads already
+// skip tweets that no longer exist.
+func (s *TweetService) removeTweet(ctx context.Context, tweet *domain.Tweet) error {
+	if err := s.tweetRepo.Delete(ctx, tweet.ID); err != nil {
+		return err
+	}
+
+	go func(tweet *domain.Tweet) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timelineCleanupTimeout)
+		defer cancel()
+		if err := s.timelines.remove(ctx, int(tweet.UserID), []int64{tweet.ID}); err != nil {
+			slog.ErrorContext(ctx, "Failed to remove deleted tweet from cached timelines", "tweet_id", tweet.ID, "error", err)
+		}
+	}(tweet)
+
+	return nil
+}
+
+func validateTweetContent(content string) error {
+	if content == "" {
+		return ErrTweetContentEmpty
+	}
+	if len(content) > 280 {
+		return ErrTweetContentTooLong
+	}
+	return nil
+}
+
+func (s *TweetService) checkVisibility(ctx context.Context, viewerID, authorID int) error {
+	if viewerID == authorID {
+		return nil
+	}
+
+	author, err := s.userRepo.GetByID(ctx, authorID)
+	if err != nil {
+		return err
+	}
+	// Deactivated accounts are hidden as if already deleted
+	if author == nil || author.DeactivatedAt != nil {
+		return NewErrUserNotFound(authorID)
+	}
+	if !author.Protected {
+		return nil
+	}
+
+	if viewerID != 0 {
+		isFollowing, err := s.followRepo.IsFollowing(ctx, viewerID, authorID)
+		if err != nil {
+			return err
+		}
+		if isFollowing {
+			return nil
+		}
+	}
+
+	return NewErrProtectedAccount(authorID)
+}
+
+// timelineRemover takes tweets out of the cached timelines they were fanned
+// out to: those of the author's followers and of the lists the author is in.
+// Both caches are cleaned even if one fails, and the failures are returned
+// together.
+type timelineRemover struct {
+	followRepo        repositories.FollowRepository
+	listRepo          repositories.ListRepository
+	timelineCache     repositories.TimelineCache
+	listTimelineCache repositories.TimelineCache
+}
+
+func (r timelineRemover) remove(ctx context.Context, authorID int, tweetIDs []int64) error {
+	var errs []error
+
+	followers, err := r.followRepo.GetFollowers(ctx, authorID)
+	if err == nil {
+		err = r.timelineCache.RemoveFromTimelines(ctx, followers, tweetIDs)
+	}
+	errs = append(errs, err)
+
+	listIDs, err := r.listRepo.GetListIDsByMember(ctx, authorID)
+	if err == nil {
+		err = r.listTimelineCache.RemoveFromTimelines(ctx, listIDs, tweetIDs)
+	}
+	errs = append(errs, err)
+
+	return errors.Join(errs...)
 }
 
-func (s *TweetService) GetUserTweets(ctx context.Context, userID int64) ([]*domain.Tweet, error) {
-	return s.tweetRepo.GetByUserID(userID)
+// removeFrom removes the tweets from the timelines of followers and lists
+// already looked up, trying both caches even if one fails
+func (r timelineRemover) removeFrom(ctx context.Context, followers, listIDs []int, tweetIDs []int64) error {
+	return errors.Join(
+		r.timelineCache.RemoveFromTimelines(ctx, followers, tweetIDs),
+		r.listTimelineCache.RemoveFromTimelines(ctx, listIDs, tweetIDs),
+	)
 }
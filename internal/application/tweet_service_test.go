@@ -18,12 +18,12 @@ type MockTweetRepository struct {
 	mock.Mock
 }
 
-func (m *MockTweetRepository) Create(tweet *domain.Tweet) error {
+func (m *MockTweetRepository) Create(ctx context.Context, tweet *domain.Tweet) error {
 	args := m.Called(tweet)
 	return args.Error(0)
 }
 
-func (m *MockTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
+func (m *MockTweetRepository) GetByID(ctx context.Context, id int64) (*domain.Tweet, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -31,7 +31,15 @@ func (m *MockTweetRepository) GetByID(id int64) (*domain.Tweet, error) {
 	return args.Get(0).(*domain.Tweet), args.Error(1)
 }
 
-func (m *MockTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet, error) {
+func (m *MockTweetRepository) GetByIDs(ctx context.Context, ids []int64) ([]*domain.Tweet, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Tweet), args.Error(1)
+}
+
+func (m *MockTweetRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Tweet, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -39,7 +47,7 @@ func (m *MockTweetRepository) GetByUserID(userID int64) ([]*domain.Tweet, error)
 	return args.Get(0).([]*domain.Tweet), args.Error(1)
 }
 
-func (m *MockTweetRepository) GetTweetIDsByUser(userID int) ([]int64, error) {
+func (m *MockTweetRepository) GetTweetIDsByUser(ctx context.Context, userID int) ([]int64, error) {
 	args := m.Called(userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -47,6 +55,16 @@ func (m *MockTweetRepository) GetTweetIDsByUser(userID int) ([]int64, error) {
 	return args.Get(0).([]int64), args.Error(1)
 }
 
+func (m *MockTweetRepository) Update(ctx context.Context, tweet *domain.Tweet) error {
+	args := m.Called(tweet)
+	return args.Error(0)
+}
+
+func (m *MockTweetRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
 type MockTweetPublisher struct {
 	mock.Mock
 }
@@ -121,7 +139,7 @@ func TestTweetService_CreateTweet(t *testing.T) {
 
 			tt.mockSetup(mockRepo, mockPub, &wg)
 
-			service := application.NewTweetService(mockRepo, mockPub)
+			service := application.NewTweetService(mockRepo, mockPub, &application.MockUserRepository{}, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
 
 			tweet, err := service.CreateTweet(context.Background(), tt.input)
 
@@ -201,8 +219,8 @@ func TestTweetService_GetTweet(t *testing.T) {
 
 			tt.setupMock(mockRepo)
 
-			service := application.NewTweetService(mockRepo, mockPub)
-			tweet, err := service.GetTweet(context.Background(), tt.tweetID)
+			service := application.NewTweetService(mockRepo, mockPub, &application.MockUserRepository{}, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
+			tweet, err := service.GetTweet(context.Background(), 1, tt.tweetID)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
@@ -277,9 +295,12 @@ func TestTweetService_GetUserTweets(t *testing.T) {
 
 			tt.setupMock(mockRepo)
 
-			service := application.NewTweetService(mockRepo, mockPub)
+			userRepo := &application.MockUserRepository{}
+			userRepo.On("GetByID", int(tt.userID)).Return(&domain.User{ID: int(tt.userID)}, nil)
+
+			service := application.NewTweetService(mockRepo, mockPub, userRepo, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
 
-			tweets, err := service.GetUserTweets(context.Background(), tt.userID)
+			tweets, err := service.GetUserTweets(context.Background(), 0, tt.userID)
 
 			if tt.expectedError != "" {
 				assert.ErrorContains(t, err, tt.expectedError)
@@ -298,3 +319,183 @@ func TestTweetService_GetUserTweets(t *testing.T) {
 		})
 	}
 }
+
+func TestTweetService_GetTweet_ProtectedAccount(t *testing.T) {
+	tests := []struct {
+		name       string
+		viewerID   int
+		setupMocks func(*application.MockUserRepository, *application.MockFollowRepository)
+		expectErr  bool
+	}{
+		{
+			name:     "author can read their own tweet",
+			viewerID: 1,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository) {
+			},
+		},
+		{
+			name:     "follower can read the tweet",
+			viewerID: 2,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository) {
+				users.On("GetByID", 1).Return(&domain.User{ID: 1, Protected: true}, nil)
+				follows.On("IsFollowing", 2, 1).Return(true, nil)
+			},
+		},
+		{
+			name:     "non follower cannot read the tweet",
+			viewerID: 3,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository) {
+				users.On("GetByID", 1).Return(&domain.User{ID: 1, Protected: true}, nil)
+				follows.On("IsFollowing", 3, 1).Return(false, nil)
+			},
+			expectErr: true,
+		},
+		{
+			name:     "anonymous viewer cannot read the tweet",
+			viewerID: 0,
+			setupMocks: func(users *application.MockUserRepository, follows *application.MockFollowRepository) {
+				users.On("GetByID", 1).Return(&domain.User{ID: 1, Protected: true}, nil)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTweetRepository)
+			mockRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1, Content: "secret"}, nil)
+			userRepo := &application.MockUserRepository{}
+			followRepo := &application.MockFollowRepository{}
+			tt.setupMocks(userRepo, followRepo)
+
+			service := application.NewTweetService(mockRepo, new(MockTweetPublisher), userRepo, followRepo, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
+			tweet, err := service.GetTweet(context.Background(), tt.viewerID, 10)
+
+			if tt.expectErr {
+				assert.IsType(t, &application.ErrProtectedAccount{}, err)
+				assert.Nil(t, tweet)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(10), tweet.ID)
+			}
+
+			userRepo.AssertExpectations(t)
+			followRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTweetService_GetTweet_DeactivatedAuthor(t *testing.T) {
+	deactivatedAt := time.Now().Add(-time.Hour)
+	mockRepo := new(MockTweetRepository)
+	mockRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1}, nil)
+	userRepo := &application.MockUserRepository{}
+	userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, DeactivatedAt: &deactivatedAt}, nil)
+
+	service := application.NewTweetService(mockRepo, new(MockTweetPublisher), userRepo, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
+
+	_, err := service.GetTweet(context.Background(), 2, 10)
+	assert.IsType(t, &application.ErrUserNotFound{}, err)
+
+	// The author still sees their own tweets
+	tweet, err := service.GetTweet(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), tweet.ID)
+}
+
+func TestTweetService_EditTweet(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorID   int
+		content   string
+		setup     func(*MockTweetRepository)
+		expectErr any
+	}{
+		{
+			name:    "author edits their tweet",
+			actorID: 1,
+			content: "edited",
+			setup: func(repo *MockTweetRepository) {
+				repo.On("Update", mock.MatchedBy(func(tweet *domain.Tweet) bool { return tweet.Content == "edited" })).Return(nil)
+			},
+		},
+		{
+			name:      "other users cannot edit the tweet",
+			actorID:   2,
+			content:   "edited",
+			setup:     func(repo *MockTweetRepository) {},
+			expectErr: new(*application.ErrForbidden),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockTweetRepository)
+			mockRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1, Content: "original"}, nil)
+			tt.setup(mockRepo)
+			userRepo := &application.MockUserRepository{}
+			userRepo.On("GetByID", tt.actorID).Return(&domain.User{ID: tt.actorID, Role: domain.RoleAdmin}, nil)
+
+			service := application.NewTweetService(mockRepo, new(MockTweetPublisher), userRepo, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
+			tweet, err := service.EditTweet(context.Background(), tt.actorID, 10, tt.content)
+
+			if tt.expectErr != nil {
+				assert.ErrorAs(t, err, tt.expectErr)
+				assert.Nil(t, tweet)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "edited", tweet.Content)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("content is validated", func(t *testing.T) {
+		service := application.NewTweetService(new(MockTweetRepository), new(MockTweetPublisher), &application.MockUserRepository{}, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
+		_, err := service.EditTweet(context.Background(), 1, 10, "")
+		assert.ErrorIs(t, err, application.ErrTweetContentEmpty)
+	})
+}
+
+func TestTweetService_DeleteTweet(t *testing.T) {
+	t.Run("author deletes their tweet", func(t *testing.T) {
+		tweetRepo := new(MockTweetRepository)
+		tweetRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1}, nil)
+		tweetRepo.On("Delete", int64(10)).Return(nil)
+		userRepo := &application.MockUserRepository{}
+		userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Role: domain.RoleUser}, nil)
+		followRepo := &application.MockFollowRepository{}
+		followRepo.On("GetFollowers", 1).Return([]int{2}, nil)
+		listRepo := &application.MockListRepository{}
+		listRepo.On("GetListIDsByMember", 1).Return([]int{7}, nil)
+		// The cached timelines are cleaned after DeleteTweet returns
+		var cleaned sync.WaitGroup
+		cleaned.Add(2)
+		timeline := &application.MockTimelineCache{}
+		timeline.On("RemoveFromTimelines", []int{2}, []int64{10}).Run(func(mock.Arguments) { cleaned.Done() }).Return(nil)
+		listTimeline := &application.MockTimelineCache{}
+		listTimeline.On("RemoveFromTimelines", []int{7}, []int64{10}).Run(func(mock.Arguments) { cleaned.Done() }).Return(nil)
+
+		service := application.NewTweetService(tweetRepo, new(MockTweetPublisher), userRepo, followRepo, listRepo, timeline, listTimeline, application.NewPolicy())
+		err := service.DeleteTweet(context.Background(), 1, 10)
+
+		assert.NoError(t, err)
+		cleaned.Wait()
+		tweetRepo.AssertExpectations(t)
+		timeline.AssertExpectations(t)
+		listTimeline.AssertExpectations(t)
+	})
+
+	t.Run("moderators cannot delete tweets directly", func(t *testing.T) {
+		tweetRepo := new(MockTweetRepository)
+		tweetRepo.On("GetByID", int64(10)).Return(&domain.Tweet{ID: 10, UserID: 1}, nil)
+		userRepo := &application.MockUserRepository{}
+		userRepo.On("GetByID", 3).Return(&domain.User{ID: 3, Role: domain.RoleModerator}, nil)
+
+		service := application.NewTweetService(tweetRepo, new(MockTweetPublisher), userRepo, &application.MockFollowRepository{}, &application.MockListRepository{}, &application.MockTimelineCache{}, &application.MockTimelineCache{}, application.NewPolicy())
+		err := service.DeleteTweet(context.Background(), 3, 10)
+
+		assert.ErrorAs(t, err, new(*application.ErrForbidden))
+		tweetRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
@@ -1,14 +1,28 @@
 package application
 
 import (
-	"errors"
+	"context"
 	"fmt"
-	"time"
+	"net/url"
+	"regexp"
+	"strings"
 
 	"uala-tweets/internal/domain"
 	"uala-tweets/internal/ports/repositories"
 )
 
+const (
+	maxDisplayNameLength = 50
+	maxBioLength         = 160
+	maxLocationLength    = 30
+	maxWebsiteLength     = 100
+	maxAvatarURLLength   = 255
+)
+
+// usernamePattern restricts new usernames to characters that can be written
+// in an @mention.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9_]{3,50}$`)
+
 type UserService struct {
 	userRepo repositories.UserRepository
 }
@@ -19,43 +33,111 @@ func NewUserService(userRepo repositories.UserRepository) *UserService {
 	}
 }
 
-type CreateUserInput struct {
-	Username string
-}
+// GetUser returns a user by ID. Deactivated accounts are reported as not
+// found.
+func (s *UserService) GetUser(ctx context.Context, id int) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUser")
+	defer span.End()
 
-func (s *UserService) CreateUser(input CreateUserInput) (*domain.User, error) {
-	if input.Username == "" {
-		return nil, &ErrInvalidInput{Message: "username cannot be empty"}
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-
-	user := &domain.User{
-		Username:  input.Username,
-		CreatedAt: time.Now().UTC(),
-		UpdatedAt: time.Now().UTC(),
+	if user == nil || user.DeactivatedAt != nil {
+		return nil, &ErrUserNotFound{UserID: id}
 	}
+	return user, nil
+}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
-	}
+func (s *UserService) UserExists(ctx context.Context, id int) (bool, error) {
+	ctx, span := tracer.Start(ctx, "UserService.UserExists")
+	defer span.End()
 
-	if user.ID == 0 {
-		return nil, errors.New("user created but ID not set")
-	}
+	return s.userRepo.Exists(ctx, id)
+}
+
+// GetUserByUsername looks a user up by username. Usernames the user has
+// renamed away from resolve too, so old mentions keep pointing at them.
+// Deactivated accounts are reported as not found.
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.GetUserByUsername")
+	defer span.End()
 
+	username = strings.TrimPrefix(username, "@")
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeactivatedAt != nil {
+		return nil, NewErrUsernameNotFound(username)
+	}
 	return user, nil
 }
 
-func (s *UserService) GetUser(id int) (*domain.User, error) {
-	user, err := s.userRepo.GetByID(id)
+// UpdateProfileInput holds the profile fields to change; nil fields are left
+// as they are and empty strings clear the field.
+type UpdateProfileInput struct {
+	Username    *string
+	DisplayName *string
+	Bio         *string
+	Location    *string
+	Website     *string
+	AvatarURL   *string
+}
+
+func (s *UserService) UpdateProfile(ctx context.Context, id int, input UpdateProfileInput) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserService.UpdateProfile")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
-		return nil, &ErrUserNotFound{UserID: id}
+
+	if input.Username != nil {
+		if !usernamePattern.MatchString(*input.Username) {
+			return nil, NewErrInvalidInput("username must be 3-50 letters, digits or underscores")
+		}
+		user.Username = *input.Username
+	}
+
+	fields := []struct {
+		name   string
+		value  *string
+		target *string
+		max    int
+		isURL  bool
+	}{
+		{"display_name", input.DisplayName, &user.DisplayName, maxDisplayNameLength, false},
+		{"bio", input.Bio, &user.Bio, maxBioLength, false},
+		{"location", input.Location, &user.Location, maxLocationLength, false},
+		{"website", input.Website, &user.Website, maxWebsiteLength, true},
+		{"avatar_url", input.AvatarURL, &user.AvatarURL, maxAvatarURLLength, true},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		value := strings.TrimSpace(*f.value)
+		if len([]rune(value)) > f.max {
+			return nil, NewErrInvalidInput(fmt.Sprintf("%s is too long (max %d characters)", f.name, f.max))
+		}
+		if f.isURL && value != "" && !isHTTPURL(value) {
+			return nil, NewErrInvalidInput(fmt.Sprintf("%s must be an http or https URL", f.name))
+		}
+		*f.target = value
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
 	}
 	return user, nil
 }
 
-func (s *UserService) UserExists(id int) (bool, error) {
-	return s.userRepo.Exists(id)
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
@@ -1,6 +1,8 @@
 package application_test
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,78 +14,6 @@ import (
 	"uala-tweets/internal/domain"
 )
 
-func TestUserService_CreateUser(t *testing.T) {
-	tests := []struct {
-		name        string
-		username    string
-		setupMock   func(*application.MockUserRepository)
-		expectErr   bool
-		errType     error
-		errContains string
-	}{
-		{
-			name:     "successful user creation",
-			username: "testuser",
-			setupMock: func(repo *application.MockUserRepository) {
-				repo.On("Create", mock.MatchedBy(func(user *domain.User) bool {
-					user.ID = 123 // Set a non-zero ID
-					return user.Username == "testuser"
-				})).Return(nil)
-			},
-			expectErr: false,
-		},
-		{
-			name:     "empty username",
-			username: "",
-			setupMock: func(repo *application.MockUserRepository) {
-				// No repository calls expected for empty username
-			},
-			expectErr:   true,
-			errType:     &application.ErrInvalidInput{},
-			errContains: "username cannot be empty",
-		},
-		{
-			name:     "repository error",
-			username: "testuser",
-			setupMock: func(repo *application.MockUserRepository) {
-				repo.On("Create", mock.Anything).Return(assert.AnError)
-			},
-			expectErr:   true,
-			errContains: "failed to create user",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockRepo := &application.MockUserRepository{}
-			tt.setupMock(mockRepo)
-
-			service := application.NewUserService(mockRepo)
-
-			user, err := service.CreateUser(application.CreateUserInput{
-				Username: tt.username,
-			})
-
-			if tt.expectErr {
-				require.Error(t, err)
-				if tt.errType != nil {
-					assert.IsType(t, tt.errType, err)
-				}
-				if tt.errContains != "" {
-					assert.Contains(t, err.Error(), tt.errContains)
-				}
-			} else {
-				require.NoError(t, err)
-				assert.Equal(t, tt.username, user.Username)
-				assert.NotZero(t, user.ID)
-				assert.WithinDuration(t, time.Now(), user.CreatedAt, time.Second)
-			}
-
-			mockRepo.AssertExpectations(t)
-		})
-	}
-}
-
 func TestUserService_GetUser(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -128,7 +58,7 @@ func TestUserService_GetUser(t *testing.T) {
 
 			service := application.NewUserService(mockRepo)
 
-			user, err := service.GetUser(tt.userID)
+			user, err := service.GetUser(context.Background(), tt.userID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -191,7 +121,7 @@ func TestUserService_UserExists(t *testing.T) {
 
 			service := application.NewUserService(mockRepo)
 
-			exists, err := service.UserExists(tt.userID)
+			exists, err := service.UserExists(context.Background(), tt.userID)
 
 			if tt.expectErr {
 				assert.Error(t, err)
@@ -204,3 +134,127 @@ func TestUserService_UserExists(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name      string
+		input     application.UpdateProfileInput
+		setupMock func(*application.MockUserRepository)
+		expectErr bool
+		errType   error
+	}{
+		{
+			name: "updates only the given fields",
+			input: application.UpdateProfileInput{
+				DisplayName: str("  John Doe "),
+				Website:     str("https://johndoe.dev"),
+			},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe", Bio: "kept"}, nil)
+				repo.On("Update", mock.MatchedBy(func(u *domain.User) bool {
+					return u.Username == "johndoe" && u.DisplayName == "John Doe" &&
+						u.Website == "https://johndoe.dev" && u.Bio == "kept"
+				})).Return(nil)
+			},
+		},
+		{
+			name:  "empty string clears a field",
+			input: application.UpdateProfileInput{Bio: str("")},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe", Bio: "old bio"}, nil)
+				repo.On("Update", mock.MatchedBy(func(u *domain.User) bool { return u.Bio == "" })).Return(nil)
+			},
+		},
+		{
+			name:  "username change",
+			input: application.UpdateProfileInput{Username: str("john_doe")},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+				repo.On("Update", mock.MatchedBy(func(u *domain.User) bool { return u.Username == "john_doe" })).Return(nil)
+			},
+		},
+		{
+			name:  "username that cannot be mentioned",
+			input: application.UpdateProfileInput{Username: str("john doe")},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "username taken",
+			input: application.UpdateProfileInput{Username: str("janedoe")},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+				repo.On("Update", mock.Anything).Return(application.NewErrUserAlreadyExists("janedoe"))
+			},
+			expectErr: true,
+			errType:   &application.ErrUserAlreadyExists{},
+		},
+		{
+			name:  "website must be an http URL",
+			input: application.UpdateProfileInput{Website: str("javascript:alert(1)")},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "bio too long",
+			input: application.UpdateProfileInput{Bio: str(strings.Repeat("a", 161))},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+			},
+			expectErr: true,
+			errType:   &application.ErrInvalidInput{},
+		},
+		{
+			name:  "user not found",
+			input: application.UpdateProfileInput{Bio: str("hi")},
+			setupMock: func(repo *application.MockUserRepository) {
+				repo.On("GetByID", 1).Return(nil, application.NewErrUserNotFound(1))
+			},
+			expectErr: true,
+			errType:   &application.ErrUserNotFound{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &application.MockUserRepository{}
+			tt.setupMock(mockRepo)
+
+			service := application.NewUserService(mockRepo)
+
+			user, err := service.UpdateProfile(context.Background(), 1, tt.input)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				if tt.errType != nil {
+					assert.IsType(t, tt.errType, err)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, user)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserService_GetUserByUsername(t *testing.T) {
+	mockRepo := &application.MockUserRepository{}
+	mockRepo.On("GetByUsername", "johndoe").Return(&domain.User{ID: 1, Username: "johndoe"}, nil)
+
+	service := application.NewUserService(mockRepo)
+
+	user, err := service.GetUserByUsername(context.Background(), "@johndoe")
+	require.NoError(t, err)
+	assert.Equal(t, 1, user.ID)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupIn(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(lookupIn(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, Default(), cfg)
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestLoad_FileThenEnv(t *testing.T) {
+	path := writeConfigFile(t, `
+http:
+  port: 9000
+kafka:
+  brokers: [kafka-1:9092, kafka-2:9092]
+  topic_partitions: 12
+consumers:
+  workers: 4
+  fanout:
+    workers: 32
+jobs:
+  account_purge_interval: 30m
+`)
+
+	cfg, err := Load(lookupIn(map[string]string{
+		FileEnv:                     path,
+		"PORT":                      "9100",
+		"FANOUT_CONSUMER_WORKERS":   "64",
+		"TWEET_CONSUMER_QUEUE_SIZE": "2",
+		"SWAGGER_ENABLED":           "false",
+		"TRACING_SAMPLE_RATIO":      "0.25",
+		// Empty variables are ignored
+		"REDIS_ADDR": "",
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 9100, cfg.HTTP.Port)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, 12, cfg.Kafka.TopicPartitions)
+	assert.Equal(t, 30*time.Minute, cfg.Jobs.AccountPurgeInterval)
+	assert.False(t, cfg.Features.Swagger)
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+	assert.Equal(t, "localhost:6379", cfg.Redis.Addr)
+
+	assert.Equal(t, PoolConfig{Workers: 64, QueueSize: 16}, cfg.Consumers.Pool(cfg.Consumers.Fanout))
+	assert.Equal(t, PoolConfig{Workers: 4, QueueSize: 2}, cfg.Consumers.Pool(cfg.Consumers.Tweet))
+	assert.Equal(t, PoolConfig{Workers: 4, QueueSize: 16}, cfg.Consumers.Pool(cfg.Consumers.Follow))
+}
+
+func TestLoad_Errors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		env     map[string]string
+		file    string
+		wantErr []string
+	}{
+		{
+			name:    "malformed variables",
+			env:     map[string]string{"CONSUMER_WORKERS": "many", "SHUTDOWN_TIMEOUT": "30", "KAFKA_PROVISION_TOPICS": "maybe", "TRACING_SAMPLE_RATIO": "half"},
+			wantErr: []string{`CONSUMER_WORKERS "many"`, `SHUTDOWN_TIMEOUT "30"`, `KAFKA_PROVISION_TOPICS "maybe"`, `TRACING_SAMPLE_RATIO "half"`},
+		},
+		{
+			name:    "misspelled key",
+			file:    "kafka:\n  broker: kafka:9092\n",
+			wantErr: []string{"field broker not found"},
+		},
+		{
+			name:    "missing file",
+			env:     map[string]string{FileEnv: "/nonexistent/config.yaml"},
+			wantErr: []string{"failed to read config file"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := tc.env
+			if tc.file != "" {
+				env = map[string]string{FileEnv: writeConfigFile(t, tc.file)}
+			}
+
+			_, err := Load(lookupIn(env))
+			require.Error(t, err)
+			for _, want := range tc.wantErr {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Default()
+	cfg.HTTP.Port = 0
+	cfg.HTTP.TrustedProxies = []string{"10.0.0.0/8", "lb.internal"}
+	cfg.Kafka.Brokers = []string{"kafka"}
+	cfg.Kafka.EventEncoding = "xml"
+	cfg.Consumers.Fanout.Workers = -1
+	cfg.Consumers.MaxBackoff = time.Millisecond
+	cfg.Tracing.OTLPEndpoint = "collector:4318"
+	cfg.Tracing.SampleRatio = 2
+	cfg.Logging.Level = "verbose"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	messages := strings.Split(err.Error(), "\n")
+	assert.Equal(t, []string{
+		"http.port (PORT) must be a port between 1 and 65535, got 0",
+		"http.trusted_proxies (TRUSTED_PROXIES) must be IP addresses or CIDR ranges, got [10.0.0.0/8 lb.internal]",
+		`kafka.brokers (KAFKA_BROKER) must be host:port addresses, got [kafka]`,
+		`kafka.event_encoding (EVENT_ENCODING) must be json or protobuf, got "xml"`,
+		"consumers.fanout.workers (FANOUT_CONSUMER_WORKERS) must be at least 0, got -1",
+		"consumers.max_backoff (CONSUMER_MAX_BACKOFF) must be at least the backoff, got 1ms",
+		`tracing.otlp_endpoint (TRACING_OTLP_ENDPOINT) must be an http:// or https:// URL, got "collector:4318"`,
+		"tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1, got 2",
+		`logging.level (LOG_LEVEL) must be debug, info, warn or error, got "verbose"`,
+	}, messages)
+}
+
+func TestValidateAPI_RequiresJWTSecret(t *testing.T) {
+	cfg := Default()
+	assert.NoError(t, cfg.Validate())
+	assert.ErrorContains(t, cfg.ValidateAPI(), "auth.jwt_secret (JWT_SECRET) must be set")
+
+	cfg.Auth.JWTSecret = "secret"
+	assert.NoError(t, cfg.ValidateAPI())
+}
+
+func TestValidate_DoesNotPrintSecrets(t *testing.T) {
+	cfg := Default()
+	cfg.Database.URL = "mysql://uala:ualapass@db:3306/uala_tweets"
+
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "database.url (DB_URL) must be a postgres:// URL, got [REDACTED]", err.Error())
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Default()
+	cfg.Database.URL = "postgres://uala:ualapass@db:5432/uala_tweets"
+	cfg.Redis.Password = "redispass"
+	cfg.Auth.JWTSecret = "jwtsecret"
+
+	out, err := cfg.Redacted().YAML()
+	require.NoError(t, err)
+
+	for _, secret := range []string{"ualapass", "redispass", "jwtsecret"} {
+		assert.NotContains(t, string(out), secret)
+	}
+	assert.Contains(t, string(out), "url: postgres://uala:xxxxx@db:5432/uala_tweets")
+	assert.Contains(t, string(out), "shutdown_timeout: 30s")
+	assert.Equal(t, "jwtsecret", cfg.Auth.JWTSecret, "the configuration itself is left as it is")
+
+	for _, tc := range []struct{ url, want string }{
+		{"postgres://uala@db:5432/uala_tweets?password=ualapass&sslmode=disable", "postgres://uala@db:5432/uala_tweets?password=xxxxx&sslmode=disable"},
+		{"host=db user=uala password=ualapass dbname=uala_tweets", redacted},
+		{"db:5432", redacted},
+	} {
+		cfg := Default()
+		cfg.Database.URL = tc.url
+		assert.Equal(t, tc.want, cfg.Redacted().Database.URL, tc.url)
+	}
+
+	// The printed configuration loads back
+	reloaded, err := Load(lookupIn(map[string]string{FileEnv: writeConfigFile(t, string(out))}))
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Consumers, reloaded.Consumers)
+}
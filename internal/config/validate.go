@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// Validate reports every invalid setting of c, naming its YAML key and
+// environment variable
+func (c *Config) Validate() error {
+	v := newValidator(c)
+
+	v.check(c.HTTP.Port >= 1 && c.HTTP.Port <= 65535, &c.HTTP.Port, "must be a port between 1 and 65535")
+	v.check(!slices.ContainsFunc(c.HTTP.TrustedProxies, func(proxy string) bool { return !isIPOrCIDR(proxy) }), &c.HTTP.TrustedProxies, "must be IP addresses or CIDR ranges")
+
+	if u, err := url.Parse(c.Database.URL); err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		v.fail(&c.Database.URL, "must be a postgres:// URL")
+	}
+	v.atLeast(&c.Database.MaxOpenConns, 1)
+	v.atLeast(&c.Database.MaxIdleConns, 0)
+	v.positive(&c.Database.ConnMaxLifetime)
+	v.positive(&c.Database.ConnectTimeout)
+	v.check(c.Database.MigrationsPath != "", &c.Database.MigrationsPath, "must be set")
+
+	v.check(isHostPort(c.Redis.Addr), &c.Redis.Addr, "must be a host:port address")
+	v.atLeast(&c.Redis.DB, 0)
+
+	v.check(len(c.Kafka.Brokers) > 0, &c.Kafka.Brokers, "must name at least one broker")
+	v.check(!slices.ContainsFunc(c.Kafka.Brokers, func(broker string) bool { return !isHostPort(broker) }), &c.Kafka.Brokers, "must be host:port addresses")
+	v.check(c.Kafka.EventEncoding == "json" || c.Kafka.EventEncoding == "protobuf", &c.Kafka.EventEncoding, "must be json or protobuf")
+	v.atLeast(&c.Kafka.TopicPartitions, 1)
+	v.atLeast(&c.Kafka.TopicReplicationFactor, 1)
+	v.positive(&c.Kafka.TopicRetention)
+	v.positive(&c.Kafka.DeadLetterTopicRetention)
+	v.positive(&c.Kafka.ProvisionTimeout)
+
+	v.atLeast(&c.Consumers.Workers, 1)
+	v.atLeast(&c.Consumers.QueueSize, 0)
+	for _, pool := range []*PoolConfig{&c.Consumers.Tweet, &c.Consumers.Fanout, &c.Consumers.Follow} {
+		// Zero leaves the consumer with the shared pool size
+		v.atLeast(&pool.Workers, 0)
+		v.atLeast(&pool.QueueSize, 0)
+	}
+	v.atLeast(&c.Consumers.Attempts, 1)
+	v.positive(&c.Consumers.Backoff)
+	v.check(c.Consumers.MaxBackoff >= c.Consumers.Backoff, &c.Consumers.MaxBackoff, "must be at least the backoff")
+	v.atLeast(&c.Consumers.Redeliveries, 0)
+	v.positive(&c.Consumers.RedeliveryDelay)
+	v.positive(&c.Consumers.ProcessedEventTTL)
+
+	v.positive(&c.Auth.AccessTokenTTL)
+	v.positive(&c.Auth.RefreshTokenTTL)
+
+	v.positive(&c.Limits.RateLimitWindow)
+	v.atLeast(&c.Limits.Tweets, 1)
+	v.atLeast(&c.Limits.Follows, 1)
+	v.atLeast(&c.Limits.Reads, 1)
+	v.atLeast(&c.Limits.Auth, 1)
+	v.positive(&c.Limits.IdempotencyKeyTTL)
+
+	v.positive(&c.Jobs.SuggestionRefreshInterval)
+	v.positive(&c.Jobs.AccountPurgeInterval)
+	v.positive(&c.Jobs.AccountDeletionGracePeriod)
+
+	v.positive(&c.Health.CheckTimeout)
+	v.check(c.Health.CacheTTL >= 0, &c.Health.CacheTTL, "must not be negative")
+
+	v.check(slices.Contains([]string{"none", "otlp", "stdout"}, c.Tracing.Exporter), &c.Tracing.Exporter, "must be none, otlp or stdout")
+	if c.Tracing.OTLPEndpoint != "" {
+		if u, err := url.Parse(c.Tracing.OTLPEndpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			v.fail(&c.Tracing.OTLPEndpoint, "must be an http:// or https:// URL")
+		}
+	}
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, &c.Tracing.SampleRatio, "must be between 0 and 1")
+	v.check(c.Tracing.ServiceName != "", &c.Tracing.ServiceName, "must be set")
+
+	v.check(slices.Contains([]string{"debug", "info", "warn", "error"}, c.Logging.Level), &c.Logging.Level, "must be debug, info, warn or error")
+	v.check(c.Logging.Format == "json" || c.Logging.Format == "text", &c.Logging.Format, "must be json or text")
+
+	v.positive(&c.ShutdownTimeout)
+
+	return v.err()
+}
+
+// ValidateAPI is Validate, also requiring what only the API needs
+func (c *Config) ValidateAPI() error {
+	v := newValidator(c)
+	v.check(c.Auth.JWTSecret != "", &c.Auth.JWTSecret, "must be set to sign access tokens")
+	return errors.Join(c.Validate(), v.err())
+}
+
+// validator collects the invalid settings of a configuration
+type validator struct {
+	fields map[any]field
+	errs   []error
+}
+
+func newValidator(c *Config) *validator {
+	v := &validator{fields: make(map[any]field)}
+	for _, f := range fields(c) {
+		v.fields[f.value.Addr().Interface()] = f
+	}
+	return v
+}
+
+// fail reports the setting at ptr, a pointer into the validated
+// configuration, as invalid
+func (v *validator) fail(ptr any, reason string) {
+	f := v.fields[ptr]
+	v.errs = append(v.errs, fmt.Errorf("%s (%s) %s, got %s", f.key, f.env, reason, describe(f, f.value)))
+}
+
+func (v *validator) check(ok bool, ptr any, reason string) {
+	if !ok {
+		v.fail(ptr, reason)
+	}
+}
+
+func (v *validator) atLeast(ptr *int, minimum int) {
+	v.check(*ptr >= minimum, ptr, fmt.Sprintf("must be at least %d", minimum))
+}
+
+func (v *validator) positive(ptr *time.Duration) {
+	v.check(*ptr > 0, ptr, "must be a positive duration")
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
+
+// describe quotes value, the value of f, for error messages, unless f is a
+// secret
+func describe(f field, value reflect.Value) string {
+	if f.secret {
+		return redacted
+	}
+	if value.Kind() == reflect.String {
+		return fmt.Sprintf("%q", value.String())
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+func isIPOrCIDR(s string) bool {
+	if net.ParseIP(s) != nil {
+		return true
+	}
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
+func isHostPort(addr string) bool {
+	host, port, err := net.SplitHostPort(addr)
+	return err == nil && host != "" && port != ""
+}
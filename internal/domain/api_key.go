@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// APIKeyPrefix starts every API key, which tells keys apart from access
+// tokens in the Authorization header.
+const APIKeyPrefix = "uala_"
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeTweetsWrite  APIKeyScope = "tweets:write"
+	APIKeyScopeFollowsWrite APIKeyScope = "follows:write"
+	APIKeyScopeTimelineRead APIKeyScope = "timeline:read"
+)
+
+// APIKeyScopes lists every scope a key can be granted.
+var APIKeyScopes = []APIKeyScope{
+	APIKeyScopeTweetsWrite,
+	APIKeyScopeFollowsWrite,
+	APIKeyScopeTimelineRead,
+}
+
+// APIKey is a long-lived credential for bots and integrations that acts as
+// its user within its scopes. Prefix is the start of the key, kept so users
+// can tell their keys apart.
+type APIKey struct {
+	ID         int64         `json:"id"`
+	UserID     int           `json:"user_id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// DeadLetter is a Kafka message a consumer gave up on, archived from its
+// dead-letter topic so operators can inspect it and replay it.
+type DeadLetter struct {
+	ID       int64  `json:"id"`
+	Consumer string `json:"consumer"`
+	// Where the message was first published, and where replays go
+	Topic     string            `json:"topic"`
+	Partition int               `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Key       []byte            `json:"key,omitempty"`
+	Payload   []byte            `json:"payload"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Error     string            `json:"error"`
+	Attempts  int               `json:"attempts"`
+	FailedAt  time.Time         `json:"failed_at"`
+	// Where the message sits in the dead-letter topic, which makes
+	// archiving it twice a no-op
+	DeadLetterTopic     string     `json:"dead_letter_topic"`
+	DeadLetterPartition int        `json:"dead_letter_partition"`
+	DeadLetterOffset    int64      `json:"dead_letter_offset"`
+	ReplayCount         int        `json:"replay_count"`
+	LastReplayedAt      *time.Time `json:"last_replayed_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// DeadLetterReplay records who sent a dead letter back to its topic, and when
+type DeadLetterReplay struct {
+	ID           int64     `json:"id"`
+	DeadLetterID int64     `json:"dead_letter_id"`
+	ReplayedBy   string    `json:"replayed_by"`
+	ReplayedAt   time.Time `json:"replayed_at"`
+}
+
+// DeadLetterFilter narrows down dead letters. Zero values match everything.
+type DeadLetterFilter struct {
+	Consumer string
+	Topic    string
+	// Failed at or after From and before To
+	From  time.Time
+	To    time.Time
+	Limit int
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// NewEventID returns a random UUID identifying an event, so consumers can
+// tell a redelivered event from a new one.
+func NewEventID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("generating event ID: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -1,9 +1,10 @@
 package domain
 
 type FollowEvent struct {
-	FollowerID int  `json:"follower_id"`
-	FollowedID int  `json:"followed_id"`
-	Following  bool `json:"following"` // true for follow, false for unfollow
+	EventID    string `json:"event_id"`
+	FollowerID int    `json:"follower_id"`
+	FollowedID int    `json:"followed_id"`
+	Following  bool   `json:"following"` // true for follow, false for unfollow
 }
 
 func (e *FollowEvent) TopicName() string {
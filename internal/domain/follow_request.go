@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+type FollowRequest struct {
+	RequesterID int       `json:"requester_id"`
+	TargetID    int       `json:"target_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
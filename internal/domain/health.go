@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+// ComponentHealth is the outcome of checking one dependency
+type ComponentHealth struct {
+	Status HealthStatus
+	// Error says why the component is down
+	Error    string
+	Duration time.Duration
+}
+
+// HealthReport is the outcome of checking every dependency of a probe. The
+// service is up when every component is.
+type HealthReport struct {
+	Status     HealthStatus
+	Components map[string]ComponentHealth
+	CheckedAt  time.Time
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// List is a named group of accounts curated by its owner. Private lists are
+// only visible to the owner.
+type List struct {
+	ID          int       `json:"id"`
+	OwnerID     int       `json:"owner_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Private     bool      `json:"private"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
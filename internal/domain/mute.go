@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type MuteType string
+
+const (
+	MuteTypeUser    MuteType = "user"
+	MuteTypeKeyword MuteType = "keyword"
+)
+
+type Mute struct {
+	ID          int64      `json:"id"`
+	UserID      int        `json:"user_id"`
+	Type        MuteType   `json:"type"`
+	MutedUserID int        `json:"muted_user_id,omitempty"`
+	Keyword     string     `json:"keyword,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the mute is still in effect at the given time.
+func (m *Mute) IsActive(now time.Time) bool {
+	return m.ExpiresAt == nil || now.Before(*m.ExpiresAt)
+}
+
+// Target describes what is muted, for messages.
+func (m *Mute) Target() string {
+	if m.Type == MuteTypeKeyword {
+		return fmt.Sprintf("keyword '%s'", m.Keyword)
+	}
+	return fmt.Sprintf("user %d", m.MutedUserID)
+}
+
+// Matches reports whether the tweet should be hidden by this mute.
+// Hashtag keywords (starting with '#') match whole hashtags only, any other
+// keyword matches as a case-insensitive substring of the content.
+func (m *Mute) Matches(tweet *Tweet) bool {
+	switch m.Type {
+	case MuteTypeUser:
+		return int64(m.MutedUserID) == tweet.UserID
+	case MuteTypeKeyword:
+		keyword := strings.ToLower(m.Keyword)
+		content := strings.ToLower(tweet.Content)
+		if !strings.HasPrefix(keyword, "#") {
+			return strings.Contains(content, keyword)
+		}
+		for _, token := range strings.FieldsFunc(content, isHashtagSeparator) {
+			if token == keyword {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isHashtagSeparator(r rune) bool {
+	return r != '#' && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// RefreshToken is a long-lived credential traded for new access tokens.
+// Every refresh revokes the presented token and issues a new one in the
+// same family, so reusing a revoked token exposes a leak and revokes the
+// whole family.
+type RefreshToken struct {
+	ID        int64      `json:"id"`
+	UserID    int        `json:"user_id"`
+	FamilyID  string     `json:"family_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusDismissed ReportStatus = "dismissed"
+	// ReportStatusActioned means the reported tweet was removed
+	ReportStatusActioned ReportStatus = "actioned"
+)
+
+// Report flags a tweet for review by moderators.
+type Report struct {
+	ID         int64        `json:"id"`
+	TweetID    int64        `json:"tweet_id"`
+	ReporterID int          `json:"reporter_id"`
+	Reason     string       `json:"reason"`
+	Status     ReportStatus `json:"status"`
+	ResolvedBy *int         `json:"resolved_by,omitempty"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
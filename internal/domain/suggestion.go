@@ -0,0 +1,8 @@
+package domain
+
+// Suggestion is an account recommended to a user. Score is the number of
+// accounts the user follows that also follow the suggested account.
+type Suggestion struct {
+	UserID int `json:"user_id"`
+	Score  int `json:"score"`
+}
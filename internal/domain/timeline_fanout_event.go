@@ -1,6 +1,10 @@
 package domain
 
+// TimelineFanoutEvent adds a tweet to one timeline: the home timeline of
+// UserID, or the timeline of list ListID when it is set.
 type TimelineFanoutEvent struct {
-	TweetID int64 `json:"tweet_id"`
-	UserID  int   `json:"user_id"`
+	EventID string `json:"event_id"`
+	TweetID int64  `json:"tweet_id"`
+	UserID  int    `json:"user_id,omitempty"`
+	ListID  int    `json:"list_id,omitempty"`
 }
@@ -8,4 +8,6 @@ type Tweet struct {
 	Content   string
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	// EventID identifies the event that created the tweet
+	EventID string
 }
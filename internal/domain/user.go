@@ -4,9 +4,20 @@ import (
 	"time"
 )
 
+// User is an account. DeactivatedAt is set while the account is waiting
+// to be deleted. PasswordHash is never serialized.
 type User struct {
-	ID        int       `json:"id"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            int        `json:"id"`
+	Username      string     `json:"username"`
+	PasswordHash  string     `json:"-"`
+	Role          Role       `json:"role"`
+	DisplayName   string     `json:"display_name"`
+	Bio           string     `json:"bio"`
+	Location      string     `json:"location"`
+	Website       string     `json:"website"`
+	AvatarURL     string     `json:"avatar_url"`
+	Protected     bool       `json:"protected"`
+	DeactivatedAt *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+type UserEventType string
+
+const (
+	UserEventDeactivated UserEventType = "deactivated"
+	UserEventReactivated UserEventType = "reactivated"
+	UserEventDeleted     UserEventType = "deleted"
+)
+
+// UserEvent announces a change in the lifecycle of an account so other
+// services can drop or restore what they hold about the user.
+type UserEvent struct {
+	EventID    string        `json:"event_id"`
+	Type       UserEventType `json:"type"`
+	UserID     int           `json:"user_id"`
+	Username   string        `json:"username"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+func (e *UserEvent) TopicName() string {
+	return "user.events"
+}
@@ -0,0 +1,66 @@
+package events
+
+import "fmt"
+
+// ContentTypeHeader is the Kafka header naming how a message value is
+// encoded. Messages without it predate it and are JSON.
+const ContentTypeHeader = "content-type"
+
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Codec encodes envelopes as Kafka message values
+type Codec interface {
+	// ContentType is the value of the ContentTypeHeader of the messages
+	// the codec encodes
+	ContentType() string
+	Marshal(envelope *Envelope) ([]byte, error)
+	Unmarshal(data []byte) (*Envelope, error)
+}
+
+// JSON is the codec every message was encoded with before there were
+// codecs
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string                        { return ContentTypeJSON }
+func (jsonCodec) Marshal(envelope *Envelope) ([]byte, error) { return Marshal(envelope) }
+func (jsonCodec) Unmarshal(data []byte) (*Envelope, error)   { return Unmarshal(data) }
+
+// ErrUnsupportedContentType is returned for a message encoded in a way no
+// codec reads
+type ErrUnsupportedContentType struct {
+	ContentType string
+}
+
+func (e ErrUnsupportedContentType) Error() string {
+	return fmt.Sprintf("unsupported content type %q", e.ContentType)
+}
+
+// CodecFor returns the codec that reads messages of contentType, so JSON
+// and Protobuf messages can share a topic while producers switch over
+func CodecFor(contentType string) (Codec, error) {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return JSON, nil
+	case ContentTypeProtobuf:
+		return DefaultProtobufCodec()
+	default:
+		return nil, &ErrUnsupportedContentType{ContentType: contentType}
+	}
+}
+
+// CodecNamed returns the codec configured by name, json or protobuf
+func CodecNamed(name string) (Codec, error) {
+	switch name {
+	case "json":
+		return JSON, nil
+	case "protobuf":
+		return DefaultProtobufCodec()
+	default:
+		return nil, fmt.Errorf("unknown event encoding %q, expected json or protobuf", name)
+	}
+}
@@ -0,0 +1,137 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"uala-tweets/internal/domain"
+)
+
+func TestCodecs(t *testing.T) {
+	protobuf, err := DefaultProtobufCodec()
+	require.NoError(t, err)
+
+	createdAt := time.Date(2024, 1, 2, 15, 4, 5, 6, time.UTC)
+	envelopes := map[string]func() (*Envelope, error){
+		TypeTweetCreated: func() (*Envelope, error) {
+			return NewTweetCreated(&domain.Tweet{UserID: 42, Content: "hello", CreatedAt: createdAt})
+		},
+		TypeTimelineFanout: func() (*Envelope, error) {
+			return NewTimelineFanout(&domain.TimelineFanoutEvent{TweetID: 1, ListID: 7})
+		},
+		TypeUserUnfollowed: func() (*Envelope, error) {
+			return NewFollow(&domain.FollowEvent{FollowerID: 1, FollowedID: 2})
+		},
+		TypeUserDeleted: func() (*Envelope, error) {
+			return NewUserEvent(&domain.UserEvent{Type: domain.UserEventDeleted, UserID: 42, Username: "johndoe", OccurredAt: createdAt})
+		},
+	}
+
+	for _, codec := range []Codec{JSON, protobuf} {
+		for eventType, newEnvelope := range envelopes {
+			t.Run(codec.ContentType()+"/"+eventType, func(t *testing.T) {
+				envelope, err := newEnvelope()
+				require.NoError(t, err)
+				envelope.TraceContext = map[string]string{"traceparent": "00-trace-span-01"}
+
+				data, err := codec.Marshal(envelope)
+				require.NoError(t, err)
+				decoded, err := codec.Unmarshal(data)
+				require.NoError(t, err)
+
+				assert.Equal(t, envelope.EventID, decoded.EventID)
+				assert.Equal(t, eventType, decoded.Type)
+				assert.Equal(t, envelope.SchemaVersion, decoded.SchemaVersion)
+				assert.True(t, envelope.OccurredAt.Equal(decoded.OccurredAt))
+				assert.Equal(t, Producer, decoded.Producer)
+				assert.Equal(t, envelope.TraceContext, decoded.TraceContext)
+				assert.JSONEq(t, string(withoutZeroFields(t, envelope.Payload)), string(decoded.Payload))
+			})
+		}
+	}
+}
+
+// withoutZeroFields drops the fields Protobuf leaves out of the payload for
+// having their zero value, which decode to the same payload
+func withoutZeroFields(t *testing.T, payload []byte) []byte {
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(payload, &fields))
+	for name, value := range fields {
+		if value == 0.0 || value == "" || value == false {
+			delete(fields, name)
+		}
+	}
+	data, err := json.Marshal(fields)
+	require.NoError(t, err)
+	return data
+}
+
+func TestProtobufCodec_DecodesLikeJSON(t *testing.T) {
+	protobuf, err := DefaultProtobufCodec()
+	require.NoError(t, err)
+
+	tweet := &domain.Tweet{UserID: 42, Content: "hello", CreatedAt: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), EventID: "event-1"}
+	envelope, err := NewTweetCreated(tweet)
+	require.NoError(t, err)
+
+	data, err := protobuf.Marshal(envelope)
+	require.NoError(t, err)
+	decoded, err := protobuf.Unmarshal(data)
+	require.NoError(t, err)
+
+	decodedTweet, err := DecodeTweetCreated(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, tweet, decodedTweet)
+}
+
+func TestProtobufCodec_Errors(t *testing.T) {
+	protobuf, err := DefaultProtobufCodec()
+	require.NoError(t, err)
+
+	t.Run("field missing from schema", func(t *testing.T) {
+		envelope, err := New(TypeTimelineFanout, TimelineFanoutVersion, "", time.Now(), map[string]any{"tweet_id": 1, "retweet_id": 2})
+		require.NoError(t, err)
+
+		_, err = protobuf.Marshal(envelope)
+		assert.ErrorContains(t, err, "retweet_id")
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		envelope, err := New(TypeTimelineFanout, 99, "", time.Now(), TimelineFanoutV1{TweetID: 1})
+		require.NoError(t, err)
+
+		_, err = protobuf.Marshal(envelope)
+		var unsupported *ErrUnsupportedVersion
+		assert.ErrorAs(t, err, &unsupported)
+	})
+}
+
+func TestCodecFor(t *testing.T) {
+	testCases := []struct {
+		contentType string
+		want        string
+		wantErr     bool
+	}{
+		{contentType: "", want: ContentTypeJSON},
+		{contentType: ContentTypeJSON, want: ContentTypeJSON},
+		{contentType: ContentTypeProtobuf, want: ContentTypeProtobuf},
+		{contentType: "application/avro", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.contentType, func(t *testing.T) {
+			codec, err := CodecFor(tc.contentType)
+			if tc.wantErr {
+				var unsupported *ErrUnsupportedContentType
+				assert.ErrorAs(t, err, &unsupported)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, codec.ContentType())
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// SchemaField is a field of a schema as it was published. The fields of
+// every schema are recorded in a lock file, and later schemas are checked
+// against it so consumers can always read what was published before.
+type SchemaField struct {
+	Message     string `json:"message"`
+	Number      int32  `json:"number"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Cardinality string `json:"cardinality"`
+}
+
+// Fields lists the fields of every schema in the registry
+func (r *SchemaRegistry) Fields() []SchemaField {
+	var fields []SchemaField
+	for _, message := range r.messages() {
+		for i := 0; i < message.Fields().Len(); i++ {
+			field := message.Fields().Get(i)
+			fields = append(fields, SchemaField{
+				Message:     string(message.FullName()),
+				Number:      int32(field.Number()),
+				Name:        string(field.Name()),
+				Type:        fieldType(field),
+				Cardinality: field.Cardinality().String(),
+			})
+		}
+	}
+	slices.SortFunc(fields, func(a, b SchemaField) int {
+		return cmp.Or(cmp.Compare(a.Message, b.Message), cmp.Compare(a.Number, b.Number))
+	})
+	return fields
+}
+
+// CheckCompatibility returns how the registry breaks the published fields:
+// a field removed without reserving its number and name, or one whose
+// name, type or cardinality changed. Names count because JSON payloads are
+// keyed by them. Adding fields is always compatible.
+func (r *SchemaRegistry) CheckCompatibility(published []SchemaField) []error {
+	messages := r.messages()
+
+	var errs []error
+	for _, locked := range published {
+		message, ok := messages[protoreflect.FullName(locked.Message)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("schema %s was removed", locked.Message))
+			continue
+		}
+
+		field := message.Fields().ByNumber(protoreflect.FieldNumber(locked.Number))
+		if field == nil {
+			if !message.ReservedRanges().Has(protoreflect.FieldNumber(locked.Number)) || !message.ReservedNames().Has(protoreflect.Name(locked.Name)) {
+				errs = append(errs, fmt.Errorf("field %d %s of %s was removed without reserving its number and name", locked.Number, locked.Name, locked.Message))
+			}
+			continue
+		}
+
+		switch {
+		case string(field.Name()) != locked.Name:
+			errs = append(errs, fmt.Errorf("field %d of %s was renamed from %s to %s", locked.Number, locked.Message, locked.Name, field.Name()))
+		case fieldType(field) != locked.Type:
+			errs = append(errs, fmt.Errorf("field %s of %s changed type from %s to %s", locked.Name, locked.Message, locked.Type, fieldType(field)))
+		case field.Cardinality().String() != locked.Cardinality:
+			errs = append(errs, fmt.Errorf("field %s of %s changed from %s to %s", locked.Name, locked.Message, locked.Cardinality, field.Cardinality()))
+		}
+	}
+	return errs
+}
+
+// messages indexes every message of the registry's files by name, leaving
+// out the entries protoc generates for map fields
+func (r *SchemaRegistry) messages() map[protoreflect.FullName]protoreflect.MessageDescriptor {
+	messages := make(map[protoreflect.FullName]protoreflect.MessageDescriptor)
+	var walk func(protoreflect.MessageDescriptors)
+	walk = func(descriptors protoreflect.MessageDescriptors) {
+		for i := 0; i < descriptors.Len(); i++ {
+			message := descriptors.Get(i)
+			if message.IsMapEntry() {
+				continue
+			}
+			messages[message.FullName()] = message
+			walk(message.Messages())
+		}
+	}
+	for _, file := range r.files {
+		walk(file.Messages())
+	}
+	return messages
+}
+
+func fieldType(field protoreflect.FieldDescriptor) string {
+	switch {
+	case field.IsMap():
+		return fmt.Sprintf("map<%s, %s>", fieldType(field.MapKey()), fieldType(field.MapValue()))
+	case field.Message() != nil:
+		return string(field.Message().FullName())
+	case field.Enum() != nil:
+		return string(field.Enum().FullName())
+	default:
+		return field.Kind().String()
+	}
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSchemaLock = flag.Bool("update", false, "record the current schemas in the schema lock")
+
+const schemaLockPath = "schemas/schemas.lock.json"
+
+// TestSchemaCompatibility checks the schemas against the fields published
+// so far. After adding fields, record them with
+//
+//	go test ./internal/events -run TestSchemaCompatibility -update
+func TestSchemaCompatibility(t *testing.T) {
+	registry, err := DefaultSchemaRegistry()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(schemaLockPath)
+	require.NoError(t, err)
+	var published []SchemaField
+	require.NoError(t, json.Unmarshal(data, &published))
+
+	for _, err := range registry.CheckCompatibility(published) {
+		t.Errorf("breaking schema change: %v", err)
+	}
+	if t.Failed() {
+		return
+	}
+
+	current := registry.Fields()
+	if *updateSchemaLock {
+		data, err := json.MarshalIndent(current, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(schemaLockPath, append(data, '\n'), 0o644))
+		return
+	}
+	assert.Equal(t, published, current, "schema lock is out of date, run the test with -update")
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	published := []SchemaField{
+		{Message: "uala.events.Envelope", Number: 1, Name: "event_id", Type: "string", Cardinality: "optional"},
+		{Message: "uala.events.TestV1", Number: 1, Name: "tweet_id", Type: "int64", Cardinality: "optional"},
+		{Message: "uala.events.TestV1", Number: 2, Name: "user_id", Type: "int64", Cardinality: "optional"},
+	}
+
+	testCases := []struct {
+		name       string
+		schema     string
+		wantErrors int
+	}{
+		{
+			name:   "unchanged",
+			schema: "message TestV1 { int64 tweet_id = 1; int64 user_id = 2; }",
+		},
+		{
+			name:   "field added",
+			schema: "message TestV1 { int64 tweet_id = 1; int64 user_id = 2; string content = 3; }",
+		},
+		{
+			name:   "field removed and reserved",
+			schema: "message TestV1 { int64 tweet_id = 1; reserved 2; reserved \"user_id\"; }",
+		},
+		{
+			name:       "field removed",
+			schema:     "message TestV1 { int64 tweet_id = 1; }",
+			wantErrors: 1,
+		},
+		{
+			name:       "field renamed and retyped",
+			schema:     "message TestV1 { int64 tweet = 1; string user_id = 2; }",
+			wantErrors: 2,
+		},
+		{
+			name:       "field made repeated",
+			schema:     "message TestV1 { int64 tweet_id = 1; repeated int64 user_id = 2; }",
+			wantErrors: 1,
+		},
+		{
+			name:       "schema removed",
+			schema:     "message TestV2 { int64 tweet_id = 1; }",
+			wantErrors: 2,
+		},
+	}
+
+	envelope, err := schemaFiles.ReadFile("schemas/envelope.proto")
+	require.NoError(t, err)
+	schemas := fstest.MapFS{"envelope.proto": {Data: envelope}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			schemas["test.proto"] = &fstest.MapFile{Data: []byte("syntax = \"proto3\";\npackage uala.events;\n" + tc.schema)}
+			registry, err := compileSchemas(schemas)
+			require.NoError(t, err)
+
+			assert.Len(t, registry.CheckCompatibility(published), tc.wantErrors)
+		})
+	}
+}
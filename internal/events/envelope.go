@@ -0,0 +1,120 @@
+// Package events defines the envelope every Kafka message is wrapped in and
+// the versioned payloads of each event type.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
+
+// Producer names this service as the source of the events it publishes
+const Producer = "uala-tweets"
+
+// LegacyVersion is the schema version given to messages published before
+// the envelope, whose payload is the whole message
+const LegacyVersion = 0
+
+// Envelope wraps the payload of an event with what consumers need to know
+// before decoding it: what the event is, which schema the payload follows,
+// and where it came from.
+type Envelope struct {
+	EventID       string    `json:"event_id"`
+	Type          string    `json:"type"`
+	SchemaVersion int       `json:"schema_version"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	Producer      string    `json:"producer"`
+	// TraceContext carries the trace the event was published in, as
+	// propagation headers like traceparent
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+	Payload      json.RawMessage   `json:"payload"`
+}
+
+// New wraps payload in an envelope. Events without an ID get a new one.
+func New(eventType string, schemaVersion int, eventID string, occurredAt time.Time, payload any) (*Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+
+	if eventID == "" {
+		eventID = domain.NewEventID()
+	}
+
+	return &Envelope{
+		EventID:       eventID,
+		Type:          eventType,
+		SchemaVersion: schemaVersion,
+		OccurredAt:    occurredAt.UTC(),
+		Producer:      Producer,
+		Payload:       data,
+	}, nil
+}
+
+// Marshal encodes an envelope for a Kafka message value
+func Marshal(envelope *Envelope) ([]byte, error) {
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s envelope: %w", envelope.Type, err)
+	}
+	return data, nil
+}
+
+// Unmarshal decodes a Kafka message value. A message without an envelope,
+// published before there was one, comes back wrapped in one at
+// LegacyVersion with no type, so consumers decode both the same way.
+func Unmarshal(data []byte) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	if envelope.Type == "" || envelope.Payload == nil {
+		return &Envelope{SchemaVersion: LegacyVersion, Payload: data}, nil
+	}
+
+	return &envelope, nil
+}
+
+// ErrUnsupportedVersion is returned for a schema version the consumer does
+// not know yet, typically from a producer rolled out ahead of it
+type ErrUnsupportedVersion struct {
+	Type          string
+	SchemaVersion int
+}
+
+func (e ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("unsupported schema version %d of %s events", e.SchemaVersion, e.Type)
+}
+
+// ErrUnexpectedType is returned when an event is decoded as another type
+type ErrUnexpectedType struct {
+	Expected string
+	Actual   string
+}
+
+func (e ErrUnexpectedType) Error() string {
+	return fmt.Sprintf("expected a %s event, got %s", e.Expected, e.Actual)
+}
+
+// checkType accepts legacy envelopes, which have no type, as any type
+func checkType(envelope *Envelope, expected ...string) error {
+	if envelope.SchemaVersion == LegacyVersion && envelope.Type == "" {
+		return nil
+	}
+	for _, t := range expected {
+		if envelope.Type == t {
+			return nil
+		}
+	}
+	return &ErrUnexpectedType{Expected: expected[0], Actual: envelope.Type}
+}
+
+func decodePayload(envelope *Envelope, payload any) error {
+	if err := json.Unmarshal(envelope.Payload, payload); err != nil {
+		return fmt.Errorf("failed to unmarshal %s payload v%d: %w", envelope.Type, envelope.SchemaVersion, err)
+	}
+	return nil
+}
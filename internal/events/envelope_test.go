@@ -0,0 +1,141 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"uala-tweets/internal/domain"
+)
+
+// roundTrip encodes a new envelope and decodes it as a consumer would
+func roundTrip(envelope *Envelope, err error) (*Envelope, error) {
+	if err != nil {
+		return nil, err
+	}
+	data, err := Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	return Unmarshal(data)
+}
+
+func TestTweetCreated(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	tweet := &domain.Tweet{UserID: 42, Content: "hello", CreatedAt: createdAt, EventID: "event-1"}
+
+	envelope, err := roundTrip(NewTweetCreated(tweet))
+	require.NoError(t, err)
+	assert.Equal(t, TypeTweetCreated, envelope.Type)
+	assert.Equal(t, TweetCreatedVersion, envelope.SchemaVersion)
+	assert.Equal(t, Producer, envelope.Producer)
+	assert.Equal(t, "event-1", envelope.EventID)
+
+	decoded, err := DecodeTweetCreated(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, tweet, decoded)
+}
+
+func TestTimelineFanout(t *testing.T) {
+	event := &domain.TimelineFanoutEvent{EventID: "event-1", TweetID: 1, ListID: 7}
+
+	envelope, err := roundTrip(NewTimelineFanout(event))
+	require.NoError(t, err)
+
+	decoded, err := DecodeTimelineFanout(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, event, decoded)
+}
+
+func TestFollow(t *testing.T) {
+	for _, following := range []bool{true, false} {
+		event := &domain.FollowEvent{EventID: "event-1", FollowerID: 1, FollowedID: 2, Following: following}
+
+		envelope, err := roundTrip(NewFollow(event))
+		require.NoError(t, err)
+		wantType := TypeUserFollowed
+		if !following {
+			wantType = TypeUserUnfollowed
+		}
+		assert.Equal(t, wantType, envelope.Type)
+
+		decoded, err := DecodeFollow(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, event, decoded)
+	}
+}
+
+func TestUserEvent(t *testing.T) {
+	occurredAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	event := &domain.UserEvent{EventID: "event-1", Type: domain.UserEventDeleted, UserID: 42, Username: "johndoe", OccurredAt: occurredAt}
+
+	envelope, err := roundTrip(NewUserEvent(event))
+	require.NoError(t, err)
+	assert.Equal(t, TypeUserDeleted, envelope.Type)
+
+	decoded, err := DecodeUserEvent(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, event, decoded)
+}
+
+func TestNew_GeneratesEventID(t *testing.T) {
+	envelope, err := New(TypeTweetCreated, TweetCreatedVersion, "", time.Now(), TweetCreatedV1{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, envelope.EventID)
+}
+
+func TestUnmarshal_Legacy(t *testing.T) {
+	t.Run("tweet", func(t *testing.T) {
+		data, _ := json.Marshal(&domain.Tweet{UserID: 42, Content: "hello"})
+
+		envelope, err := Unmarshal(data)
+		require.NoError(t, err)
+		assert.Equal(t, LegacyVersion, envelope.SchemaVersion)
+
+		tweet, err := DecodeTweetCreated(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), tweet.UserID)
+		assert.Equal(t, "hello", tweet.Content)
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		data, _ := json.Marshal(&domain.FollowEvent{EventID: "event-1", FollowerID: 1, FollowedID: 2})
+
+		envelope, err := Unmarshal(data)
+		require.NoError(t, err)
+
+		event, err := DecodeFollow(envelope)
+		require.NoError(t, err)
+		assert.Equal(t, &domain.FollowEvent{EventID: "event-1", FollowerID: 1, FollowedID: 2}, event)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := Unmarshal([]byte("not json"))
+		assert.Error(t, err)
+	})
+}
+
+func TestDecode_Errors(t *testing.T) {
+	envelope, err := NewTweetCreated(&domain.Tweet{UserID: 42, Content: "hello"})
+	require.NoError(t, err)
+
+	t.Run("unsupported version", func(t *testing.T) {
+		future := *envelope
+		future.SchemaVersion = 99
+
+		_, err := DecodeTweetCreated(&future)
+		var unsupported *ErrUnsupportedVersion
+		require.ErrorAs(t, err, &unsupported)
+		assert.Equal(t, 99, unsupported.SchemaVersion)
+	})
+
+	t.Run("unexpected type", func(t *testing.T) {
+		_, err := DecodeFollow(envelope)
+		var unexpected *ErrUnexpectedType
+		require.ErrorAs(t, err, &unexpected)
+		assert.Equal(t, TypeTweetCreated, unexpected.Actual)
+	})
+}
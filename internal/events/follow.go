@@ -0,0 +1,66 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
+
+// Follows and unfollows are separate event types sharing a payload
+const (
+	TypeUserFollowed   = "user.followed"
+	TypeUserUnfollowed = "user.unfollowed"
+	// FollowVersion is the schema version follow events are published with
+	FollowVersion = 1
+)
+
+// FollowV1 is the payload of a follow or unfollow
+type FollowV1 struct {
+	FollowerID int `json:"follower_id"`
+	FollowedID int `json:"followed_id"`
+}
+
+// NewFollow wraps a follow event in a user.followed or user.unfollowed event
+func NewFollow(event *domain.FollowEvent) (*Envelope, error) {
+	eventType := TypeUserFollowed
+	if !event.Following {
+		eventType = TypeUserUnfollowed
+	}
+	return New(eventType, FollowVersion, event.EventID, time.Now(), FollowV1{
+		FollowerID: event.FollowerID,
+		FollowedID: event.FollowedID,
+	})
+}
+
+// DecodeFollow reads the follow event out of a user.followed or
+// user.unfollowed event of any schema version still in flight
+func DecodeFollow(envelope *Envelope) (*domain.FollowEvent, error) {
+	if err := checkType(envelope, TypeUserFollowed, TypeUserUnfollowed); err != nil {
+		return nil, err
+	}
+
+	switch envelope.SchemaVersion {
+	case LegacyVersion:
+		// Follows and unfollows were told apart by a flag in the payload
+		var event domain.FollowEvent
+		if err := json.Unmarshal(envelope.Payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal legacy follow event: %w", err)
+		}
+		return &event, nil
+	case 1:
+		var payload FollowV1
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return &domain.FollowEvent{
+			EventID:    envelope.EventID,
+			FollowerID: payload.FollowerID,
+			FollowedID: payload.FollowedID,
+			Following:  envelope.Type == TypeUserFollowed,
+		}, nil
+	default:
+		return nil, &ErrUnsupportedVersion{Type: envelope.Type, SchemaVersion: envelope.SchemaVersion}
+	}
+}
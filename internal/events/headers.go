@@ -0,0 +1,6 @@
+package events
+
+// RequestIDHeader is the Kafka header carrying the ID of the request an
+// event was published in, passed on to the events published in turn while
+// consuming it
+const RequestIDHeader = "x-request-id"
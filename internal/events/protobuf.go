@@ -0,0 +1,212 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const timestampSchema protoreflect.FullName = "google.protobuf.Timestamp"
+
+// ProtobufCodec encodes envelopes and their payloads with the Protobuf
+// schemas of a registry. Payloads are built from the same structs as JSON
+// ones, their JSON field names matching the schema's field names, so each
+// schema version only has to be written once in Go.
+type ProtobufCodec struct {
+	registry *SchemaRegistry
+}
+
+func NewProtobufCodec(registry *SchemaRegistry) *ProtobufCodec {
+	return &ProtobufCodec{registry: registry}
+}
+
+// DefaultProtobufCodec is the Protobuf codec of the DefaultSchemaRegistry
+var DefaultProtobufCodec = sync.OnceValues(func() (Codec, error) {
+	registry, err := DefaultSchemaRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return NewProtobufCodec(registry), nil
+})
+
+func (c *ProtobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (c *ProtobufCodec) Marshal(envelope *Envelope) ([]byte, error) {
+	schema, err := c.registry.Payload(envelope.Type, envelope.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := encodeMessage(schema, envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload v%d: %w", envelope.Type, envelope.SchemaVersion, err)
+	}
+
+	message := dynamicpb.NewMessage(c.registry.envelope)
+	fields := c.registry.envelope.Fields()
+	message.Set(fields.ByName("event_id"), protoreflect.ValueOfString(envelope.EventID))
+	message.Set(fields.ByName("type"), protoreflect.ValueOfString(envelope.Type))
+	message.Set(fields.ByName("schema_version"), protoreflect.ValueOfInt32(int32(envelope.SchemaVersion)))
+	setTimestamp(message, fields.ByName("occurred_at"), envelope.OccurredAt)
+	message.Set(fields.ByName("producer"), protoreflect.ValueOfString(envelope.Producer))
+	traceContext := message.Mutable(fields.ByName("trace_context")).Map()
+	for key, value := range envelope.TraceContext {
+		traceContext.Set(protoreflect.ValueOfString(key).MapKey(), protoreflect.ValueOfString(value))
+	}
+	message.Set(fields.ByName("payload"), protoreflect.ValueOfBytes(payload))
+
+	data, err := proto.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s envelope: %w", envelope.Type, err)
+	}
+	return data, nil
+}
+
+func (c *ProtobufCodec) Unmarshal(data []byte) (*Envelope, error) {
+	message := dynamicpb.NewMessage(c.registry.envelope)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	fields := c.registry.envelope.Fields()
+	envelope := &Envelope{
+		EventID:       message.Get(fields.ByName("event_id")).String(),
+		Type:          message.Get(fields.ByName("type")).String(),
+		SchemaVersion: int(message.Get(fields.ByName("schema_version")).Int()),
+		OccurredAt:    timestamp(message.Get(fields.ByName("occurred_at")).Message()),
+		Producer:      message.Get(fields.ByName("producer")).String(),
+	}
+	message.Get(fields.ByName("trace_context")).Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+		if envelope.TraceContext == nil {
+			envelope.TraceContext = make(map[string]string)
+		}
+		envelope.TraceContext[key.String()] = value.String()
+		return true
+	})
+
+	schema, err := c.registry.Payload(envelope.Type, envelope.SchemaVersion)
+	if err != nil {
+		return nil, err
+	}
+	if envelope.Payload, err = decodeMessage(schema, message.Get(fields.ByName("payload")).Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s payload v%d: %w", envelope.Type, envelope.SchemaVersion, err)
+	}
+	return envelope, nil
+}
+
+// encodeMessage encodes a JSON object with schema. A field the schema does
+// not have is an error rather than silently dropped.
+func encodeMessage(schema protoreflect.MessageDescriptor, data json.RawMessage) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	message := dynamicpb.NewMessage(schema)
+	for name, value := range object {
+		field := schema.Fields().ByName(protoreflect.Name(name))
+		if field == nil {
+			return nil, fmt.Errorf("field %s is not in schema %s", name, schema.FullName())
+		}
+		if value == nil {
+			continue
+		}
+		if err := setField(message, field, value); err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+	}
+	return proto.Marshal(message)
+}
+
+func setField(message *dynamicpb.Message, field protoreflect.FieldDescriptor, value any) error {
+	if field.Kind() == protoreflect.MessageKind && field.Message().FullName() == timestampSchema {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a time, got %T", value)
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		setTimestamp(message, field, t)
+		return nil
+	}
+
+	switch field.Kind() {
+	case protoreflect.StringKind:
+		if s, ok := value.(string); ok {
+			message.Set(field, protoreflect.ValueOfString(s))
+			return nil
+		}
+	case protoreflect.BoolKind:
+		if b, ok := value.(bool); ok {
+			message.Set(field, protoreflect.ValueOfBool(b))
+			return nil
+		}
+	case protoreflect.Int32Kind, protoreflect.Int64Kind:
+		n, ok := value.(json.Number)
+		if !ok {
+			break
+		}
+		i, err := n.Int64()
+		if err != nil {
+			return err
+		}
+		if field.Kind() == protoreflect.Int32Kind {
+			message.Set(field, protoreflect.ValueOfInt32(int32(i)))
+		} else {
+			message.Set(field, protoreflect.ValueOfInt64(i))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported field kind %s", field.Kind())
+	}
+	return fmt.Errorf("unexpected %T for a %s field", value, field.Kind())
+}
+
+// decodeMessage decodes data with schema into a JSON object. Fields the
+// schema does not know, from a newer producer, are left out.
+func decodeMessage(schema protoreflect.MessageDescriptor, data []byte) (json.RawMessage, error) {
+	message := dynamicpb.NewMessage(schema)
+	if err := proto.Unmarshal(data, message); err != nil {
+		return nil, err
+	}
+
+	object := make(map[string]any)
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if field.Kind() == protoreflect.MessageKind && field.Message().FullName() == timestampSchema {
+			object[string(field.Name())] = timestamp(value.Message())
+		} else {
+			object[string(field.Name())] = value.Interface()
+		}
+		return true
+	})
+	return json.Marshal(object)
+}
+
+func setTimestamp(message *dynamicpb.Message, field protoreflect.FieldDescriptor, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	ts := message.NewField(field).Message()
+	ts.Set(ts.Descriptor().Fields().ByName("seconds"), protoreflect.ValueOfInt64(t.Unix()))
+	ts.Set(ts.Descriptor().Fields().ByName("nanos"), protoreflect.ValueOfInt32(int32(t.Nanosecond())))
+	message.Set(field, protoreflect.ValueOfMessage(ts))
+}
+
+func timestamp(ts protoreflect.Message) time.Time {
+	fields := ts.Descriptor().Fields()
+	seconds := ts.Get(fields.ByName("seconds")).Int()
+	nanos := ts.Get(fields.ByName("nanos")).Int()
+	if seconds == 0 && nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, nanos).UTC()
+}
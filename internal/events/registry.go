@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+//go:embed schemas/*.proto
+var schemaFiles embed.FS
+
+const envelopeSchema protoreflect.FullName = "uala.events.Envelope"
+
+type schemaKey struct {
+	eventType     string
+	schemaVersion int
+}
+
+// payloadSchemas names the Protobuf message every event type and schema
+// version is encoded with. A new schema version needs a new message here.
+var payloadSchemas = map[schemaKey]protoreflect.FullName{
+	{TypeTweetCreated, 1}:    "uala.events.TweetCreatedV1",
+	{TypeTimelineFanout, 1}:  "uala.events.TimelineFanoutV1",
+	{TypeUserFollowed, 1}:    "uala.events.FollowV1",
+	{TypeUserUnfollowed, 1}:  "uala.events.FollowV1",
+	{TypeUserDeactivated, 1}: "uala.events.UserV1",
+	{TypeUserReactivated, 1}: "uala.events.UserV1",
+	{TypeUserDeleted, 1}:     "uala.events.UserV1",
+}
+
+// SchemaRegistry holds the Protobuf schemas of the envelope and of every
+// payload, compiled from .proto files
+type SchemaRegistry struct {
+	files    []protoreflect.FileDescriptor
+	envelope protoreflect.MessageDescriptor
+	payloads map[schemaKey]protoreflect.MessageDescriptor
+}
+
+// NewSchemaRegistry compiles every .proto file at the root of fsys and
+// checks it has a schema for the envelope and every payload
+func NewSchemaRegistry(fsys fs.FS) (*SchemaRegistry, error) {
+	registry, err := compileSchemas(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := registry.messages()
+	var ok bool
+	if registry.envelope, ok = messages[envelopeSchema]; !ok {
+		return nil, fmt.Errorf("schema %s not found", envelopeSchema)
+	}
+	for key, name := range payloadSchemas {
+		if registry.payloads[key], ok = messages[name]; !ok {
+			return nil, fmt.Errorf("schema %s of %s v%d not found", name, key.eventType, key.schemaVersion)
+		}
+	}
+	return registry, nil
+}
+
+func compileSchemas(fsys fs.FS) (*SchemaRegistry, error) {
+	paths, err := fs.Glob(fsys, "*.proto")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: func(path string) (io.ReadCloser, error) {
+				return fsys.Open(path)
+			},
+		}),
+	}
+	compiled, err := compiler.Compile(context.Background(), paths...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schemas: %w", err)
+	}
+
+	registry := &SchemaRegistry{payloads: make(map[schemaKey]protoreflect.MessageDescriptor)}
+	for _, file := range compiled {
+		registry.files = append(registry.files, file)
+	}
+	return registry, nil
+}
+
+// Payload returns the schema of the payload of an event type and version
+func (r *SchemaRegistry) Payload(eventType string, schemaVersion int) (protoreflect.MessageDescriptor, error) {
+	message, ok := r.payloads[schemaKey{eventType, schemaVersion}]
+	if !ok {
+		return nil, &ErrUnsupportedVersion{Type: eventType, SchemaVersion: schemaVersion}
+	}
+	return message, nil
+}
+
+// DefaultSchemaRegistry is the registry of the schemas built into the
+// service, compiled on first use
+var DefaultSchemaRegistry = sync.OnceValues(func() (*SchemaRegistry, error) {
+	fsys, err := fs.Sub(schemaFiles, "schemas")
+	if err != nil {
+		return nil, err
+	}
+	return NewSchemaRegistry(fsys)
+})
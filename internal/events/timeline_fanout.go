@@ -0,0 +1,62 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
+
+const (
+	TypeTimelineFanout = "timeline.fanout"
+	// TimelineFanoutVersion is the schema version fanout events are
+	// published with
+	TimelineFanoutVersion = 1
+)
+
+// TimelineFanoutV1 is the payload of a tweet to add to one timeline
+type TimelineFanoutV1 struct {
+	TweetID int64 `json:"tweet_id"`
+	UserID  int   `json:"user_id,omitempty"`
+	ListID  int   `json:"list_id,omitempty"`
+}
+
+// NewTimelineFanout wraps a fanout event in a timeline.fanout event
+func NewTimelineFanout(event *domain.TimelineFanoutEvent) (*Envelope, error) {
+	return New(TypeTimelineFanout, TimelineFanoutVersion, event.EventID, time.Now(), TimelineFanoutV1{
+		TweetID: event.TweetID,
+		UserID:  event.UserID,
+		ListID:  event.ListID,
+	})
+}
+
+// DecodeTimelineFanout reads the fanout event out of a timeline.fanout
+// event of any schema version still in flight
+func DecodeTimelineFanout(envelope *Envelope) (*domain.TimelineFanoutEvent, error) {
+	if err := checkType(envelope, TypeTimelineFanout); err != nil {
+		return nil, err
+	}
+
+	switch envelope.SchemaVersion {
+	case LegacyVersion:
+		var event domain.TimelineFanoutEvent
+		if err := json.Unmarshal(envelope.Payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal legacy fanout event: %w", err)
+		}
+		return &event, nil
+	case 1:
+		var payload TimelineFanoutV1
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return &domain.TimelineFanoutEvent{
+			EventID: envelope.EventID,
+			TweetID: payload.TweetID,
+			UserID:  payload.UserID,
+			ListID:  payload.ListID,
+		}, nil
+	default:
+		return nil, &ErrUnsupportedVersion{Type: TypeTimelineFanout, SchemaVersion: envelope.SchemaVersion}
+	}
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
+
+const (
+	TypeTweetCreated = "tweet.created"
+	// TweetCreatedVersion is the schema version tweets are published with
+	TweetCreatedVersion = 1
+)
+
+// TweetCreatedV1 is the payload of a tweet waiting to be stored
+type TweetCreatedV1 struct {
+	UserID    int64     `json:"user_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewTweetCreated wraps a tweet in a tweet.created event
+func NewTweetCreated(tweet *domain.Tweet) (*Envelope, error) {
+	return New(TypeTweetCreated, TweetCreatedVersion, tweet.EventID, tweet.CreatedAt, TweetCreatedV1{
+		UserID:    tweet.UserID,
+		Content:   tweet.Content,
+		CreatedAt: tweet.CreatedAt,
+	})
+}
+
+// DecodeTweetCreated reads the tweet out of a tweet.created event of any
+// schema version still in flight
+func DecodeTweetCreated(envelope *Envelope) (*domain.Tweet, error) {
+	if err := checkType(envelope, TypeTweetCreated); err != nil {
+		return nil, err
+	}
+
+	switch envelope.SchemaVersion {
+	case LegacyVersion:
+		// A domain.Tweet marshalled as is, field names and all
+		var tweet domain.Tweet
+		if err := json.Unmarshal(envelope.Payload, &tweet); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal legacy tweet: %w", err)
+		}
+		return &tweet, nil
+	case 1:
+		var payload TweetCreatedV1
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return &domain.Tweet{
+			UserID:    payload.UserID,
+			Content:   payload.Content,
+			CreatedAt: payload.CreatedAt,
+			EventID:   envelope.EventID,
+		}, nil
+	default:
+		return nil, &ErrUnsupportedVersion{Type: TypeTweetCreated, SchemaVersion: envelope.SchemaVersion}
+	}
+}
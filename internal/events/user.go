@@ -0,0 +1,63 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"uala-tweets/internal/domain"
+)
+
+// Each change in the lifecycle of an account is its own event type
+const (
+	TypeUserDeactivated = "user.deactivated"
+	TypeUserReactivated = "user.reactivated"
+	TypeUserDeleted     = "user.deleted"
+	// UserVersion is the schema version user events are published with
+	UserVersion = 1
+)
+
+// UserV1 is the payload of a change in the lifecycle of an account
+type UserV1 struct {
+	UserID   int    `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// NewUserEvent wraps a user event in the event type of its change
+func NewUserEvent(event *domain.UserEvent) (*Envelope, error) {
+	return New("user."+string(event.Type), UserVersion, event.EventID, event.OccurredAt, UserV1{
+		UserID:   event.UserID,
+		Username: event.Username,
+	})
+}
+
+// DecodeUserEvent reads the user event out of a user lifecycle event of any
+// schema version still in flight
+func DecodeUserEvent(envelope *Envelope) (*domain.UserEvent, error) {
+	if err := checkType(envelope, TypeUserDeactivated, TypeUserReactivated, TypeUserDeleted); err != nil {
+		return nil, err
+	}
+
+	switch envelope.SchemaVersion {
+	case LegacyVersion:
+		var event domain.UserEvent
+		if err := json.Unmarshal(envelope.Payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal legacy user event: %w", err)
+		}
+		return &event, nil
+	case 1:
+		var payload UserV1
+		if err := decodePayload(envelope, &payload); err != nil {
+			return nil, err
+		}
+		return &domain.UserEvent{
+			EventID:    envelope.EventID,
+			Type:       domain.UserEventType(strings.TrimPrefix(envelope.Type, "user.")),
+			UserID:     payload.UserID,
+			Username:   payload.Username,
+			OccurredAt: envelope.OccurredAt,
+		}, nil
+	default:
+		return nil, &ErrUnsupportedVersion{Type: envelope.Type, SchemaVersion: envelope.SchemaVersion}
+	}
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"uala-tweets/internal/application"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeactivationResponse represents the response for deactivating an account
+type DeactivationResponse struct {
+	UserID int `json:"user_id" example:"123"`
+	// Time after which the account and its data are permanently deleted
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// AccountErrorResponse represents an error response for account operations
+type AccountErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type AccountHandler struct {
+	accountService *application.AccountService
+}
+
+func NewAccountHandler(accountService *application.AccountService) *AccountHandler {
+	return &AccountHandler{accountService: accountService}
+}
+
+// DeactivateAccount schedules an account for deletion
+// @Summary      Delete an account
+// @Description  Deactivate an account; it is permanently deleted with its tweets, follows and lists once the grace period has passed unless reactivated before
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      202  {object}  DeactivationResponse
+// @Failure      400  {object}  AccountErrorResponse
+// @Failure      401  {object}  AccountErrorResponse
+// @Failure      403  {object}  AccountErrorResponse
+// @Failure      404  {object}  AccountErrorResponse
+// @Failure      500  {object}  AccountErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id} [delete]
+func (h *AccountHandler) DeactivateAccount(c *gin.Context) {
+	userID := currentUserID(c)
+
+	deletionAt, err := h.accountService.Deactivate(c.Request.Context(), userID)
+	if err != nil {
+		writeAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, DeactivationResponse{UserID: userID, DeletionScheduledAt: deletionAt})
+}
+
+// ReactivateAccount cancels the deletion of an account
+// @Summary      Reactivate an account
+// @Description  Cancel the pending deletion of a deactivated account
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "User ID"
+// @Success      204
+// @Failure      400  {object}  AccountErrorResponse
+// @Failure      401  {object}  AccountErrorResponse
+// @Failure      403  {object}  AccountErrorResponse
+// @Failure      404  {object}  AccountErrorResponse
+// @Failure      500  {object}  AccountErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/reactivate [post]
+func (h *AccountHandler) ReactivateAccount(c *gin.Context) {
+	userID := currentUserID(c)
+
+	if err := h.accountService.Reactivate(c.Request.Context(), userID); err != nil {
+		writeAccountError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ExportAccount streams a user's data
+// @Summary      Export account data
+// @Description  Download the profile, tweets and follows of a user as JSON lines, one {"type", "data"} record per line. An export failing partway through ends with an "error" record.
+// @Tags         users
+// @Produce      application/x-ndjson
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   application.ExportRecord
+// @Failure      400  {object}  AccountErrorResponse
+// @Failure      401  {object}  AccountErrorResponse
+// @Failure      403  {object}  AccountErrorResponse
+// @Failure      404  {object}  AccountErrorResponse
+// @Failure      500  {object}  AccountErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/export [get]
+func (h *AccountHandler) ExportAccount(c *gin.Context) {
+	userID := currentUserID(c)
+
+	// Headers are only sent with the first record, so errors found before
+	// anything is written can still be reported as JSON
+	started := false
+	encoder := json.NewEncoder(c.Writer)
+	err := h.accountService.Export(c.Request.Context(), userID, func(record application.ExportRecord) error {
+		if !started {
+			started = true
+			c.Header("Content-Type", "application/x-ndjson")
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-export.jsonl"`, userID))
+			c.Status(http.StatusOK)
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		if started {
+			// Too late to change the status; end the stream with an error
+			// record so the client knows the download is incomplete
+			slog.ErrorContext(c.Request.Context(), "Error exporting account data", "user_id", userID, "error", err)
+			_ = encoder.Encode(application.ExportRecord{
+				Type: application.ExportRecordError,
+				Data: AccountErrorResponse{Error: "export failed"},
+			})
+			return
+		}
+		writeAccountError(c, err)
+	}
+}
+
+func writeAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, AccountErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrUserNotFound)):
+		c.JSON(http.StatusNotFound, AccountErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, AccountErrorResponse{Error: "internal server error"})
+	}
+}
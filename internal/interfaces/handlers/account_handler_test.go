@@ -0,0 +1,44 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/interfaces/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHandler_ExportAccount_EndsFailedExportsWithAnError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userRepo := &application.MockUserRepository{}
+	userRepo.On("GetByID", 0).Return(&domain.User{Username: "alice"}, nil)
+	tweetRepo := &application.MockTweetRepository{}
+	tweetRepo.On("GetByUserID", int64(0)).Return([]*domain.Tweet{{ID: 10, Content: "hi", EventID: "event-10"}}, nil)
+	followRepo := &application.MockFollowRepository{}
+	followRepo.On("GetFollowing", 0).Return(nil, errors.New("db error"))
+
+	accountService := application.NewAccountService(userRepo, followRepo, tweetRepo, nil, nil, nil, nil, time.Hour)
+	router := gin.New()
+	router.GET("/users/:id/export", handlers.NewAccountHandler(accountService).ExportAccount)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/0/export", nil))
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"type":"tweet","data":{"id":10,"content":"hi","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}}`, lines[1])
+
+	var last application.ExportRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &last))
+	assert.Equal(t, application.ExportRecordError, last.Type)
+}
@@ -0,0 +1,375 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceRunResponse represents the outcome of a maintenance task
+type MaintenanceRunResponse struct {
+	// Number of users or accounts processed successfully
+	Processed int `json:"processed" example:"120"`
+	// Number that failed and are retried on the next scheduled run
+	Failed int `json:"failed" example:"0"`
+}
+
+// SetRoleRequest represents the request body for changing a user's role
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user moderator admin" example:"moderator"`
+}
+
+// UserRoleResponse represents the role of a user
+type UserRoleResponse struct {
+	UserID int    `json:"user_id" example:"123"`
+	Role   string `json:"role" example:"moderator"`
+}
+
+// DeadLetterResponse represents a message a consumer gave up on
+type DeadLetterResponse struct {
+	ID       int64  `json:"id" example:"12"`
+	Consumer string `json:"consumer" example:"tweet-consumer"`
+	// Where the message was first published, and where replays go
+	Topic     string `json:"topic" example:"tweets.created"`
+	Partition int    `json:"partition" example:"0"`
+	Offset    int64  `json:"offset" example:"1042"`
+	Key       string `json:"key,omitempty" example:"tweet_42"`
+	// The payload as text, or base64 encoded when it is not valid UTF-8
+	Payload         string            `json:"payload" example:"{\"user_id\":42,\"content\":\"hello\"}"`
+	PayloadEncoding string            `json:"payload_encoding" example:"utf-8" enums:"utf-8,base64"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Error           string            `json:"error" example:"error persisting tweet: connection refused"`
+	Attempts        int               `json:"attempts" example:"4"`
+	FailedAt        time.Time         `json:"failed_at"`
+	ReplayCount     int               `json:"replay_count" example:"0"`
+	LastReplayedAt  *time.Time        `json:"last_replayed_at,omitempty"`
+}
+
+// DeadLetterReplayResponse represents a replay of a dead letter
+type DeadLetterReplayResponse struct {
+	// Who replayed it: user:<id> through the API, cli:<name> through the CLI
+	ReplayedBy string    `json:"replayed_by" example:"user:1"`
+	ReplayedAt time.Time `json:"replayed_at"`
+}
+
+// DeadLetterDetailResponse represents a dead letter with its replays
+type DeadLetterDetailResponse struct {
+	DeadLetterResponse
+	Replays []DeadLetterReplayResponse `json:"replays"`
+}
+
+// ReplayDeadLettersRequest selects the dead letters to replay: either ids,
+// or every dead letter that failed between from and to
+type ReplayDeadLettersRequest struct {
+	IDs []int64 `json:"ids,omitempty" example:"12,13"`
+	// Start of the time range, inclusive
+	From *time.Time `json:"from,omitempty" example:"2024-01-01T00:00:00Z"`
+	// End of the time range, exclusive
+	To *time.Time `json:"to,omitempty" example:"2024-01-02T00:00:00Z"`
+	// Only replay dead letters of this consumer, with a time range
+	Consumer string `json:"consumer,omitempty" example:"tweet-consumer"`
+	// Only replay dead letters first published to this topic, with a time range
+	Topic string `json:"topic,omitempty" example:"tweets.created"`
+}
+
+// ReplayDeadLettersResponse represents the dead letters that were replayed
+type ReplayDeadLettersResponse struct {
+	Replayed []int64 `json:"replayed" example:"12,13"`
+	Count    int     `json:"count" example:"2"`
+}
+
+// AdminErrorResponse represents an error response for admin operations
+type AdminErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type AdminHandler struct {
+	adminService *application.AdminService
+}
+
+func NewAdminHandler(adminService *application.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// RefreshSuggestions recomputes follow suggestions now
+// @Summary      Refresh follow suggestions
+// @Description  Recompute the follow suggestions of every user without waiting for the scheduled job. Requires the admin role.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  MaintenanceRunResponse
+// @Failure      401  {object}  AdminErrorResponse
+// @Failure      403  {object}  AdminErrorResponse
+// @Failure      500  {object}  AdminErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /admin/suggestions/refresh [post]
+func (h *AdminHandler) RefreshSuggestions(c *gin.Context) {
+	refreshed, failed, err := h.adminService.RefreshSuggestions(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MaintenanceRunResponse{Processed: refreshed, Failed: failed})
+}
+
+// PurgeAccounts deletes the accounts due for deletion now
+// @Summary      Purge deactivated accounts
+// @Description  Permanently delete the accounts whose deactivation grace period has passed without waiting for the scheduled job. Requires the admin role.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  MaintenanceRunResponse
+// @Failure      401  {object}  AdminErrorResponse
+// @Failure      403  {object}  AdminErrorResponse
+// @Failure      500  {object}  AdminErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /admin/accounts/purge [post]
+func (h *AdminHandler) PurgeAccounts(c *gin.Context) {
+	purged, failed, err := h.adminService.PurgeDeactivatedAccounts(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MaintenanceRunResponse{Processed: purged, Failed: failed})
+}
+
+// SetUserRole changes the role of a user
+// @Summary      Set a user's role
+// @Description  Make a user a regular user, moderator or admin. Admins cannot change their own role. Requires the admin role.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int             true  "User ID"
+// @Param        role  body      SetRoleRequest  true  "New role"
+// @Success      200  {object}  UserRoleResponse
+// @Failure      400  {object}  AdminErrorResponse
+// @Failure      401  {object}  AdminErrorResponse
+// @Failure      403  {object}  AdminErrorResponse
+// @Failure      404  {object}  AdminErrorResponse
+// @Failure      500  {object}  AdminErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /admin/users/{id}/role [put]
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: "invalid user id"})
+		return
+	}
+
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, err := h.adminService.SetRole(c.Request.Context(), currentUserID(c), userID, domain.Role(req.Role))
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UserRoleResponse{UserID: user.ID, Role: string(user.Role)})
+}
+
+// ListDeadLetters returns the messages consumers gave up on
+// @Summary      List dead letters
+// @Description  List the messages consumers gave up on, most recent failure first, with the reason they failed. Requires the admin role.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        consumer  query     string  false  "Only dead letters of this consumer"
+// @Param        topic     query     string  false  "Only dead letters first published to this topic"
+// @Param        from      query     string  false  "Only dead letters that failed at or after this time (RFC 3339)"
+// @Param        to        query     string  false  "Only dead letters that failed before this time (RFC 3339)"
+// @Param        limit     query     int     false  "Maximum number of dead letters to return (default 50, max 500)"
+// @Success      200  {array}   DeadLetterResponse
+// @Failure      400  {object}  AdminErrorResponse
+// @Failure      401  {object}  AdminErrorResponse
+// @Failure      403  {object}  AdminErrorResponse
+// @Failure      500  {object}  AdminErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /admin/dead-letters [get]
+func (h *AdminHandler) ListDeadLetters(c *gin.Context) {
+	filter := domain.DeadLetterFilter{
+		Consumer: c.Query("consumer"),
+		Topic:    c.Query("topic"),
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: err.Error()})
+		return
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: err.Error()})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: "invalid limit"})
+			return
+		}
+	}
+
+	deadLetters, err := h.adminService.ListDeadLetters(c.Request.Context(), currentUserID(c), filter)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	response := make([]DeadLetterResponse, len(deadLetters))
+	for i, deadLetter := range deadLetters {
+		response[i] = newDeadLetterResponse(deadLetter)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDeadLetter returns a dead letter with its payload and replays
+// @Summary      Inspect a dead letter
+// @Description  Get a message a consumer gave up on, with its payload, headers and every time it was replayed. Requires the admin role.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "Dead letter ID"
+// @Success      200  {object}  DeadLetterDetailResponse
+// @Failure      400  {object}  AdminErrorResponse
+// @Failure      401  {object}  AdminErrorResponse
+// @Failure      403  {object}  AdminErrorResponse
+// @Failure      404  {object}  AdminErrorResponse
+// @Failure      500  {object}  AdminErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /admin/dead-letters/{id} [get]
+func (h *AdminHandler) GetDeadLetter(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: "invalid dead letter id"})
+		return
+	}
+
+	deadLetter, replays, err := h.adminService.GetDeadLetter(c.Request.Context(), currentUserID(c), id)
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	response := DeadLetterDetailResponse{
+		DeadLetterResponse: newDeadLetterResponse(deadLetter),
+		Replays:            make([]DeadLetterReplayResponse, len(replays)),
+	}
+	for i, replay := range replays {
+		response.Replays[i] = DeadLetterReplayResponse{ReplayedBy: replay.ReplayedBy, ReplayedAt: replay.ReplayedAt}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ReplayDeadLetters publishes dead letters back onto their original topics
+// @Summary      Replay dead letters
+// @Description  Publish the selected dead letters, or every dead letter that failed in a time range, back onto the topic they were first published to. Each replay is recorded with the admin who made it. Requires the admin role.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        selection  body      ReplayDeadLettersRequest  true  "Dead letters to replay"
+// @Success      200  {object}  ReplayDeadLettersResponse
+// @Failure      400  {object}  AdminErrorResponse
+// @Failure      401  {object}  AdminErrorResponse
+// @Failure      403  {object}  AdminErrorResponse
+// @Failure      404  {object}  AdminErrorResponse
+// @Failure      500  {object}  AdminErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /admin/dead-letters/replay [post]
+func (h *AdminHandler) ReplayDeadLetters(c *gin.Context) {
+	var req ReplayDeadLettersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: err.Error()})
+		return
+	}
+
+	hasRange := req.From != nil || req.To != nil
+	if len(req.IDs) > 0 == hasRange {
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: "either ids or from and to are required"})
+		return
+	}
+
+	var replayed []int64
+	var err error
+	if hasRange {
+		filter := domain.DeadLetterFilter{Consumer: req.Consumer, Topic: req.Topic}
+		if req.From != nil {
+			filter.From = *req.From
+		}
+		if req.To != nil {
+			filter.To = *req.To
+		}
+		replayed, err = h.adminService.ReplayDeadLettersBetween(c.Request.Context(), currentUserID(c), filter)
+	} else {
+		replayed, err = h.adminService.ReplayDeadLetters(c.Request.Context(), currentUserID(c), req.IDs)
+	}
+	if err != nil {
+		writeAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ReplayDeadLettersResponse{Replayed: replayed, Count: len(replayed)})
+}
+
+func newDeadLetterResponse(deadLetter *domain.DeadLetter) DeadLetterResponse {
+	response := DeadLetterResponse{
+		ID:              deadLetter.ID,
+		Consumer:        deadLetter.Consumer,
+		Topic:           deadLetter.Topic,
+		Partition:       deadLetter.Partition,
+		Offset:          deadLetter.Offset,
+		Key:             string(deadLetter.Key),
+		Payload:         string(deadLetter.Payload),
+		PayloadEncoding: "utf-8",
+		Headers:         deadLetter.Headers,
+		Error:           deadLetter.Error,
+		Attempts:        deadLetter.Attempts,
+		FailedAt:        deadLetter.FailedAt,
+		ReplayCount:     deadLetter.ReplayCount,
+		LastReplayedAt:  deadLetter.LastReplayedAt,
+	}
+	if !utf8.Valid(deadLetter.Payload) {
+		response.Payload = base64.StdEncoding.EncodeToString(deadLetter.Payload)
+		response.PayloadEncoding = "base64"
+	}
+	return response
+}
+
+// parseTimeQuery reads an optional RFC 3339 time from the query string
+func parseTimeQuery(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + key + ", expected an RFC 3339 time")
+	}
+	return t, nil
+}
+
+func writeAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, AdminErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrForbidden)):
+		c.JSON(http.StatusForbidden, AdminErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrUserNotFound)):
+		c.JSON(http.StatusNotFound, AdminErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrDeadLetterNotFound)):
+		c.JSON(http.StatusNotFound, AdminErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, AdminErrorResponse{Error: "internal server error"})
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyResponse represents an API key without its secret
+type APIKeyResponse struct {
+	ID         int64      `json:"id" example:"1"`
+	Name       string     `json:"name" example:"scheduler"`
+	Prefix     string     `json:"prefix" example:"uala_3q2-7w"`
+	Scopes     []string   `json:"scopes" example:"tweets:write,timeline:read"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse represents a newly created API key
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	// The key itself, sent as "Authorization: Bearer <key>". It is only
+	// shown once.
+	Key string `json:"key" example:"uala_3q2-7wF0mZ..."`
+}
+
+// APIKeyErrorResponse represents an error response for API key operations
+type APIKeyErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+// CreateAPIKeyRequest represents the request body for creating an API key
+type CreateAPIKeyRequest struct {
+	// Name to recognize the key by
+	// required: true
+	// example: scheduler
+	Name string `json:"name" binding:"required,max=50"`
+
+	// What the key may do: tweets:write, follows:write, timeline:read
+	// required: true
+	// example: ["tweets:write"]
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+type APIKeyHandler struct {
+	apiKeyService *application.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService *application.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyService: apiKeyService}
+}
+
+// CreateAPIKey mints an API key
+// @Summary      Create an API key
+// @Description  Create a key for bots and integrations to act as the user within its scopes. The key is only returned by this call.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                  true  "User ID"
+// @Param        request  body      CreateAPIKeyRequest  true  "Key to create"
+// @Success      201  {object}  CreatedAPIKeyResponse
+// @Failure      400  {object}  APIKeyErrorResponse
+// @Failure      401  {object}  APIKeyErrorResponse
+// @Failure      403  {object}  APIKeyErrorResponse
+// @Failure      500  {object}  APIKeyErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID := currentUserID(c)
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, APIKeyErrorResponse{Error: err.Error()})
+		return
+	}
+
+	scopes := make([]domain.APIKeyScope, len(req.Scopes))
+	for i, scope := range req.Scopes {
+		scopes[i] = domain.APIKeyScope(scope)
+	}
+
+	key, secret, err := h.apiKeyService.CreateAPIKey(c.Request.Context(), userID, application.CreateAPIKeyInput{
+		Name:   req.Name,
+		Scopes: scopes,
+	})
+	if err != nil {
+		writeAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedAPIKeyResponse{
+		APIKeyResponse: newAPIKeyResponse(key),
+		Key:            secret,
+	})
+}
+
+// ListAPIKeys lists a user's API keys
+// @Summary      List API keys
+// @Description  Get the API keys of a user that have not been revoked
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   APIKeyResponse
+// @Failure      401  {object}  APIKeyErrorResponse
+// @Failure      403  {object}  APIKeyErrorResponse
+// @Failure      500  {object}  APIKeyErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListAPIKeys(c.Request.Context(), currentUserID(c))
+	if err != nil {
+		writeAPIKeyError(c, err)
+		return
+	}
+
+	response := make([]APIKeyResponse, len(keys))
+	for i, key := range keys {
+		response[i] = newAPIKeyResponse(key)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey revokes an API key
+// @Summary      Revoke an API key
+// @Description  Revoke an API key; requests using it are rejected from now on
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        id      path  int  true  "User ID"
+// @Param        key_id  path  int  true  "API key ID"
+// @Success      204
+// @Failure      400  {object}  APIKeyErrorResponse
+// @Failure      401  {object}  APIKeyErrorResponse
+// @Failure      403  {object}  APIKeyErrorResponse
+// @Failure      404  {object}  APIKeyErrorResponse
+// @Failure      500  {object}  APIKeyErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/api-keys/{key_id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	keyID, err := strconv.ParseInt(c.Param("key_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, APIKeyErrorResponse{Error: "invalid API key ID"})
+		return
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.Request.Context(), currentUserID(c), keyID); err != nil {
+		writeAPIKeyError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, APIKeyErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrAPIKeyNotFound)):
+		c.JSON(http.StatusNotFound, APIKeyErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, APIKeyErrorResponse{Error: "internal server error"})
+	}
+}
+
+func newAPIKeyResponse(key *domain.APIKey) APIKeyResponse {
+	scopes := make([]string, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = string(scope)
+	}
+	return APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
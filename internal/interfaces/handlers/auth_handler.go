@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"uala-tweets/internal/application"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CredentialsRequest represents the request body for registering or logging in
+type CredentialsRequest struct {
+	// example: johndoe
+	Username string `json:"username" binding:"required"`
+	// At least 8 characters
+	// example: correct horse battery staple
+	Password string `json:"password" binding:"required"`
+}
+
+// RefreshTokenRequest represents the request body for refreshing or revoking tokens
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse represents the tokens issued after authenticating
+type TokenResponse struct {
+	UserID int `json:"user_id,omitempty" example:"123"`
+	// Send as "Authorization: Bearer <access_token>"
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type" example:"Bearer"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+// AuthErrorResponse represents an error response for authentication operations
+type AuthErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type AuthHandler struct {
+	authService *application.AuthService
+}
+
+func NewAuthHandler(authService *application.AuthService) *AuthHandler {
+	return &AuthHandler{authService: authService}
+}
+
+// Register creates an account
+// @Summary      Register
+// @Description  Create an account with a username and password and log in
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CredentialsRequest  true  "Credentials"
+// @Success      201  {object}  TokenResponse
+// @Failure      400  {object}  AuthErrorResponse
+// @Failure      409  {object}  AuthErrorResponse
+// @Failure      429  {object}  AuthErrorResponse
+// @Failure      500  {object}  AuthErrorResponse
+// @Router       /auth/register [post]
+func (h *AuthHandler) Register(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AuthErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, tokens, err := h.authService.Register(c.Request.Context(), application.RegisterInput{
+		Username: req.Username,
+		Password: req.Password,
+	})
+	if err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	response := newTokenResponse(tokens)
+	response.UserID = user.ID
+	c.JSON(http.StatusCreated, response)
+}
+
+// Login authenticates with a username and password
+// @Summary      Log in
+// @Description  Exchange a username and password for an access token and a refresh token
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CredentialsRequest  true  "Credentials"
+// @Success      200  {object}  TokenResponse
+// @Failure      400  {object}  AuthErrorResponse
+// @Failure      401  {object}  AuthErrorResponse
+// @Failure      429  {object}  AuthErrorResponse
+// @Failure      500  {object}  AuthErrorResponse
+// @Router       /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req CredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AuthErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tokens, err := h.authService.Login(c.Request.Context(), req.Username, req.Password)
+	if err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// Refresh issues a new token pair
+// @Summary      Refresh tokens
+// @Description  Exchange a refresh token for a new access token and refresh token. Each refresh token can be used once; reusing one revokes the whole session.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RefreshTokenRequest  true  "Refresh token"
+// @Success      200  {object}  TokenResponse
+// @Failure      400  {object}  AuthErrorResponse
+// @Failure      401  {object}  AuthErrorResponse
+// @Failure      429  {object}  AuthErrorResponse
+// @Failure      500  {object}  AuthErrorResponse
+// @Router       /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AuthErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// Logout revokes a refresh token
+// @Summary      Log out
+// @Description  Revoke the refresh token and every token refreshed from the same login. Access tokens stay valid until they expire.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request  body  RefreshTokenRequest  true  "Refresh token"
+// @Success      204
+// @Failure      400  {object}  AuthErrorResponse
+// @Failure      401  {object}  AuthErrorResponse
+// @Failure      429  {object}  AuthErrorResponse
+// @Failure      500  {object}  AuthErrorResponse
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, AuthErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken); err != nil {
+		writeAuthError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func newTokenResponse(tokens *application.TokenPair) TokenResponse {
+	return TokenResponse{
+		AccessToken:           tokens.AccessToken,
+		TokenType:             "Bearer",
+		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt,
+		RefreshToken:          tokens.RefreshToken,
+		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
+	}
+}
+
+func writeAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, AuthErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrUserAlreadyExists)):
+		c.JSON(http.StatusConflict, AuthErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrInvalidCredentials)),
+		errors.As(err, new(*application.ErrUnauthorized)):
+		c.JSON(http.StatusUnauthorized, AuthErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, AuthErrorResponse{Error: "internal server error"})
+	}
+}
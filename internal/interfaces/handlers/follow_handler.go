@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"uala-tweets/internal/application"
 
@@ -13,6 +15,22 @@ import (
 // FollowResponse represents a success response for follow operations
 type FollowResponse struct {
 	Message string `json:"message" example:"successfully followed user"`
+	Status  string `json:"status,omitempty" example:"following"`
+}
+
+// FollowRequestResponse represents a pending follow request
+type FollowRequestResponse struct {
+	RequesterID int       `json:"requester_id" example:"123"`
+	TargetID    int       `json:"target_id" example:"456"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SetProtectedRequest represents the request body for protecting an account
+type SetProtectedRequest struct {
+	// Whether new followers need to be approved
+	// required: true
+	// example: true
+	Protected *bool `json:"protected" binding:"required"`
 }
 
 // FollowErrorResponse represents an error response for follow operations
@@ -40,24 +58,26 @@ type FollowRequest struct {
 
 // FollowUser follows another user
 // @Summary      Follow a user
-// @Description  Follow another user by their ID
+// @Description  Follow another user by their ID. Following a protected account sends a follow request instead. API keys need the follows:write scope.
 // @Tags         follows
 // @Accept       json
 // @Produce      json
 // @Param        id    path      int  true  "Follower User ID"
 // @Param        target_id  path  int  true  "Target User ID to follow"
+// @Param        Idempotency-Key  header  string  false  "Unique key; retries with the same key get the first response back"
 // @Success      200  {object}  FollowResponse
+// @Success      202  {object}  FollowResponse
 // @Failure      400  {object}  FollowErrorResponse
+// @Failure      401  {object}  FollowErrorResponse
+// @Failure      403  {object}  FollowErrorResponse
 // @Failure      404  {object}  FollowErrorResponse
 // @Failure      409  {object}  FollowErrorResponse
+// @Failure      429  {object}  FollowErrorResponse
 // @Failure      500  {object}  FollowErrorResponse
+// @Security     ApiKeyAuth
 // @Router       /users/{id}/follow/{target_id} [post]
 func (h *FollowHandler) FollowUser(c *gin.Context) {
-	followerID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, FollowErrorResponse{Error: "invalid follower ID"})
-		return
-	}
+	followerID := currentUserID(c)
 
 	targetID, err := strconv.Atoi(c.Param("target_id"))
 	if err != nil {
@@ -65,12 +85,13 @@ func (h *FollowHandler) FollowUser(c *gin.Context) {
 		return
 	}
 
-	err = h.followService.Follow(followerID, targetID)
+	status, err := h.followService.Follow(c.Request.Context(), followerID, targetID)
 	if err != nil {
 		switch {
-		case errors.As(err, &application.ErrUserNotFound{}):
+		case errors.As(err, new(*application.ErrUserNotFound)):
 			c.JSON(http.StatusNotFound, FollowErrorResponse{Error: err.Error()})
-		case errors.As(err, &application.ErrAlreadyFollowing{}):
+		case errors.As(err, new(*application.ErrAlreadyFollowing)),
+			errors.As(err, new(*application.ErrFollowRequestAlreadyExists)):
 			c.JSON(http.StatusConflict, FollowErrorResponse{Error: err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, FollowErrorResponse{Error: "internal server error"})
@@ -78,30 +99,41 @@ func (h *FollowHandler) FollowUser(c *gin.Context) {
 		return
 	}
 
+	if status == application.FollowStatusPending {
+		c.JSON(http.StatusAccepted, FollowResponse{
+			Message: "follow request sent",
+			Status:  string(status),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, FollowResponse{
 		Message: "successfully followed user",
+		Status:  string(status),
 	})
 }
 
 // UnfollowUser unfollows a user
 // @Summary      Unfollow a user
-// @Description  Unfollow a user by their ID
+// @Description  Unfollow a user by their ID. API keys need the follows:write scope.
 // @Tags         follows
 // @Accept       json
 // @Produce      json
 // @Param        id    path      int  true  "Follower User ID"
 // @Param        target_id  path  int  true  "Target User ID to unfollow"
+// @Param        Idempotency-Key  header  string  false  "Unique key; retries with the same key get the first response back"
 // @Success      200  {object}  FollowResponse
 // @Failure      400  {object}  FollowErrorResponse
+// @Failure      401  {object}  FollowErrorResponse
+// @Failure      403  {object}  FollowErrorResponse
 // @Failure      404  {object}  FollowErrorResponse
+// @Failure      409  {object}  FollowErrorResponse
+// @Failure      429  {object}  FollowErrorResponse
 // @Failure      500  {object}  FollowErrorResponse
+// @Security     ApiKeyAuth
 // @Router       /users/{id}/unfollow/{target_id} [post]
 func (h *FollowHandler) UnfollowUser(c *gin.Context) {
-	followerID, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, FollowErrorResponse{Error: "invalid follower ID"})
-		return
-	}
+	followerID := currentUserID(c)
 
 	targetID, err := strconv.Atoi(c.Param("target_id"))
 	if err != nil {
@@ -109,12 +141,12 @@ func (h *FollowHandler) UnfollowUser(c *gin.Context) {
 		return
 	}
 
-	err = h.followService.Unfollow(followerID, targetID)
+	err = h.followService.Unfollow(c.Request.Context(), followerID, targetID)
 	if err != nil {
 		switch {
-		case errors.As(err, &application.ErrUserNotFound{}):
+		case errors.As(err, new(*application.ErrUserNotFound)):
 			c.JSON(http.StatusNotFound, FollowErrorResponse{Error: err.Error()})
-		case errors.As(err, &application.ErrNotFollowing{}):
+		case errors.As(err, new(*application.ErrNotFollowing)):
 			c.JSON(http.StatusBadRequest, FollowErrorResponse{Error: err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, FollowErrorResponse{Error: "internal server error"})
@@ -126,3 +158,146 @@ func (h *FollowHandler) UnfollowUser(c *gin.Context) {
 		Message: "successfully unfollowed user",
 	})
 }
+
+// ListFollowRequests lists pending follow requests
+// @Summary      List follow requests
+// @Description  Get the pending follow requests received by a protected account
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   FollowRequestResponse
+// @Failure      400  {object}  FollowErrorResponse
+// @Failure      401  {object}  FollowErrorResponse
+// @Failure      403  {object}  FollowErrorResponse
+// @Failure      404  {object}  FollowErrorResponse
+// @Failure      500  {object}  FollowErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/follow-requests [get]
+func (h *FollowHandler) ListFollowRequests(c *gin.Context) {
+	userID := currentUserID(c)
+
+	requests, err := h.followService.ListFollowRequests(c.Request.Context(), userID)
+	if err != nil {
+		if errors.As(err, new(*application.ErrUserNotFound)) {
+			c.JSON(http.StatusNotFound, FollowErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, FollowErrorResponse{Error: "internal server error"})
+		return
+	}
+
+	response := make([]FollowRequestResponse, len(requests))
+	for i, request := range requests {
+		response[i] = FollowRequestResponse{
+			RequesterID: request.RequesterID,
+			TargetID:    request.TargetID,
+			CreatedAt:   request.CreatedAt,
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ApproveFollowRequest approves a pending follow request
+// @Summary      Approve a follow request
+// @Description  Accept a pending follow request; the requester starts following the user. API keys need the follows:write scope.
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id            path  int  true  "User ID"
+// @Param        requester_id  path  int  true  "Requester User ID"
+// @Param        Idempotency-Key  header  string  false  "Unique key; retries with the same key get the first response back"
+// @Success      200  {object}  FollowResponse
+// @Failure      400  {object}  FollowErrorResponse
+// @Failure      401  {object}  FollowErrorResponse
+// @Failure      403  {object}  FollowErrorResponse
+// @Failure      404  {object}  FollowErrorResponse
+// @Failure      409  {object}  FollowErrorResponse
+// @Failure      429  {object}  FollowErrorResponse
+// @Failure      500  {object}  FollowErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/follow-requests/{requester_id}/approve [post]
+func (h *FollowHandler) ApproveFollowRequest(c *gin.Context) {
+	h.resolveFollowRequest(c, h.followService.ApproveFollowRequest, "follow request approved")
+}
+
+// RejectFollowRequest rejects a pending follow request
+// @Summary      Reject a follow request
+// @Description  Discard a pending follow request. API keys need the follows:write scope.
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id            path  int  true  "User ID"
+// @Param        requester_id  path  int  true  "Requester User ID"
+// @Param        Idempotency-Key  header  string  false  "Unique key; retries with the same key get the first response back"
+// @Success      200  {object}  FollowResponse
+// @Failure      400  {object}  FollowErrorResponse
+// @Failure      401  {object}  FollowErrorResponse
+// @Failure      403  {object}  FollowErrorResponse
+// @Failure      404  {object}  FollowErrorResponse
+// @Failure      409  {object}  FollowErrorResponse
+// @Failure      429  {object}  FollowErrorResponse
+// @Failure      500  {object}  FollowErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/follow-requests/{requester_id}/reject [post]
+func (h *FollowHandler) RejectFollowRequest(c *gin.Context) {
+	h.resolveFollowRequest(c, h.followService.RejectFollowRequest, "follow request rejected")
+}
+
+func (h *FollowHandler) resolveFollowRequest(c *gin.Context, resolve func(ctx context.Context, targetID, requesterID int) error, message string) {
+	userID := currentUserID(c)
+
+	requesterID, err := strconv.Atoi(c.Param("requester_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, FollowErrorResponse{Error: "invalid requester ID"})
+		return
+	}
+
+	if err := resolve(c.Request.Context(), userID, requesterID); err != nil {
+		if errors.As(err, new(*application.ErrFollowRequestNotFound)) {
+			c.JSON(http.StatusNotFound, FollowErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, FollowErrorResponse{Error: "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, FollowResponse{Message: message})
+}
+
+// SetProtected protects or unprotects an account
+// @Summary      Protect an account
+// @Description  When protected, new followers must be approved and tweets are only visible to followers. Unprotecting approves all pending requests.
+// @Tags         follows
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int                  true  "User ID"
+// @Param        request  body  SetProtectedRequest  true  "Protection setting"
+// @Success      204
+// @Failure      400  {object}  FollowErrorResponse
+// @Failure      401  {object}  FollowErrorResponse
+// @Failure      403  {object}  FollowErrorResponse
+// @Failure      404  {object}  FollowErrorResponse
+// @Failure      500  {object}  FollowErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/protected [put]
+func (h *FollowHandler) SetProtected(c *gin.Context) {
+	userID := currentUserID(c)
+
+	var req SetProtectedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, FollowErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.followService.SetProtected(c.Request.Context(), userID, *req.Protected); err != nil {
+		if errors.As(err, new(*application.ErrUserNotFound)) {
+			c.JSON(http.StatusNotFound, FollowErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, FollowErrorResponse{Error: "internal server error"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthReportResponse represents the outcome of a probe
+type HealthReportResponse struct {
+	Status     string                             `json:"status" example:"up" enums:"up,down"`
+	Components map[string]ComponentHealthResponse `json:"components"`
+	CheckedAt  time.Time                          `json:"checked_at"`
+}
+
+// ComponentHealthResponse represents the outcome of checking one dependency
+type ComponentHealthResponse struct {
+	Status string `json:"status" example:"up" enums:"up,down"`
+	// Why the component is down
+	Error      string  `json:"error,omitempty" example:"dial tcp 127.0.0.1:6379: connect: connection refused"`
+	DurationMS float64 `json:"duration_ms" example:"1.5"`
+}
+
+type HealthHandler struct {
+	healthService *application.HealthService
+}
+
+func NewHealthHandler(healthService *application.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// Livez reports whether the service works at all
+// @Summary      Liveness probe
+// @Description  Report whether the service works at all, such as its consumers still running. Dependencies are not checked, since restarting the service would not fix them. Reports are cached for a few seconds.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  HealthReportResponse
+// @Failure      503  {object}  HealthReportResponse
+// @Router       /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	writeHealthReport(c, h.healthService.Liveness(c.Request.Context()))
+}
+
+// Readyz reports whether the service can handle requests and messages
+// @Summary      Readiness probe
+// @Description  Report whether PostgreSQL, Redis, the Kafka brokers and, in workers, the consumer groups work, each checked with its own timeout. Reports are cached for a few seconds.
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  HealthReportResponse
+// @Failure      503  {object}  HealthReportResponse
+// @Router       /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	writeHealthReport(c, h.healthService.Readiness(c.Request.Context()))
+}
+
+func writeHealthReport(c *gin.Context, report domain.HealthReport) {
+	resp := HealthReportResponse{
+		Status:     string(report.Status),
+		Components: make(map[string]ComponentHealthResponse, len(report.Components)),
+		CheckedAt:  report.CheckedAt,
+	}
+	for name, component := range report.Components {
+		resp.Components[name] = ComponentHealthResponse{
+			Status:     string(component.Status),
+			Error:      component.Error,
+			DurationMS: float64(component.Duration.Microseconds()) / 1000,
+		}
+	}
+
+	status := http.StatusOK
+	if report.Status != domain.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, resp)
+}
@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListResponse represents a list in the system
+type ListResponse struct {
+	ID          int       `json:"id" example:"1"`
+	OwnerID     int       `json:"owner_id" example:"123"`
+	Name        string    `json:"name" example:"Go devs"`
+	Description string    `json:"description" example:"People writing Go"`
+	Private     bool      `json:"private" example:"false"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ListMembersResponse represents the members of a list
+type ListMembersResponse struct {
+	ListID  int   `json:"list_id" example:"1"`
+	UserIDs []int `json:"user_ids"`
+}
+
+// ListTimelineResponse represents the timeline of a list
+type ListTimelineResponse struct {
+	ListID   int     `json:"list_id" example:"1"`
+	TweetIDs []int64 `json:"tweet_ids"`
+}
+
+// ListErrorResponse represents an error response for list operations
+type ListErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type ListHandler struct {
+	listService *application.ListService
+}
+
+func NewListHandler(listService *application.ListService) *ListHandler {
+	return &ListHandler{listService: listService}
+}
+
+// ListRequest represents the request body for creating or replacing a list
+type ListRequest struct {
+	// Name of the list
+	// required: true
+	// example: Go devs
+	Name string `json:"name" binding:"required,max=25"`
+
+	// Optional description of the list
+	// example: People writing Go
+	Description string `json:"description" binding:"max=100"`
+
+	// Whether only the owner can see the list
+	// example: false
+	Private bool `json:"private"`
+}
+
+// AddListMemberRequest represents the request body for adding a member to a list
+type AddListMemberRequest struct {
+	// ID of the user to add
+	// required: true
+	// example: 456
+	UserID int `json:"user_id" binding:"required"`
+}
+
+// CreateList creates a list
+// @Summary      Create a list
+// @Description  Create a named group of accounts whose tweets form their own timeline
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int          true  "Owner user ID"
+// @Param        list  body      ListRequest  true  "List to create"
+// @Success      201  {object}  ListResponse
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      401  {object}  ListErrorResponse
+// @Failure      403  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/lists [post]
+func (h *ListHandler) CreateList(c *gin.Context) {
+	ownerID := currentUserID(c)
+
+	var req ListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := h.listService.CreateList(c.Request.Context(), ownerID, req.toInput())
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newListResponse(list))
+}
+
+// GetUserLists lists the lists owned by a user
+// @Summary      Get a user's lists
+// @Description  Get the lists owned by a user; private lists are only included for the owner
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int  true   "Owner user ID"
+// @Success      200  {array}   ListResponse
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      401  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      429  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/lists [get]
+func (h *ListHandler) GetUserLists(c *gin.Context) {
+	ownerID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: "invalid user ID"})
+		return
+	}
+
+	viewerID := currentUserID(c)
+
+	lists, err := h.listService.GetUserLists(c.Request.Context(), viewerID, ownerID)
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	response := make([]ListResponse, len(lists))
+	for i, list := range lists {
+		response[i] = newListResponse(list)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateList replaces the details of a list
+// @Summary      Update a list
+// @Description  Replace the name, description and visibility of a list
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int          true  "Owner user ID"
+// @Param        list_id  path      int          true  "List ID"
+// @Param        list     body      ListRequest  true  "New list details"
+// @Success      200  {object}  ListResponse
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      401  {object}  ListErrorResponse
+// @Failure      403  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/lists/{list_id} [put]
+func (h *ListHandler) UpdateList(c *gin.Context) {
+	ownerID, listID, ok := parseOwnerAndListID(c)
+	if !ok {
+		return
+	}
+
+	var req ListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: err.Error()})
+		return
+	}
+
+	list, err := h.listService.UpdateList(c.Request.Context(), ownerID, listID, req.toInput())
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newListResponse(list))
+}
+
+// DeleteList deletes a list
+// @Summary      Delete a list
+// @Description  Delete a list together with its members and timeline
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int  true  "Owner user ID"
+// @Param        list_id  path  int  true  "List ID"
+// @Success      204
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      401  {object}  ListErrorResponse
+// @Failure      403  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/lists/{list_id} [delete]
+func (h *ListHandler) DeleteList(c *gin.Context) {
+	ownerID, listID, ok := parseOwnerAndListID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.listService.DeleteList(c.Request.Context(), ownerID, listID); err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddMember adds a user to a list
+// @Summary      Add a list member
+// @Description  Add a user to a list; their tweets are added to the list timeline. Protected accounts can only be added by followers.
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                   true  "Owner user ID"
+// @Param        list_id  path      int                   true  "List ID"
+// @Param        member   body      AddListMemberRequest  true  "User to add"
+// @Success      204
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      401  {object}  ListErrorResponse
+// @Failure      403  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      409  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/lists/{list_id}/members [post]
+func (h *ListHandler) AddMember(c *gin.Context) {
+	ownerID, listID, ok := parseOwnerAndListID(c)
+	if !ok {
+		return
+	}
+
+	var req AddListMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.listService.AddMember(c.Request.Context(), ownerID, listID, req.UserID); err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RemoveMember removes a user from a list
+// @Summary      Remove a list member
+// @Description  Remove a user from a list; their tweets leave the list timeline
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        id         path  int  true  "Owner user ID"
+// @Param        list_id    path  int  true  "List ID"
+// @Param        member_id  path  int  true  "ID of the member to remove"
+// @Success      204
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      401  {object}  ListErrorResponse
+// @Failure      403  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/lists/{list_id}/members/{member_id} [delete]
+func (h *ListHandler) RemoveMember(c *gin.Context) {
+	ownerID, listID, ok := parseOwnerAndListID(c)
+	if !ok {
+		return
+	}
+
+	memberID, err := strconv.Atoi(c.Param("member_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: "invalid member ID"})
+		return
+	}
+
+	if err := h.listService.RemoveMember(c.Request.Context(), ownerID, listID, memberID); err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetList retrieves a list
+// @Summary      Get a list
+// @Description  Get a list by its ID; private lists are only visible to their owner
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        list_id    path      int  true   "List ID"
+// @Success      200  {object}  ListResponse
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      429  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Router       /lists/{list_id} [get]
+func (h *ListHandler) GetList(c *gin.Context) {
+	listID, viewerID, ok := parseListAndViewerID(c)
+	if !ok {
+		return
+	}
+
+	list, err := h.listService.GetList(c.Request.Context(), viewerID, listID)
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newListResponse(list))
+}
+
+// GetMembers lists the members of a list
+// @Summary      Get list members
+// @Description  Get the IDs of the users in a list
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        list_id    path      int  true   "List ID"
+// @Success      200  {object}  ListMembersResponse
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      429  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Router       /lists/{list_id}/members [get]
+func (h *ListHandler) GetMembers(c *gin.Context) {
+	listID, viewerID, ok := parseListAndViewerID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.listService.GetMembers(c.Request.Context(), viewerID, listID)
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ListMembersResponse{ListID: listID, UserIDs: members})
+}
+
+// GetListTimeline retrieves the timeline of a list
+// @Summary      Get list timeline
+// @Description  Get the most recent tweet IDs from the members of a list
+// @Tags         lists
+// @Accept       json
+// @Produce      json
+// @Param        list_id    path      int  true   "List ID"
+// @Param        limit      query     int  false  "Maximum number of tweets to return (default 10)"
+// @Success      200  {object}  ListTimelineResponse
+// @Failure      400  {object}  ListErrorResponse
+// @Failure      404  {object}  ListErrorResponse
+// @Failure      429  {object}  ListErrorResponse
+// @Failure      500  {object}  ListErrorResponse
+// @Router       /lists/{list_id}/timeline [get]
+func (h *ListHandler) GetListTimeline(c *gin.Context) {
+	listID, viewerID, ok := parseListAndViewerID(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	ids, err := h.listService.GetListTimeline(c.Request.Context(), viewerID, listID, limit)
+	if err != nil {
+		writeListError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, ListTimelineResponse{ListID: listID, TweetIDs: ids})
+}
+
+func (r ListRequest) toInput() application.ListInput {
+	return application.ListInput{
+		Name:        r.Name,
+		Description: r.Description,
+		Private:     r.Private,
+	}
+}
+
+func parseOwnerAndListID(c *gin.Context) (ownerID, listID int, ok bool) {
+	listID, err := strconv.Atoi(c.Param("list_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: "invalid list ID"})
+		return 0, 0, false
+	}
+
+	return currentUserID(c), listID, true
+}
+
+func parseListAndViewerID(c *gin.Context) (listID, viewerID int, ok bool) {
+	listID, err := strconv.Atoi(c.Param("list_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: "invalid list ID"})
+		return 0, 0, false
+	}
+
+	return listID, currentUserID(c), true
+}
+
+func writeListError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, ListErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrProtectedAccount)):
+		c.JSON(http.StatusForbidden, ListErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrUserNotFound)),
+		errors.As(err, new(*application.ErrListNotFound)),
+		errors.As(err, new(*application.ErrNotListMember)):
+		c.JSON(http.StatusNotFound, ListErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrAlreadyListMember)):
+		c.JSON(http.StatusConflict, ListErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ListErrorResponse{Error: "internal server error"})
+	}
+}
+
+func newListResponse(list *domain.List) ListResponse {
+	return ListResponse{
+		ID:          list.ID,
+		OwnerID:     list.OwnerID,
+		Name:        list.Name,
+		Description: list.Description,
+		Private:     list.Private,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultReportLimit = 20
+	maxReportLimit     = 100
+)
+
+// ReportTweetRequest represents the request body for reporting a tweet
+type ReportTweetRequest struct {
+	// Why the tweet should be reviewed (max 280 characters)
+	Reason string `json:"reason" binding:"max=280" example:"spam"`
+}
+
+// ResolveReportRequest represents the request body for resolving a report
+type ResolveReportRequest struct {
+	// What to do with the report: dismiss it or remove the reported tweet
+	Action string `json:"action" binding:"required,oneof=dismiss remove_tweet" example:"remove_tweet"`
+}
+
+// ReportResponse represents a report of a tweet
+type ReportResponse struct {
+	ID         int64      `json:"id" example:"1"`
+	TweetID    int64      `json:"tweet_id" example:"123"`
+	ReporterID int        `json:"reporter_id" example:"456"`
+	Reason     string     `json:"reason" example:"spam"`
+	Status     string     `json:"status" example:"open"`
+	ResolvedBy *int       `json:"resolved_by,omitempty" example:"789"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ModerationErrorResponse represents an error response for moderation operations
+type ModerationErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type ModerationHandler struct {
+	moderationService *application.ModerationService
+}
+
+func NewModerationHandler(moderationService *application.ModerationService) *ModerationHandler {
+	return &ModerationHandler{moderationService: moderationService}
+}
+
+// ReportTweet flags a tweet for review
+// @Summary      Report a tweet
+// @Description  Flag a tweet for review by moderators. Each user can report a tweet once, and not their own. Not available to API keys.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                 true  "Tweet ID"
+// @Param        report  body      ReportTweetRequest  true  "Report"
+// @Success      201  {object}  ReportResponse
+// @Failure      400  {object}  ModerationErrorResponse
+// @Failure      401  {object}  ModerationErrorResponse
+// @Failure      403  {object}  ModerationErrorResponse
+// @Failure      404  {object}  ModerationErrorResponse
+// @Failure      409  {object}  ModerationErrorResponse
+// @Failure      500  {object}  ModerationErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /tweets/{id}/reports [post]
+func (h *ModerationHandler) ReportTweet(c *gin.Context) {
+	tweetID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ModerationErrorResponse{Error: "invalid tweet id"})
+		return
+	}
+
+	var req ReportTweetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ModerationErrorResponse{Error: err.Error()})
+		return
+	}
+
+	report, err := h.moderationService.ReportTweet(c.Request.Context(), currentUserID(c), tweetID, req.Reason)
+	if err != nil {
+		writeModerationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newReportResponse(report))
+}
+
+// ListReports returns the reports waiting for review
+// @Summary      List open reports
+// @Description  List the open reports, oldest first. Requires the moderator or admin role.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        limit  query     int  false  "Maximum number of reports to return (default 20, max 100)"
+// @Success      200  {array}   ReportResponse
+// @Failure      401  {object}  ModerationErrorResponse
+// @Failure      403  {object}  ModerationErrorResponse
+// @Failure      500  {object}  ModerationErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /moderation/reports [get]
+func (h *ModerationHandler) ListReports(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultReportLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultReportLimit
+	}
+	limit = min(limit, maxReportLimit)
+
+	reports, err := h.moderationService.ListOpenReports(c.Request.Context(), currentUserID(c), limit)
+	if err != nil {
+		writeModerationError(c, err)
+		return
+	}
+
+	response := make([]ReportResponse, len(reports))
+	for i, report := range reports {
+		response[i] = newReportResponse(report)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ResolveReport closes an open report
+// @Summary      Resolve a report
+// @Description  Dismiss a report, or remove the reported tweet which closes every open report against it. Requires the moderator or admin role.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        report_id  path  int                   true  "Report ID"
+// @Param        action     body  ResolveReportRequest  true  "Resolution"
+// @Success      204
+// @Failure      400  {object}  ModerationErrorResponse
+// @Failure      401  {object}  ModerationErrorResponse
+// @Failure      403  {object}  ModerationErrorResponse
+// @Failure      404  {object}  ModerationErrorResponse
+// @Failure      500  {object}  ModerationErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /moderation/reports/{report_id}/resolve [post]
+func (h *ModerationHandler) ResolveReport(c *gin.Context) {
+	reportID, err := strconv.ParseInt(c.Param("report_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ModerationErrorResponse{Error: "invalid report id"})
+		return
+	}
+
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ModerationErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.moderationService.ResolveReport(c.Request.Context(), currentUserID(c), reportID, req.Action); err != nil {
+		writeModerationError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func newReportResponse(report *domain.Report) ReportResponse {
+	return ReportResponse{
+		ID:         report.ID,
+		TweetID:    report.TweetID,
+		ReporterID: report.ReporterID,
+		Reason:     report.Reason,
+		Status:     string(report.Status),
+		ResolvedBy: report.ResolvedBy,
+		ResolvedAt: report.ResolvedAt,
+		CreatedAt:  report.CreatedAt,
+	}
+}
+
+func writeModerationError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, ModerationErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrForbidden)), errors.As(err, new(*application.ErrProtectedAccount)):
+		c.JSON(http.StatusForbidden, ModerationErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrTweetNotFound)), errors.As(err, new(*application.ErrReportNotFound)),
+		errors.As(err, new(*application.ErrUserNotFound)):
+		c.JSON(http.StatusNotFound, ModerationErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrAlreadyReported)):
+		c.JSON(http.StatusConflict, ModerationErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, ModerationErrorResponse{Error: "internal server error"})
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MuteResponse represents a mute in the system
+type MuteResponse struct {
+	ID          int64      `json:"id" example:"1"`
+	UserID      int        `json:"user_id" example:"123"`
+	Type        string     `json:"type" example:"keyword"`
+	MutedUserID int        `json:"muted_user_id,omitempty" example:"456"`
+	Keyword     string     `json:"keyword,omitempty" example:"#spoilers"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// MuteErrorResponse represents an error response for mute operations
+type MuteErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type MuteHandler struct {
+	muteService *application.MuteService
+}
+
+func NewMuteHandler(muteService *application.MuteService) *MuteHandler {
+	return &MuteHandler{muteService: muteService}
+}
+
+// CreateMuteRequest represents the request body for muting a user or keyword
+type CreateMuteRequest struct {
+	// Type of mute: "user" or "keyword"
+	// required: true
+	// example: keyword
+	Type string `json:"type" binding:"required,oneof=user keyword"`
+
+	// ID of the user to mute (required when type is "user")
+	// example: 456
+	MutedUserID int `json:"muted_user_id"`
+
+	// Keyword or hashtag to mute (required when type is "keyword")
+	// example: #spoilers
+	Keyword string `json:"keyword" binding:"max=100"`
+
+	// Optional time at which the mute stops applying
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateMuteRequest represents the request body for changing a mute's expiry
+type UpdateMuteRequest struct {
+	// New expiry, or null to mute indefinitely
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreateMute mutes a user or keyword
+// @Summary      Mute a user or keyword
+// @Description  Hide tweets from a user, or containing a keyword or hashtag, from the timeline without unfollowing
+// @Tags         mutes
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                true  "User ID"
+// @Param        mute  body      CreateMuteRequest  true  "Mute to create"
+// @Success      201  {object}  MuteResponse
+// @Failure      400  {object}  MuteErrorResponse
+// @Failure      401  {object}  MuteErrorResponse
+// @Failure      403  {object}  MuteErrorResponse
+// @Failure      404  {object}  MuteErrorResponse
+// @Failure      409  {object}  MuteErrorResponse
+// @Failure      500  {object}  MuteErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/mutes [post]
+func (h *MuteHandler) CreateMute(c *gin.Context) {
+	userID := currentUserID(c)
+
+	var req CreateMuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, MuteErrorResponse{Error: err.Error()})
+		return
+	}
+
+	mute, err := h.muteService.Mute(c.Request.Context(), userID, application.MuteInput{
+		Type:        domain.MuteType(req.Type),
+		MutedUserID: req.MutedUserID,
+		Keyword:     req.Keyword,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		writeMuteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newMuteResponse(mute))
+}
+
+// ListMutes lists a user's active mutes
+// @Summary      List mutes
+// @Description  Get the mutes of a user that are currently in effect
+// @Tags         mutes
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   MuteResponse
+// @Failure      400  {object}  MuteErrorResponse
+// @Failure      401  {object}  MuteErrorResponse
+// @Failure      403  {object}  MuteErrorResponse
+// @Failure      404  {object}  MuteErrorResponse
+// @Failure      500  {object}  MuteErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/mutes [get]
+func (h *MuteHandler) ListMutes(c *gin.Context) {
+	userID := currentUserID(c)
+
+	mutes, err := h.muteService.ListMutes(c.Request.Context(), userID)
+	if err != nil {
+		writeMuteError(c, err)
+		return
+	}
+
+	response := make([]MuteResponse, len(mutes))
+	for i, mute := range mutes {
+		response[i] = newMuteResponse(mute)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateMute changes when a mute expires
+// @Summary      Update a mute
+// @Description  Change or remove the expiry of a mute
+// @Tags         mutes
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                true  "User ID"
+// @Param        mute_id  path      int                true  "Mute ID"
+// @Param        mute     body      UpdateMuteRequest  true  "New expiry"
+// @Success      204
+// @Failure      400  {object}  MuteErrorResponse
+// @Failure      401  {object}  MuteErrorResponse
+// @Failure      403  {object}  MuteErrorResponse
+// @Failure      404  {object}  MuteErrorResponse
+// @Failure      500  {object}  MuteErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/mutes/{mute_id} [patch]
+func (h *MuteHandler) UpdateMute(c *gin.Context) {
+	userID := currentUserID(c)
+
+	muteID, err := strconv.ParseInt(c.Param("mute_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, MuteErrorResponse{Error: "invalid mute ID"})
+		return
+	}
+
+	var req UpdateMuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, MuteErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.muteService.UpdateExpiry(c.Request.Context(), userID, muteID, req.ExpiresAt); err != nil {
+		writeMuteError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteMute removes a mute
+// @Summary      Unmute
+// @Description  Remove a mute; hidden tweets reappear in the timeline immediately
+// @Tags         mutes
+// @Accept       json
+// @Produce      json
+// @Param        id       path  int  true  "User ID"
+// @Param        mute_id  path  int  true  "Mute ID"
+// @Success      204
+// @Failure      400  {object}  MuteErrorResponse
+// @Failure      401  {object}  MuteErrorResponse
+// @Failure      403  {object}  MuteErrorResponse
+// @Failure      404  {object}  MuteErrorResponse
+// @Failure      500  {object}  MuteErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/mutes/{mute_id} [delete]
+func (h *MuteHandler) DeleteMute(c *gin.Context) {
+	userID := currentUserID(c)
+
+	muteID, err := strconv.ParseInt(c.Param("mute_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, MuteErrorResponse{Error: "invalid mute ID"})
+		return
+	}
+
+	if err := h.muteService.Unmute(c.Request.Context(), userID, muteID); err != nil {
+		writeMuteError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func writeMuteError(c *gin.Context, err error) {
+	switch {
+	case errors.As(err, new(*application.ErrInvalidInput)):
+		c.JSON(http.StatusBadRequest, MuteErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrUserNotFound)), errors.As(err, new(*application.ErrMuteNotFound)):
+		c.JSON(http.StatusNotFound, MuteErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrAlreadyMuted)):
+		c.JSON(http.StatusConflict, MuteErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, MuteErrorResponse{Error: "internal server error"})
+	}
+}
+
+func newMuteResponse(mute *domain.Mute) MuteResponse {
+	return MuteResponse{
+		ID:          mute.ID,
+		UserID:      mute.UserID,
+		Type:        string(mute.Type),
+		MutedUserID: mute.MutedUserID,
+		Keyword:     mute.Keyword,
+		ExpiresAt:   mute.ExpiresAt,
+		CreatedAt:   mute.CreatedAt,
+	}
+}
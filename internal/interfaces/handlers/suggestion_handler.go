@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"uala-tweets/internal/application"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+)
+
+// SuggestionResponse represents an account recommended to a user
+type SuggestionResponse struct {
+	UserID int `json:"user_id" example:"456"`
+	// Number of followed accounts that also follow this account
+	MutualFollows int `json:"mutual_follows" example:"3"`
+}
+
+// SuggestionErrorResponse represents an error response for suggestion operations
+type SuggestionErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+type SuggestionHandler struct {
+	suggestionService *application.SuggestionService
+}
+
+func NewSuggestionHandler(suggestionService *application.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{suggestionService: suggestionService}
+}
+
+// GetSuggestions returns who-to-follow recommendations
+// @Summary      Get follow suggestions
+// @Description  Recommend accounts followed by the accounts the user follows, ranked by how many of them do
+// @Tags         suggestions
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int  true   "User ID"
+// @Param        limit  query     int  false  "Maximum number of suggestions to return (default 10, max 50)"
+// @Success      200  {array}   SuggestionResponse
+// @Failure      400  {object}  SuggestionErrorResponse
+// @Failure      401  {object}  SuggestionErrorResponse
+// @Failure      403  {object}  SuggestionErrorResponse
+// @Failure      404  {object}  SuggestionErrorResponse
+// @Failure      500  {object}  SuggestionErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /users/{id}/suggestions [get]
+func (h *SuggestionHandler) GetSuggestions(c *gin.Context) {
+	userID := currentUserID(c)
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSuggestionLimit)))
+	if err != nil || limit <= 0 {
+		limit = defaultSuggestionLimit
+	}
+	limit = min(limit, maxSuggestionLimit)
+
+	suggestions, err := h.suggestionService.GetSuggestions(c.Request.Context(), userID, limit)
+	if err != nil {
+		if errors.As(err, new(*application.ErrUserNotFound)) {
+			c.JSON(http.StatusNotFound, SuggestionErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, SuggestionErrorResponse{Error: "internal server error"})
+		return
+	}
+
+	response := make([]SuggestionResponse, len(suggestions))
+	for i, s := range suggestions {
+		response[i] = SuggestionResponse{UserID: s.UserID, MutualFollows: s.Score}
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -32,7 +32,7 @@ func NewTimelineHandler(service *application.TimelineService) *TimelineHandler {
 
 // GetTimelineHandler retrieves a user's timeline
 // @Summary      Get user timeline
-// @Description  Get a paginated list of tweet IDs from users that the specified user follows
+// @Description  Get a paginated list of tweet IDs from users that the specified user follows. API keys need the timeline:read scope.
 // @Tags         timeline
 // @Accept       json
 // @Produce      json
@@ -40,21 +40,20 @@ func NewTimelineHandler(service *application.TimelineService) *TimelineHandler {
 // @Param        limit query int false "Maximum number of tweets to return (default 10)"
 // @Success      200  {object}  TimelineResponse
 // @Failure      400  {object}  TimelineErrorResponse
+// @Failure      401  {object}  TimelineErrorResponse
+// @Failure      403  {object}  TimelineErrorResponse
+// @Failure      429  {object}  TimelineErrorResponse
 // @Failure      500  {object}  TimelineErrorResponse
+// @Security     ApiKeyAuth
 // @Router       /timeline/{user_id} [get]
 func (h *TimelineHandler) GetTimelineHandler(c *gin.Context) {
-	userIDStr := c.Param("user_id")
-	userID, err := strconv.Atoi(userIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, TimelineErrorResponse{Error: "invalid user_id"})
-		return
-	}
+	userID := currentUserID(c)
 	limitStr := c.DefaultQuery("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 {
 		limit = 10
 	}
-	ids, err := h.service.GetTimeline(userID, limit)
+	ids, err := h.service.GetTimeline(c.Request.Context(), userID, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, TimelineErrorResponse{Error: err.Error()})
 		return
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"uala-tweets/internal/application"
+	"uala-tweets/internal/interfaces/middleware"
 
 	"github.com/gin-gonic/gin"
 )
@@ -35,11 +37,6 @@ func NewTweetHandler(tweetService *application.TweetService) *TweetHandler {
 // CreateTweetRequest represents the request body for creating a tweet
 // swagger:parameters createTweet
 type CreateTweetRequest struct {
-	// ID of the user creating the tweet
-	// required: true
-	// example: 123
-	UserID  int64  `json:"user_id" binding:"required"`
-	
 	// Content of the tweet (max 280 characters)
 	// required: true
 	// example: Hello, this is my first tweet!
@@ -49,14 +46,20 @@ type CreateTweetRequest struct {
 
 // CreateTweet creates a new tweet
 // @Summary      Create a new tweet
-// @Description  Create a new tweet with the specified content
+// @Description  Create a new tweet with the specified content. API keys need the tweets:write scope.
 // @Tags         tweets
 // @Accept       json
 // @Produce      json
 // @Param        tweet  body      CreateTweetRequest  true  "Tweet to create"
+// @Param        Idempotency-Key  header  string  false  "Unique key; retries with the same key get the first response back"
 // @Success      201  {object}  TweetResponse
 // @Failure      400  {object}  TweetErrorResponse
+// @Failure      401  {object}  TweetErrorResponse
+// @Failure      403  {object}  TweetErrorResponse
+// @Failure      409  {object}  TweetErrorResponse
+// @Failure      429  {object}  TweetErrorResponse
 // @Failure      500  {object}  TweetErrorResponse
+// @Security     ApiKeyAuth
 // @Router       /tweets [post]
 func (h *TweetHandler) CreateTweet(c *gin.Context) {
 	var req CreateTweetRequest
@@ -66,7 +69,7 @@ func (h *TweetHandler) CreateTweet(c *gin.Context) {
 	}
 
 	tweet, err := h.tweetService.CreateTweet(c.Request.Context(), application.CreateTweetInput{
-		UserID:  req.UserID,
+		UserID:  int64(currentUserID(c)),
 		Content: req.Content,
 	})
 	if err != nil {
@@ -90,10 +93,12 @@ func (h *TweetHandler) CreateTweet(c *gin.Context) {
 // @Tags         tweets
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "Tweet ID"
+// @Param        id         path      int  true   "Tweet ID"
 // @Success      200  {object}  TweetResponse
 // @Failure      400  {object}  TweetErrorResponse
+// @Failure      403  {object}  TweetErrorResponse
 // @Failure      404  {object}  TweetErrorResponse
+// @Failure      429  {object}  TweetErrorResponse
 // @Router       /tweets/{id} [get]
 func (h *TweetHandler) GetTweet(c *gin.Context) {
 	idStr := c.Param("id")
@@ -103,8 +108,14 @@ func (h *TweetHandler) GetTweet(c *gin.Context) {
 		return
 	}
 
-	tweet, err := h.tweetService.GetTweet(c.Request.Context(), id)
+	viewerID := currentUserID(c)
+
+	tweet, err := h.tweetService.GetTweet(c.Request.Context(), viewerID, id)
 	if err != nil {
+		if errors.As(err, new(*application.ErrProtectedAccount)) {
+			c.JSON(http.StatusForbidden, TweetErrorResponse{Error: err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, TweetErrorResponse{Error: "tweet not found"})
 		return
 	}
@@ -119,28 +130,121 @@ func (h *TweetHandler) GetTweet(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// UpdateTweetRequest represents the request body for editing a tweet
+type UpdateTweetRequest struct {
+	// New content of the tweet (max 280 characters)
+	Content string `json:"content" binding:"required,max=280" example:"Hello, world! (edited)"`
+}
+
+// EditTweet changes the content of a tweet
+// @Summary      Edit a tweet
+// @Description  Replace the content of a tweet. Only its author can edit it. API keys need the tweets:write scope.
+// @Tags         tweets
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                 true  "Tweet ID"
+// @Param        tweet  body      UpdateTweetRequest  true  "New content"
+// @Success      200  {object}  TweetResponse
+// @Failure      400  {object}  TweetErrorResponse
+// @Failure      401  {object}  TweetErrorResponse
+// @Failure      403  {object}  TweetErrorResponse
+// @Failure      404  {object}  TweetErrorResponse
+// @Failure      500  {object}  TweetErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /tweets/{id} [patch]
+func (h *TweetHandler) EditTweet(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TweetErrorResponse{Error: "invalid tweet id"})
+		return
+	}
+
+	var req UpdateTweetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, TweetErrorResponse{Error: err.Error()})
+		return
+	}
+
+	tweet, err := h.tweetService.EditTweet(c.Request.Context(), currentUserID(c), id, req.Content)
+	if err != nil {
+		writeTweetError(c, err)
+		return
+	}
+
+	response := TweetResponse{
+		ID:        tweet.ID,
+		UserID:    tweet.UserID,
+		Content:   tweet.Content,
+		CreatedAt: tweet.CreatedAt,
+		UpdatedAt: tweet.UpdatedAt,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTweet deletes a tweet
+// @Summary      Delete a tweet
+// @Description  Delete a tweet and remove it from timelines. Only its author can delete it. API keys need the tweets:write scope.
+// @Tags         tweets
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "Tweet ID"
+// @Success      204
+// @Failure      400  {object}  TweetErrorResponse
+// @Failure      401  {object}  TweetErrorResponse
+// @Failure      403  {object}  TweetErrorResponse
+// @Failure      404  {object}  TweetErrorResponse
+// @Failure      500  {object}  TweetErrorResponse
+// @Security     ApiKeyAuth
+// @Router       /tweets/{id} [delete]
+func (h *TweetHandler) DeleteTweet(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, TweetErrorResponse{Error: "invalid tweet id"})
+		return
+	}
+
+	if err := h.tweetService.DeleteTweet(c.Request.Context(), currentUserID(c), id); err != nil {
+		writeTweetError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetUserTweets retrieves all tweets for a specific user
 // @Summary      Get user tweets
 // @Description  Get all tweets for a specific user
 // @Tags         tweets
 // @Accept       json
 // @Produce      json
-// @Param        user_id   path      int  true  "User ID"
+// @Param        id         path      int  true   "User ID"
 // @Success      200  {array}   TweetResponse
 // @Failure      400  {object}  TweetErrorResponse
+// @Failure      403  {object}  TweetErrorResponse
+// @Failure      404  {object}  TweetErrorResponse
+// @Failure      429  {object}  TweetErrorResponse
 // @Failure      500  {object}  TweetErrorResponse
-// @Router       /users/{user_id}/tweets [get]
+// @Router       /users/{id}/tweets [get]
 func (h *TweetHandler) GetUserTweets(c *gin.Context) {
-	userIDStr := c.Param("user_id")
+	userIDStr := c.Param("id")
 	userID, err := strconv.ParseInt(userIDStr, 10, 64)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, TweetErrorResponse{Error: "invalid user id"})
 		return
 	}
 
-	tweets, err := h.tweetService.GetUserTweets(c.Request.Context(), userID)
+	viewerID := currentUserID(c)
+
+	tweets, err := h.tweetService.GetUserTweets(c.Request.Context(), viewerID, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, TweetErrorResponse{Error: err.Error()})
+		switch {
+		case errors.As(err, new(*application.ErrProtectedAccount)):
+			c.JSON(http.StatusForbidden, TweetErrorResponse{Error: err.Error()})
+		case errors.As(err, new(*application.ErrUserNotFound)):
+			c.JSON(http.StatusNotFound, TweetErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, TweetErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
@@ -156,3 +260,22 @@ func (h *TweetHandler) GetUserTweets(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, response)
 }
+
+func writeTweetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, application.ErrTweetContentEmpty), errors.Is(err, application.ErrTweetContentTooLong):
+		c.JSON(http.StatusBadRequest, TweetErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrForbidden)):
+		c.JSON(http.StatusForbidden, TweetErrorResponse{Error: err.Error()})
+	case errors.As(err, new(*application.ErrTweetNotFound)):
+		c.JSON(http.StatusNotFound, TweetErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, TweetErrorResponse{Error: "internal server error"})
+	}
+}
+
+// currentUserID returns the authenticated user; 0 means anonymous
+func currentUserID(c *gin.Context) int {
+	userID, _ := middleware.UserID(c)
+	return userID
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,6 +18,20 @@ type UserResponse struct {
 	Username string `json:"username" example:"johndoe"`
 }
 
+// UserProfileResponse represents a user together with their profile
+type UserProfileResponse struct {
+	ID          int       `json:"id" example:"123"`
+	Username    string    `json:"username" example:"johndoe"`
+	DisplayName string    `json:"display_name" example:"John Doe"`
+	Bio         string    `json:"bio" example:"Writing Go at Uala"`
+	Location    string    `json:"location" example:"Buenos Aires"`
+	Website     string    `json:"website" example:"https://johndoe.dev"`
+	AvatarURL   string    `json:"avatar_url" example:"https://cdn.example.com/avatars/123.png"`
+	Protected   bool      `json:"protected" example:"false"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
 // UserErrorResponse represents an error response for user operations
 type UserErrorResponse struct {
 	Error string `json:"error" example:"error message"`
@@ -31,75 +47,129 @@ func NewUserHandler(userService *application.UserService) *UserHandler {
 	}
 }
 
-// CreateUserRequest represents the request body for creating a user
-// swagger:parameters createUser
-type CreateUserRequest struct {
-	// Username must be unique and between 3-50 characters
-	// required: true
-	// example: johndoe
-	Username string `json:"username" binding:"required,min=3,max=50"`
+// GetUser retrieves a user by ID
+// @Summary      Get a user
+// @Description  Get a user by ID
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  UserResponse
+// @Failure      400  {object}  UserErrorResponse
+// @Failure      404  {object}  UserErrorResponse
+// @Failure      429  {object}  UserErrorResponse
+// @Failure      500  {object}  UserErrorResponse
+// @Router       /users/{id} [get]
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, UserErrorResponse{Error: "invalid user ID"})
+		return
+	}
+
+	user, err := h.userService.GetUser(c.Request.Context(), id)
+	if err != nil {
+		if errors.As(err, new(*application.ErrUserNotFound)) {
+			c.JSON(http.StatusNotFound, UserErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, UserErrorResponse{Error: "failed to get user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
 }
 
-// CreateUser creates a new user
-// @Summary      Create a new user
-// @Description  Create a new user with the specified username
+// UpdateUserRequest represents the request body for updating a user's profile.
+// Omitted fields are left unchanged; empty strings clear a field.
+type UpdateUserRequest struct {
+	// New username; the old one keeps resolving to this user
+	// example: john_doe
+	Username *string `json:"username" binding:"omitempty,min=3,max=50"`
+
+	// example: John Doe
+	DisplayName *string `json:"display_name" binding:"omitempty,max=50"`
+
+	// example: Writing Go at Uala
+	Bio *string `json:"bio" binding:"omitempty,max=160"`
+
+	// example: Buenos Aires
+	Location *string `json:"location" binding:"omitempty,max=30"`
+
+	// example: https://johndoe.dev
+	Website *string `json:"website" binding:"omitempty,max=100"`
+
+	// example: https://cdn.example.com/avatars/123.png
+	AvatarURL *string `json:"avatar_url" binding:"omitempty,max=255"`
+}
+
+// UpdateUser updates a user's profile
+// @Summary      Update a user
+// @Description  Update the username, display name, bio, location, website or avatar URL of a user
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Param        user  body      CreateUserRequest  true  "User to create"
-// @Success      201  {object}  UserResponse
+// @Param        id    path      int                true  "User ID"
+// @Param        user  body      UpdateUserRequest  true  "Profile fields to change"
+// @Success      200  {object}  UserProfileResponse
 // @Failure      400  {object}  UserErrorResponse
+// @Failure      401  {object}  UserErrorResponse
+// @Failure      403  {object}  UserErrorResponse
+// @Failure      404  {object}  UserErrorResponse
 // @Failure      409  {object}  UserErrorResponse
 // @Failure      500  {object}  UserErrorResponse
-// @Router       /users [post]
-func (h *UserHandler) CreateUser(c *gin.Context) {
-	var req CreateUserRequest
+// @Security     ApiKeyAuth
+// @Router       /users/{id} [patch]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id := currentUserID(c)
+
+	var req UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, UserErrorResponse{Error: err.Error()})
 		return
 	}
 
-	user, err := h.userService.CreateUser(application.CreateUserInput{
-		Username: req.Username,
+	user, err := h.userService.UpdateProfile(c.Request.Context(), id, application.UpdateProfileInput{
+		Username:    req.Username,
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		Location:    req.Location,
+		Website:     req.Website,
+		AvatarURL:   req.AvatarURL,
 	})
-
 	if err != nil {
-		if errors.As(err, &application.ErrUserAlreadyExists{}) {
+		switch {
+		case errors.As(err, new(*application.ErrInvalidInput)):
+			c.JSON(http.StatusBadRequest, UserErrorResponse{Error: err.Error()})
+		case errors.As(err, new(*application.ErrUserNotFound)):
+			c.JSON(http.StatusNotFound, UserErrorResponse{Error: err.Error()})
+		case errors.As(err, new(*application.ErrUserAlreadyExists)):
 			c.JSON(http.StatusConflict, UserErrorResponse{Error: err.Error()})
-			return
+		default:
+			c.JSON(http.StatusInternalServerError, UserErrorResponse{Error: "failed to update user"})
 		}
-		c.JSON(http.StatusInternalServerError, UserErrorResponse{Error: "failed to create user"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, UserResponse{
-		ID:       int64(user.ID),
-		Username: user.Username,
-	})
+	c.JSON(http.StatusOK, newUserProfileResponse(user))
 }
 
-// GetUser retrieves a user by ID
-// @Summary      Get a user
-// @Description  Get a user by ID
+// GetUserByUsername retrieves a user by username
+// @Summary      Get a user by username
+// @Description  Get a user by their current or a previous username, with or without the leading @
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Param        id   path      int  true  "User ID"
-// @Success      200  {object}  UserResponse
-// @Failure      400  {object}  UserErrorResponse
+// @Param        username  path      string  true  "Username"
+// @Success      200  {object}  UserProfileResponse
 // @Failure      404  {object}  UserErrorResponse
+// @Failure      429  {object}  UserErrorResponse
 // @Failure      500  {object}  UserErrorResponse
-// @Router       /users/{id} [get]
-func (h *UserHandler) GetUser(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+// @Router       /users/by-username/{username} [get]
+func (h *UserHandler) GetUserByUsername(c *gin.Context) {
+	user, err := h.userService.GetUserByUsername(c.Request.Context(), c.Param("username"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, UserErrorResponse{Error: "invalid user ID"})
-		return
-	}
-
-	user, err := h.userService.GetUser(id)
-	if err != nil {
-		if errors.As(err, &application.ErrUserNotFound{}) {
+		if errors.As(err, new(*application.ErrUsernameNotFound)) {
 			c.JSON(http.StatusNotFound, UserErrorResponse{Error: err.Error()})
 			return
 		}
@@ -107,5 +177,20 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, newUserProfileResponse(user))
+}
+
+func newUserProfileResponse(user *domain.User) UserProfileResponse {
+	return UserProfileResponse{
+		ID:          user.ID,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Bio:         user.Bio,
+		Location:    user.Location,
+		Website:     user.Website,
+		AvatarURL:   user.AvatarURL,
+		Protected:   user.Protected,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+	}
 }
@@ -0,0 +1,44 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/interfaces/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserHandler_GetUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deactivatedAt := time.Now()
+	userRepo := &application.MockUserRepository{}
+	userRepo.On("GetByID", 1).Return(&domain.User{ID: 1, Username: "alice"}, nil)
+	userRepo.On("GetByID", 2).Return(&domain.User{ID: 2, Username: "bob", DeactivatedAt: &deactivatedAt}, nil)
+	userRepo.On("GetByID", 3).Return((*domain.User)(nil), nil)
+
+	router := gin.New()
+	router.GET("/users/:id", handlers.NewUserHandler(application.NewUserService(userRepo)).GetUser)
+
+	for _, tc := range []struct {
+		name string
+		id   string
+		want int
+	}{
+		{"active user", "1", http.StatusOK},
+		{"deactivated user", "2", http.StatusNotFound},
+		{"missing user", "3", http.StatusNotFound},
+		{"invalid ID", "alice", http.StatusBadRequest},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+tc.id, nil))
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
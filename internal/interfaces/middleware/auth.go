@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const principalKey = "auth.principal"
+
+// ErrorResponse is the body of requests rejected by a middleware
+type ErrorResponse struct {
+	Error string `json:"error" example:"error message"`
+}
+
+// Principal is the caller of an authenticated request, either a user logged
+// in with their password or an API key acting for its user.
+type Principal struct {
+	UserID   int
+	APIKeyID int64
+	// Scopes limits what an API key may do; it is unused for users
+	Scopes []domain.APIKeyScope
+	// Deactivated users may read but only change their account to
+	// reactivate it
+	Deactivated bool
+}
+
+func (p Principal) IsAPIKey() bool {
+	return p.APIKeyID != 0
+}
+
+// Allows reports whether the principal may act within scope. Users logged
+// in with their password may do anything their account can.
+func (p Principal) Allows(scope domain.APIKeyScope) bool {
+	return !p.IsAPIKey() || slices.Contains(p.Scopes, scope)
+}
+
+// Authenticator resolves the credential sent in the Authorization header to
+// the caller.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (Principal, error)
+}
+
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*domain.User, error)
+}
+
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, secret string) (*domain.APIKey, error)
+}
+
+// CredentialAuthenticator accepts both access tokens and API keys, telling
+// them apart by the API key prefix.
+type CredentialAuthenticator struct {
+	tokens  TokenAuthenticator
+	apiKeys APIKeyAuthenticator
+}
+
+func NewCredentialAuthenticator(tokens TokenAuthenticator, apiKeys APIKeyAuthenticator) *CredentialAuthenticator {
+	return &CredentialAuthenticator{tokens: tokens, apiKeys: apiKeys}
+}
+
+func (a *CredentialAuthenticator) Authenticate(ctx context.Context, credential string) (Principal, error) {
+	if strings.HasPrefix(credential, domain.APIKeyPrefix) {
+		key, err := a.apiKeys.AuthenticateAPIKey(ctx, credential)
+		if err != nil {
+			return Principal{}, err
+		}
+		return Principal{UserID: key.UserID, APIKeyID: key.ID, Scopes: key.Scopes}, nil
+	}
+
+	user, err := a.tokens.Authenticate(ctx, credential)
+	if err != nil {
+		return Principal{}, err
+	}
+	return Principal{UserID: user.ID, Deactivated: user.DeactivatedAt != nil}, nil
+}
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer"
+// access token or API key and stores the caller for handlers. Deactivated
+// users may only read, except on the allowDeactivated routes, matched by
+// their path pattern.
+func RequireAuth(authenticator Authenticator, allowDeactivated ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			token, ok := bearerToken(c)
+			if !ok {
+				abortUnauthorized(c, "missing bearer token")
+				return
+			}
+			if principal, ok = authenticate(c, authenticator, token); !ok {
+				return
+			}
+		}
+
+		if principal.Deactivated && !isRead(c.Request.Method) && !slices.Contains(allowDeactivated, c.FullPath()) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "account is deactivated"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// OptionalAuth authenticates the request when it carries a token and lets
+// anonymous requests through. An invalid token is still rejected rather
+// than silently treated as anonymous.
+func OptionalAuth(authenticator Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentPrincipal(c); ok || c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c)
+		if !ok {
+			abortUnauthorized(c, "malformed authorization header")
+			return
+		}
+		if _, ok := authenticate(c, authenticator, token); ok {
+			c.Next()
+		}
+	}
+}
+
+// RequireSelf only lets the authenticated user act on the account named by
+// the path parameter. It must run after RequireAuth.
+func RequireSelf(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok := UserID(c)
+		if !ok {
+			abortUnauthorized(c, "authentication required")
+			return
+		}
+
+		if c.Param(param) != strconv.Itoa(userID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "cannot act on behalf of another user"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope only lets API keys through when they carry every one of
+// scopes; with no scopes the route is closed to API keys. Users logged in
+// with their password always pass. It must run after RequireAuth.
+func RequireScope(scopes ...domain.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			abortUnauthorized(c, "authentication required")
+			return
+		}
+
+		if principal.IsAPIKey() {
+			if len(scopes) == 0 {
+				c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: "API keys cannot access this endpoint"})
+				return
+			}
+			for _, scope := range scopes {
+				if !principal.Allows(scope) {
+					c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{Error: fmt.Sprintf("API key is missing the %s scope", scope)})
+					return
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// CurrentPrincipal returns the caller, if the request is authenticated.
+func CurrentPrincipal(c *gin.Context) (Principal, bool) {
+	value, ok := c.Get(principalKey)
+	if !ok {
+		return Principal{}, false
+	}
+	principal, ok := value.(Principal)
+	return principal, ok
+}
+
+// UserID returns the authenticated user, if any.
+func UserID(c *gin.Context) (int, bool) {
+	principal, ok := CurrentPrincipal(c)
+	return principal.UserID, ok
+}
+
+// authenticate stores the caller of the request, aborting it when the
+// credential is rejected. Other failures, such as the database being down,
+// are not the client's fault and are only detailed in the logs.
+func authenticate(c *gin.Context, authenticator Authenticator, credential string) (Principal, bool) {
+	principal, err := authenticator.Authenticate(c.Request.Context(), credential)
+	if err != nil {
+		switch {
+		case errors.As(err, new(*application.ErrUnauthorized)), errors.As(err, new(*application.ErrInvalidCredentials)):
+			abortUnauthorized(c, err.Error())
+		default:
+			slog.ErrorContext(c.Request.Context(), "Failed to authenticate request", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, ErrorResponse{Error: "internal server error"})
+		}
+		return Principal{}, false
+	}
+	c.Set(principalKey, principal)
+	return principal, true
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="uala-tweets"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, ErrorResponse{Error: message})
+}
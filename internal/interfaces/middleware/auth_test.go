@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/application"
+	"uala-tweets/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuthenticator map[string]Principal
+
+func (s stubAuthenticator) Authenticate(ctx context.Context, credential string) (Principal, error) {
+	if principal, ok := s[credential]; ok {
+		return principal, nil
+	}
+	if credential == "broken" {
+		return Principal{}, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	}
+	return Principal{}, application.NewErrUnauthorized("invalid credential")
+}
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authenticator := stubAuthenticator{
+		"alice":     {UserID: 1},
+		"bob":       {UserID: 2},
+		"alice_bot": {UserID: 1, APIKeyID: 10, Scopes: []domain.APIKeyScope{domain.APIKeyScopeTweetsWrite}},
+	}
+
+	whoami := func(c *gin.Context) {
+		userID, _ := UserID(c)
+		c.String(http.StatusOK, strconv.Itoa(userID))
+	}
+
+	r := gin.New()
+	r.GET("/public", OptionalAuth(authenticator), whoami)
+	r.GET("/private", RequireAuth(authenticator), whoami)
+	r.GET("/users/:id", RequireAuth(authenticator), RequireSelf("id"), whoami)
+	r.GET("/tweets", RequireAuth(authenticator), RequireScope(domain.APIKeyScopeTweetsWrite), whoami)
+	r.GET("/follows", RequireAuth(authenticator), RequireScope(domain.APIKeyScopeFollowsWrite), whoami)
+	r.GET("/settings", RequireAuth(authenticator), RequireScope(), whoami)
+	return r
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		expectStatus  int
+		expectBody    string
+	}{
+		{name: "anonymous public request", path: "/public", expectStatus: http.StatusOK, expectBody: "0"},
+		{name: "authenticated public request", path: "/public", authorization: "Bearer alice", expectStatus: http.StatusOK, expectBody: "1"},
+		{name: "invalid token on public request", path: "/public", authorization: "Bearer mallory", expectStatus: http.StatusUnauthorized},
+		{name: "missing token", path: "/private", expectStatus: http.StatusUnauthorized},
+		{name: "wrong scheme", path: "/private", authorization: "Basic alice", expectStatus: http.StatusUnauthorized},
+		{name: "valid token", path: "/private", authorization: "bearer bob", expectStatus: http.StatusOK, expectBody: "2"},
+		{name: "authentication failure is not detailed", path: "/private", authorization: "Bearer broken", expectStatus: http.StatusInternalServerError, expectBody: `{"error":"internal server error"}`},
+		{name: "acting as self", path: "/users/1", authorization: "Bearer alice", expectStatus: http.StatusOK, expectBody: "1"},
+		{name: "acting as someone else", path: "/users/2", authorization: "Bearer alice", expectStatus: http.StatusForbidden},
+		{name: "user on scoped route", path: "/follows", authorization: "Bearer alice", expectStatus: http.StatusOK, expectBody: "1"},
+		{name: "API key with scope", path: "/tweets", authorization: "Bearer alice_bot", expectStatus: http.StatusOK, expectBody: "1"},
+		{name: "API key without scope", path: "/follows", authorization: "Bearer alice_bot", expectStatus: http.StatusForbidden},
+		{name: "API key on route closed to keys", path: "/settings", authorization: "Bearer alice_bot", expectStatus: http.StatusForbidden},
+		{name: "user on route closed to keys", path: "/settings", authorization: "Bearer alice", expectStatus: http.StatusOK, expectBody: "1"},
+	}
+
+	router := newTestRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+			if tt.expectBody != "" {
+				assert.Equal(t, tt.expectBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequireAuth_DeactivatedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authenticator := stubAuthenticator{
+		"alice": {UserID: 1},
+		"carol": {UserID: 3, Deactivated: true},
+	}
+	requireAuth := RequireAuth(authenticator, "/users/:id/reactivate")
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	r := gin.New()
+	r.GET("/users/:id", requireAuth, ok)
+	r.PATCH("/users/:id", requireAuth, ok)
+	r.POST("/users/:id/reactivate", requireAuth, ok)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		token        string
+		expectStatus int
+	}{
+		{name: "deactivated user reads", method: http.MethodGet, path: "/users/3", token: "carol", expectStatus: http.StatusOK},
+		{name: "deactivated user writes", method: http.MethodPatch, path: "/users/3", token: "carol", expectStatus: http.StatusForbidden},
+		{name: "deactivated user reactivates", method: http.MethodPost, path: "/users/3/reactivate", token: "carol", expectStatus: http.StatusOK},
+		{name: "active user writes", method: http.MethodPatch, path: "/users/1", token: "alice", expectStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectStatus, w.Code)
+		})
+	}
+}
+
+type stubTokens map[string]*domain.User
+
+func (s stubTokens) Authenticate(ctx context.Context, accessToken string) (*domain.User, error) {
+	if user, ok := s[accessToken]; ok {
+		return user, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+type stubAPIKeys map[string]*domain.APIKey
+
+func (s stubAPIKeys) AuthenticateAPIKey(ctx context.Context, secret string) (*domain.APIKey, error) {
+	if key, ok := s[secret]; ok {
+		return key, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+func TestCredentialAuthenticator(t *testing.T) {
+	deactivatedAt := time.Now()
+	authenticator := NewCredentialAuthenticator(
+		stubTokens{"eyJhbGciOi": {ID: 1}, "eyJkZWFjdC": {ID: 3, DeactivatedAt: &deactivatedAt}},
+		stubAPIKeys{"uala_secret": {ID: 10, UserID: 2, Scopes: []domain.APIKeyScope{domain.APIKeyScopeTimelineRead}}},
+	)
+
+	principal, err := authenticator.Authenticate(context.Background(), "eyJhbGciOi")
+	require.NoError(t, err)
+	assert.Equal(t, Principal{UserID: 1}, principal)
+	assert.False(t, principal.IsAPIKey())
+
+	principal, err = authenticator.Authenticate(context.Background(), "eyJkZWFjdC")
+	require.NoError(t, err)
+	assert.Equal(t, Principal{UserID: 3, Deactivated: true}, principal)
+
+	principal, err = authenticator.Authenticate(context.Background(), "uala_secret")
+	require.NoError(t, err)
+	assert.Equal(t, 2, principal.UserID)
+	assert.True(t, principal.IsAPIKey())
+	assert.True(t, principal.Allows(domain.APIKeyScopeTimelineRead))
+	assert.False(t, principal.Allows(domain.APIKeyScopeTweetsWrite))
+
+	// API keys are never tried as access tokens
+	_, err = authenticator.Authenticate(context.Background(), "uala_unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"uala-tweets/internal/ports/idempotency"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotentRequestBytes = 1 << 20
+	// idempotencyLease is how long a key stays reserved while its request
+	// is handled. Should the process die mid-request, retries are turned
+	// away as in progress for this long rather than for the whole TTL.
+	idempotencyLease = time.Minute
+)
+
+// responseRecorder keeps a copy of what the handler writes
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes retries of a request carrying an Idempotency-Key header
+// safe: the first response is stored for ttl and replayed for later requests
+// with the same key instead of running the handler again. Reusing a key
+// with a different request is a conflict. Keys are scoped to the caller and
+// requests without the header are handled as usual. Server errors and
+// panics are not stored, so the request can be retried, and when the store
+// fails requests are handled as if they carried no key. It must run after
+// RequireAuth.
+func Idempotency(store idempotency.Store, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)})
+			return
+		}
+
+		userID, ok := UserID(c)
+		if !ok {
+			abortUnauthorized(c, "authentication required")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentRequestBytes+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, ErrorResponse{Error: "could not read request body"})
+			return
+		}
+		if len(body) > maxIdempotentRequestBytes {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, ErrorResponse{Error: "request body is too large"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storeKey := fmt.Sprintf("%d:%s", userID, key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		ctx := c.Request.Context()
+		existing, reserved, err := store.Reserve(ctx, storeKey, fingerprint, idempotencyLease)
+		if err != nil {
+			slog.WarnContext(ctx, "Idempotency store unavailable, handling request without key", "error", err)
+			c.Next()
+			return
+		}
+
+		if !reserved {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Error: fmt.Sprintf("%s was already used for a different request", IdempotencyKeyHeader)})
+			case !existing.Completed():
+				c.AbortWithStatusJSON(http.StatusConflict, ErrorResponse{Error: fmt.Sprintf("a request with this %s is still in progress", IdempotencyKeyHeader)})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+				c.Abort()
+			}
+			return
+		}
+
+		// The request has been handled, or has failed, from here on; a
+		// cancelled client must not keep the outcome from being recorded
+		ctx = context.WithoutCancel(ctx)
+		succeeded := false
+		defer func() {
+			// Deferred so that handlers that panic free the key too
+			if succeeded {
+				return
+			}
+			if err := store.Release(ctx, storeKey); err != nil {
+				slog.ErrorContext(ctx, "Failed to release idempotency key", "error", err)
+			}
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		succeeded = true
+
+		response := idempotency.Response{
+			Fingerprint: fingerprint,
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := store.Complete(ctx, storeKey, response, ttl); err != nil {
+			slog.ErrorContext(ctx, "Failed to store idempotent response", "error", err)
+		}
+	}
+}
+
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/ports/idempotency"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryIdempotencyStore struct {
+	responses map[string]idempotency.Response
+	// expiries is how long each key was last set to be kept
+	expiries map[string]time.Duration
+	err      error
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, key, fingerprint string, lease time.Duration) (idempotency.Response, bool, error) {
+	if s.err != nil {
+		return idempotency.Response{}, false, s.err
+	}
+	if existing, ok := s.responses[key]; ok {
+		return existing, false, nil
+	}
+	s.responses[key] = idempotency.Response{Fingerprint: fingerprint}
+	s.expire(key, lease)
+	return idempotency.Response{}, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key string, response idempotency.Response, ttl time.Duration) error {
+	s.responses[key] = response
+	s.expire(key, ttl)
+	return nil
+}
+
+func (s *memoryIdempotencyStore) expire(key string, ttl time.Duration) {
+	if s.expiries == nil {
+		s.expiries = make(map[string]time.Duration)
+	}
+	s.expiries[key] = ttl
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	delete(s.responses, key)
+	return nil
+}
+
+func newIdempotentRouter(store idempotency.Store, calls *int, status *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authenticator := stubAuthenticator{"alice": {UserID: 1}, "bob": {UserID: 2}}
+
+	r := gin.New()
+	r.POST("/tweets", RequireAuth(authenticator), Idempotency(store, time.Hour), func(c *gin.Context) {
+		*calls++
+		c.JSON(*status, gin.H{"call": *calls})
+	})
+	return r
+}
+
+func doIdempotentRequest(r *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/tweets", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+user)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotency(t *testing.T) {
+	calls, status := 0, http.StatusCreated
+	store := &memoryIdempotencyStore{responses: map[string]idempotency.Response{}}
+	r := newIdempotentRouter(store, &calls, &status)
+
+	first := doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	// Only the completed response is kept for the whole TTL
+	assert.Equal(t, time.Hour, store.expiries["1:key-1"])
+
+	// Retries get the first response back without running the handler
+	retry := doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// The same key with a different body is a conflict
+	w := doIdempotentRequest(r, "alice", "key-1", `{"content":"bye"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 1, calls)
+
+	// Keys are scoped to the caller
+	w = doIdempotentRequest(r, "bob", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, calls)
+
+	// Requests without a key always run
+	doIdempotentRequest(r, "alice", "", `{"content":"hello"}`)
+	doIdempotentRequest(r, "alice", "", `{"content":"hello"}`)
+	assert.Equal(t, 4, calls)
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	calls, status := 0, http.StatusCreated
+	store := &memoryIdempotencyStore{responses: map[string]idempotency.Response{}}
+	r := newIdempotentRouter(store, &calls, &status)
+
+	fingerprint := requestFingerprint(http.MethodPost, "/tweets", []byte(`{"content":"hello"}`))
+	store.responses["1:key-1"] = idempotency.Response{Fingerprint: fingerprint}
+
+	w := doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	calls, status := 0, http.StatusInternalServerError
+	store := &memoryIdempotencyStore{responses: map[string]idempotency.Response{}}
+	r := newIdempotentRouter(store, &calls, &status)
+
+	w := doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	status = http.StatusCreated
+	w = doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotency_PanicsReleaseTheKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryIdempotencyStore{responses: map[string]idempotency.Response{}}
+	authenticator := stubAuthenticator{"alice": {UserID: 1}}
+
+	var leased time.Duration
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.POST("/tweets", RequireAuth(authenticator), Idempotency(store, time.Hour), func(c *gin.Context) {
+		leased = store.expiries["1:key-1"]
+		panic("handler bug")
+	})
+
+	w := doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, idempotencyLease, leased)
+	assert.Empty(t, store.responses, "the request can be retried")
+}
+
+func TestIdempotency_StoreDownFailsOpen(t *testing.T) {
+	calls, status := 0, http.StatusCreated
+	store := &memoryIdempotencyStore{err: errors.New("connection refused")}
+	r := newIdempotentRouter(store, &calls, &status)
+
+	doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	w := doIdempotentRequest(r, "alice", "key-1", `{"content":"hello"}`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"uala-tweets/internal/ports/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests no route matched, so that arbitrary paths
+// do not each become a series of their own
+const unmatchedRoute = "unmatched"
+
+// Metrics records the latency and status of every request by the route it
+// matched. It should run before any middleware that can abort a request.
+func Metrics(recorder metrics.HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		recorder.RequestServed(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
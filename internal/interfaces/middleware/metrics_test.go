@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type servedRequest struct {
+	method, route string
+	status        int
+}
+
+// recordingHTTPMetrics records the requests served
+type recordingHTTPMetrics struct {
+	served []servedRequest
+}
+
+func (m *recordingHTTPMetrics) RequestServed(method, route string, status int, duration time.Duration) {
+	m.served = append(m.served, servedRequest{method, route, status})
+}
+
+func TestMetrics_RecordsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := &recordingHTTPMetrics{}
+	r := gin.New()
+	r.Use(Metrics(recorder))
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/tweets", func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{Error: "rate limit exceeded"})
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/users/1", nil),
+		httptest.NewRequest(http.MethodGet, "/users/2", nil),
+		httptest.NewRequest(http.MethodPost, "/tweets", nil),
+		httptest.NewRequest(http.MethodGet, "/wp-admin", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, []servedRequest{
+		{http.MethodGet, "/users/:id", http.StatusOK},
+		{http.MethodGet, "/users/:id", http.StatusOK},
+		{http.MethodPost, "/tweets", http.StatusTooManyRequests},
+		{http.MethodGet, "unmatched", http.StatusNotFound},
+	}, recorder.served)
+}
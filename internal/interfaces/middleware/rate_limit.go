@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"uala-tweets/internal/ports/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit limits how often a caller can hit the routes of a class, such
+// as tweet creation or reads. Authenticated callers are counted per user,
+// across their sessions and API keys; anonymous ones per client IP. Each
+// class is counted separately. When the limiter fails, requests are let
+// through: an outage of the limiter should not take the API down with it.
+// It must run after the authentication middleware.
+func RateLimit(limiter ratelimit.RateLimiter, class string, limit ratelimit.Limit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), rateLimitKey(c, class), limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Rate limiter unavailable, letting request through", "class", class, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(result.ResetAt.Unix(), 10))
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, ErrorResponse{Error: "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func rateLimitKey(c *gin.Context, class string) string {
+	if userID, ok := UserID(c); ok {
+		return fmt.Sprintf("%s:user:%d", class, userID)
+	}
+	return fmt.Sprintf("%s:ip:%s", class, c.ClientIP())
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"uala-tweets/internal/ports/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingLimiter allows limit.Requests requests per key, forever
+type countingLimiter struct {
+	counts map[string]int
+	err    error
+}
+
+func (l *countingLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if l.err != nil {
+		return ratelimit.Result{}, l.err
+	}
+	resetAt := time.Now().Add(limit.Window)
+	if l.counts[key] >= limit.Requests {
+		return ratelimit.Result{ResetAt: resetAt, RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	l.counts[key]++
+	return ratelimit.Result{Allowed: true, Remaining: limit.Requests - l.counts[key], ResetAt: resetAt}, nil
+}
+
+func newRateLimitedRouter(limiter ratelimit.RateLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	authenticator := stubAuthenticator{"alice": {UserID: 1}, "bob": {UserID: 2}}
+	limit := ratelimit.Limit{Requests: 2, Window: time.Minute}
+
+	r := gin.New()
+	r.GET("/reads", OptionalAuth(authenticator), RateLimit(limiter, "reads", limit), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/writes", OptionalAuth(authenticator), RateLimit(limiter, "writes", limit), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func doRateLimitedRequest(r *gin.Engine, path, authorization, remoteAddr string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	r := newRateLimitedRouter(&countingLimiter{counts: map[string]int{}})
+
+	w := doRateLimitedRequest(r, "/reads", "Bearer alice", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+
+	// The same user is counted across client IPs
+	w = doRateLimitedRequest(r, "/reads", "Bearer alice", "10.0.0.2:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doRateLimitedRequest(r, "/reads", "Bearer alice", "10.0.0.3:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Other users and other route classes have their own budget
+	w = doRateLimitedRequest(r, "/reads", "Bearer bob", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = doRateLimitedRequest(r, "/writes", "Bearer alice", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Anonymous callers are counted per IP
+	doRateLimitedRequest(r, "/reads", "", "10.0.0.9:1234")
+	doRateLimitedRequest(r, "/reads", "", "10.0.0.9:1234")
+	w = doRateLimitedRequest(r, "/reads", "", "10.0.0.9:1234")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	w = doRateLimitedRequest(r, "/reads", "", "10.0.0.8:1234")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	r := newRateLimitedRouter(&countingLimiter{err: errors.New("connection refused")})
+
+	for i := 0; i < 5; i++ {
+		w := doRateLimitedRequest(r, "/reads", "Bearer alice", "10.0.0.1:1234")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"regexp"
+
+	"uala-tweets/internal/domain"
+	"uala-tweets/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID of a request, from the caller if it sent
+// one, and back in the response
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID accepts the IDs callers may choose, which end up in logs
+// and Kafka headers
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID gives every request an ID, the caller's own if valid, which
+// the records logged while handling it and the events it publishes carry
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = domain.NewEventID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"uala-tweets/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var handled string
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/tweets/:id", func(c *gin.Context) {
+		handled = logging.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name   string
+		sent   string
+		keepIt bool
+	}{
+		{name: "missing", sent: ""},
+		{name: "sent by the caller", sent: "checkout-7f3a:42", keepIt: true},
+		{name: "with spaces", sent: "drop table tweets"},
+		{name: "too long", sent: strings.Repeat("a", 129)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handled = ""
+			req := httptest.NewRequest(http.MethodGet, "/tweets/1", nil)
+			if tc.sent != "" {
+				req.Header.Set(RequestIDHeader, tc.sent)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, handled)
+			if tc.keepIt {
+				assert.Equal(t, tc.sent, id)
+			} else {
+				assert.NotEqual(t, tc.sent, id)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Tracing traces each request in a span named after its route, continuing
+// the trace of the caller if it sent one. Requests to the untraced routes,
+// like the probes, are left out.
+func Tracing(service string, untraced ...string) gin.HandlerFunc {
+	return otelgin.Middleware(service, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		return !slices.Contains(untraced, c.FullPath())
+	}))
+}
@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func decodeRecords(t *testing.T, out *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	decoder := json.NewDecoder(out)
+	for decoder.More() {
+		var record map[string]any
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestNew_LogsTheAttributesOfTheContext(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, "info", FormatJSON)
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = With(ctx, slog.String("event_id", "evt-1"))
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(ctx, "process")
+	defer span.End()
+
+	logger.InfoContext(ctx, "Tweet persisted", "tweet_id", 42)
+	logger.DebugContext(ctx, "Fanout event published")
+	logger.Info("Consumer started")
+
+	records := decodeRecords(t, &out)
+	require.Len(t, records, 2, "debug records are below the level")
+	assert.Equal(t, "Tweet persisted", records[0]["msg"])
+	assert.Equal(t, "INFO", records[0]["level"])
+	assert.Equal(t, float64(42), records[0]["tweet_id"])
+	assert.Equal(t, "req-1", records[0]["request_id"])
+	assert.Equal(t, "evt-1", records[0]["event_id"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), records[0]["trace_id"])
+	assert.NotContains(t, records[1], "request_id")
+	assert.Equal(t, "req-1", RequestID(ctx))
+}
+
+func TestWith_DoesNotShareAttributesBetweenContexts(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, "debug", FormatJSON)
+	require.NoError(t, err)
+
+	parent := With(context.Background(), slog.String("consumer", "tweet-consumer"))
+	first := With(parent, slog.String("event_id", "evt-1"))
+	second := With(parent, slog.String("event_id", "evt-2"))
+	logger.InfoContext(first, "first")
+	logger.InfoContext(second, "second")
+
+	records := decodeRecords(t, &out)
+	require.Len(t, records, 2)
+	assert.Equal(t, "evt-1", records[0]["event_id"])
+	assert.Equal(t, "evt-2", records[1]["event_id"])
+}
+
+func TestNew_RejectsUnknownSettings(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "loud", FormatJSON)
+	assert.ErrorContains(t, err, `invalid log level "loud"`)
+
+	_, err = New(&bytes.Buffer{}, "info", "xml")
+	assert.ErrorContains(t, err, `unknown log format "xml"`)
+}
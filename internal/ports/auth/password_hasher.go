@@ -0,0 +1,7 @@
+package auth
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Compare returns an error unless password matches hash
+	Compare(hash, password string) error
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "time"
+
+// TokenIssuer signs and verifies the short-lived access tokens sent in the
+// Authorization header.
+type TokenIssuer interface {
+	Issue(userID int) (token string, expiresAt time.Time, err error)
+	// Parse returns the user a valid, unexpired token was issued to
+	Parse(token string) (int, error)
+}
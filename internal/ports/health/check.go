@@ -0,0 +1,12 @@
+package health
+
+import "context"
+
+// Check tells whether one dependency of the service works.
+type Check interface {
+	// Name identifies the dependency in health reports
+	Name() string
+	// Check returns why the dependency does not work, or nil. It should
+	// give up once ctx is done.
+	Check(ctx context.Context) error
+}
@@ -0,0 +1,33 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Response is the response recorded for an idempotency key. A zero
+// StatusCode means the first request with the key is still being handled.
+type Response struct {
+	// Fingerprint identifies the request the key was first used with
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+func (r Response) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Store remembers the responses of requests made with an idempotency key.
+type Store interface {
+	// Reserve claims key for a request with fingerprint until lease runs
+	// out, unless the request completes first. When the key is already
+	// taken it returns what is recorded for it and false.
+	Reserve(ctx context.Context, key, fingerprint string, lease time.Duration) (Response, bool, error)
+	// Complete records the response of the request that reserved key,
+	// keeping it for ttl
+	Complete(ctx context.Context, key string, response Response, ttl time.Duration) error
+	// Release frees key so that the request can be retried
+	Release(ctx context.Context, key string) error
+}
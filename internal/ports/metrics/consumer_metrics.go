@@ -0,0 +1,20 @@
+package metrics
+
+import "time"
+
+// ConsumerMetrics records how consumers keep up with their topics.
+type ConsumerMetrics interface {
+	// MessageProcessed records one attempt at processing a message of
+	// topic, which failed unless ok
+	MessageProcessed(consumer, topic string, ok bool, duration time.Duration)
+	// ConsumerLag records how many messages of a partition are left to
+	// fetch after the one just fetched
+	ConsumerLag(consumer, topic string, partition int, lag int64)
+}
+
+// FanoutMetrics records how far tweets are fanned out.
+type FanoutMetrics interface {
+	// TweetFannedOut records the timelines, of users and of lists, a
+	// tweet was fanned out to
+	TweetFannedOut(timelines int)
+}
@@ -0,0 +1,10 @@
+package metrics
+
+import "time"
+
+// HTTPMetrics records the requests the API serves.
+type HTTPMetrics interface {
+	// RequestServed records a request to route, the path pattern it
+	// matched, answered with status after duration
+	RequestServed(method, route string, status int, duration time.Duration)
+}
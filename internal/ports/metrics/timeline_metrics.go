@@ -0,0 +1,10 @@
+package metrics
+
+import "time"
+
+// TimelineMetrics records how home timeline reads go.
+type TimelineMetrics interface {
+	// TimelineRead records a read that took duration. hit tells whether
+	// the cache held the timeline; a miss returns an empty timeline.
+	TimelineRead(duration time.Duration, hit bool)
+}
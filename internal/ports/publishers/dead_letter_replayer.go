@@ -0,0 +1,11 @@
+package publishers
+
+import (
+	"context"
+	"uala-tweets/internal/domain"
+)
+
+// DeadLetterReplayer publishes a dead letter back onto its original topic
+type DeadLetterReplayer interface {
+	Replay(ctx context.Context, deadLetter *domain.DeadLetter) error
+}
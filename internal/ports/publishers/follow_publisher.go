@@ -1,7 +1,11 @@
 package publishers
 
-import "uala-tweets/internal/domain"
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
 
 type FollowPublisher interface {
-	PublishFollowEvent(event domain.FollowEvent) error
+	PublishFollowEvent(ctx context.Context, event domain.FollowEvent) error
 }
@@ -0,0 +1,10 @@
+package publishers
+
+import (
+	"context"
+	"uala-tweets/internal/domain"
+)
+
+type UserEventPublisher interface {
+	PublishUserEvent(ctx context.Context, event domain.UserEvent) error
+}
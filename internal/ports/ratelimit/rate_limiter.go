@@ -0,0 +1,28 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit is how many requests are allowed within any window of time.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Result is the outcome of counting one request against a limit.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// ResetAt is when the oldest counted request leaves the window
+	ResetAt time.Time
+	// RetryAfter is how long a rejected caller should wait
+	RetryAfter time.Duration
+}
+
+// RateLimiter counts requests per key.
+type RateLimiter interface {
+	// Allow counts a request for key, unless the limit is already reached
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
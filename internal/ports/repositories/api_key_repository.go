@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *domain.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	// GetByUserID returns the keys of a user that have not been revoked
+	GetByUserID(ctx context.Context, userID int) ([]*domain.APIKey, error)
+	Revoke(ctx context.Context, userID int, id int64) error
+	SetLastUsedAt(ctx context.Context, id int64, at time.Time) error
+}
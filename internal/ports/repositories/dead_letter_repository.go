@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
+type DeadLetterRepository interface {
+	// Create archives a dead letter; archiving the same dead-letter topic
+	// position again does nothing
+	Create(ctx context.Context, deadLetter *domain.DeadLetter) error
+	GetByID(ctx context.Context, id int64) (*domain.DeadLetter, error)
+	// List returns the most recently failed dead letters first, all of
+	// them when the filter has no limit
+	List(ctx context.Context, filter domain.DeadLetterFilter) ([]*domain.DeadLetter, error)
+	RecordReplay(ctx context.Context, replay *domain.DeadLetterReplay) error
+	// GetReplays returns the replays of a dead letter, oldest first
+	GetReplays(ctx context.Context, deadLetterID int64) ([]*domain.DeadLetterReplay, error)
+}
@@ -1,8 +1,16 @@
 package repositories
 
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
 type FollowRepository interface {
-	Follow(followerID, followedID int) error
-	Unfollow(followerID, followedID int) error
-	IsFollowing(followerID, followedID int) (bool, error)
-	GetFollowers(userID int) ([]int, error)
+	Follow(ctx context.Context, followerID, followedID int) error
+	Unfollow(ctx context.Context, followerID, followedID int) error
+	IsFollowing(ctx context.Context, followerID, followedID int) (bool, error)
+	GetFollowers(ctx context.Context, userID int) ([]int, error)
+	GetFollowing(ctx context.Context, userID int) ([]int, error)
+	GetFriendsOfFriends(ctx context.Context, userID int, limit int) ([]*domain.Suggestion, error)
 }
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
+type FollowRequestRepository interface {
+	Create(ctx context.Context, requesterID, targetID int) error
+	Delete(ctx context.Context, requesterID, targetID int) error
+	// Approve turns the request into a follow in one transaction. Approving
+	// a request whose follow already exists still removes the request.
+	Approve(ctx context.Context, requesterID, targetID int) error
+	Exists(ctx context.Context, requesterID, targetID int) (bool, error)
+	GetByTargetID(ctx context.Context, targetID int) ([]*domain.FollowRequest, error)
+}
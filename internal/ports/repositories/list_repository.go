@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
+type ListRepository interface {
+	Create(ctx context.Context, list *domain.List) error
+	GetByID(ctx context.Context, id int) (*domain.List, error)
+	GetByOwnerID(ctx context.Context, ownerID int) ([]*domain.List, error)
+	Update(ctx context.Context, list *domain.List) error
+	Delete(ctx context.Context, id int) error
+	AddMember(ctx context.Context, listID, userID int) error
+	RemoveMember(ctx context.Context, listID, userID int) error
+	GetMembers(ctx context.Context, listID int) ([]int, error)
+	// GetListIDsByMember returns the IDs of the lists userID is a member of
+	GetListIDsByMember(ctx context.Context, userID int) ([]int, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
+
+type MuteRepository interface {
+	Create(ctx context.Context, mute *domain.Mute) error
+	UpdateExpiry(ctx context.Context, userID int, muteID int64, expiresAt *time.Time) error
+	Delete(ctx context.Context, userID int, muteID int64) error
+	GetByUserID(ctx context.Context, userID int) ([]*domain.Mute, error)
+}
@@ -0,0 +1,10 @@
+package repositories
+
+import "context"
+
+// ProcessedEventStore remembers the events each consumer has processed, so
+// that redelivered events can be skipped.
+type ProcessedEventStore interface {
+	IsProcessed(ctx context.Context, consumer, eventID string) (bool, error)
+	MarkProcessed(ctx context.Context, consumer, eventID string) error
+}
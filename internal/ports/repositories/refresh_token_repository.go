@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *domain.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	// Revoke marks a token as used; it returns false when the token was
+	// already revoked
+	Revoke(ctx context.Context, id int64) (bool, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+}
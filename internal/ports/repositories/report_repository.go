@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
+type ReportRepository interface {
+	Create(ctx context.Context, report *domain.Report) error
+	GetByID(ctx context.Context, id int64) (*domain.Report, error)
+	// GetOpen returns the oldest open reports first
+	GetOpen(ctx context.Context, limit int) ([]*domain.Report, error)
+	// Resolve closes an open report
+	Resolve(ctx context.Context, id int64, status domain.ReportStatus, resolvedBy int) error
+	// ResolveByTweet closes every open report of a tweet
+	ResolveByTweet(ctx context.Context, tweetID int64, status domain.ReportStatus, resolvedBy int) error
+}
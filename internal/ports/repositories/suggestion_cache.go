@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
+
+type SuggestionCache interface {
+	SetSuggestions(ctx context.Context, userID int, suggestions []*domain.Suggestion) error
+	// GetSuggestions returns found=false when nothing has been computed for the user yet
+	GetSuggestions(ctx context.Context, userID int) (suggestions []*domain.Suggestion, found bool, err error)
+}
@@ -1,8 +1,22 @@
 package repositories
 
+import "context"
+
+// TimelineLength is how many of their newest tweets timelines keep. Reads
+// never page further back than this.
+const TimelineLength = 1000
+
 type TimelineCache interface {
-	AddToTimeline(userID int, tweetID int64) error
-	GetTimeline(userID int, limit int) ([]int64, error)
-	ClearTimeline(userID int) error
-	RemoveFromTimeline(userID int, tweetID int64) error
+	// AddToTimeline does nothing when the tweet is already in the timeline.
+	// Timelines are bounded: adding to a full one drops its oldest entry.
+	AddToTimeline(ctx context.Context, userID int, tweetID int64) error
+	// MergeIntoTimeline adds the tweets in one atomic step, keeping the
+	// timeline newest first and only its newest size entries
+	MergeIntoTimeline(ctx context.Context, userID int, tweetIDs []int64, size int) error
+	GetTimeline(ctx context.Context, userID int, limit int) ([]int64, error)
+	ClearTimeline(ctx context.Context, userID int) error
+	RemoveFromTimeline(ctx context.Context, userID int, tweetID int64) error
+	// RemoveFromTimelines removes the tweets from every one of the
+	// timelines, in batches rather than a call per tweet and timeline
+	RemoveFromTimelines(ctx context.Context, userIDs []int, tweetIDs []int64) error
 }
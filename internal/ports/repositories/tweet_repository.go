@@ -1,10 +1,20 @@
 package repositories
 
-import "uala-tweets/internal/domain"
+import (
+	"context"
+
+	"uala-tweets/internal/domain"
+)
 
 type TweetRepository interface {
-	Create(tweet *domain.Tweet) error
-	GetByID(id int64) (*domain.Tweet, error)
-	GetByUserID(userID int64) ([]*domain.Tweet, error)
-	GetTweetIDsByUser(userID int) ([]int64, error)
+	Create(ctx context.Context, tweet *domain.Tweet) error
+	GetByID(ctx context.Context, id int64) (*domain.Tweet, error)
+	// GetByIDs leaves out tweets that do not exist and those of deactivated
+	// accounts
+	GetByIDs(ctx context.Context, ids []int64) ([]*domain.Tweet, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*domain.Tweet, error)
+	GetTweetIDsByUser(ctx context.Context, userID int) ([]int64, error)
+	// Update saves the content of an existing tweet
+	Update(ctx context.Context, tweet *domain.Tweet) error
+	Delete(ctx context.Context, id int64) error
 }
@@ -1,9 +1,29 @@
 package repositories
 
-import "uala-tweets/internal/domain"
+import (
+	"context"
+	"time"
+
+	"uala-tweets/internal/domain"
+)
 
 type UserRepository interface {
-	Create(user *domain.User) error
-	GetByID(id int) (*domain.User, error)
-	Exists(id int) (bool, error)
+	Create(ctx context.Context, user *domain.User) error
+	GetByID(ctx context.Context, id int) (*domain.User, error)
+	// GetByIDs leaves out the IDs of users that do not exist
+	GetByIDs(ctx context.Context, ids []int) ([]*domain.User, error)
+	// GetByUsername also resolves usernames the user has renamed away from
+	GetByUsername(ctx context.Context, username string) (*domain.User, error)
+	// Update saves the profile fields and username of an existing user
+	Update(ctx context.Context, user *domain.User) error
+	Exists(ctx context.Context, id int) (bool, error)
+	SetProtected(ctx context.Context, id int, protected bool) error
+	SetRole(ctx context.Context, id int, role domain.Role) error
+	ListIDs(ctx context.Context, afterID int, limit int) ([]int, error)
+	SetDeactivatedAt(ctx context.Context, id int, at *time.Time) error
+	GetDeactivatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]int, error)
+	Delete(ctx context.Context, id int) error
+	// DeleteDeactivated deletes the user only if they were deactivated
+	// before cutoff, reporting whether they were deleted
+	DeleteDeactivated(ctx context.Context, id int, cutoff time.Time) (bool, error)
 }
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"uala-tweets/internal/config"
+	"uala-tweets/internal/logging"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+const usage = `Usage: uala-tweets [role] [flags]
+
+Roles:
+  all      [-consumers names] [-jobs=false]
+           Serve the API and run the consumers and jobs (the default)
+  api      Serve the API
+  worker   [-consumers names] [-jobs]
+           Run the consumers, and the jobs with -jobs
+  migrate  [up|down]
+           Apply the pending database migrations, or roll back the last one
+  dlq      <command> [flags]
+           Inspect and replay dead letters
+  config   Print the configuration, with secrets redacted, and check it
+
+Consumers are tweet, fanout, follow and dead-letter, comma separated, and
+all of them run by default.
+
+Settings are read from environment variables, and from the YAML file named
+in CONFIG_FILE for those not set.
+`
+
+// roleOptions is what a role runs
+type roleOptions struct {
+	api       bool
+	consumers []workerConsumer
+	jobs      bool
+}
+
+// runRole runs role with args and returns the exit code. The API and the
+// workers run as separate processes so they can be scaled apart, or
+// together in one process with the all role.
+func runRole(role string, args []string) int {
+	if !slices.Contains([]string{"all", "api", "worker", "migrate", "dlq", "config"}, role) {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	cfg, err := config.Load(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return 1
+	}
+	validate := cfg.Validate
+	if role == "all" || role == "api" || role == "config" {
+		validate = cfg.ValidateAPI
+	}
+	if role == "config" {
+		return printConfig(cfg, validate())
+	}
+	if err := validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return 1
+	}
+	logger, err := logging.New(os.Stderr, cfg.Logging.Level, cfg.Logging.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		return 1
+	}
+	slog.SetDefault(logger)
+
+	switch role {
+	case "migrate":
+		return runMigrate(cfg, args)
+	case "dlq":
+		return runDeadLetterCommand(cfg, args)
+	}
+	opts, err := parseRoleFlags(role, args)
+	if err != nil {
+		return 2
+	}
+	serve(cfg, opts)
+	return 0
+}
+
+// fatal logs msg with args as an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// printConfig prints cfg with its secrets redacted, then what is invalid in
+// it, and returns the exit code
+func printConfig(cfg *config.Config, invalid error) int {
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to print configuration: %v\n", err)
+		return 1
+	}
+	os.Stdout.Write(out)
+	if invalid != nil {
+		fmt.Fprintf(os.Stderr, "\nInvalid configuration:\n%v\n", invalid)
+		return 1
+	}
+	return 0
+}
+
+// parseRoleFlags parses the flags of role, reporting errors on stderr.
+// Roles running workers take the consumers and jobs to run; the API takes
+// none. Workers run the jobs only when asked, since they only need to run
+// in one process however many workers there are.
+func parseRoleFlags(role string, args []string) (roleOptions, error) {
+	flags := flag.NewFlagSet(role, flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	opts := roleOptions{api: role != "worker"}
+	var consumers *string
+	if role != "api" {
+		consumers = flags.String("consumers", "", "Comma-separated consumers to run, all of them when empty")
+		flags.BoolVar(&opts.jobs, "jobs", role == "all", "Run the background jobs")
+	}
+	if err := flags.Parse(args); err != nil {
+		return roleOptions{}, err
+	}
+	if flags.NArg() > 0 {
+		err := fmt.Errorf("unexpected argument %q", flags.Arg(0))
+		fmt.Fprintf(flags.Output(), "%s: %v\n", role, err)
+		return roleOptions{}, err
+	}
+	if consumers == nil {
+		return opts, nil
+	}
+
+	var err error
+	if opts.consumers, err = parseConsumers(*consumers); err != nil {
+		fmt.Fprintf(flags.Output(), "%s: %v\n", role, err)
+		return roleOptions{}, err
+	}
+	return opts, nil
+}
+
+// parseConsumers returns the consumers named in the comma-separated names,
+// or all of them when names is empty
+func parseConsumers(names string) ([]workerConsumer, error) {
+	if names == "" {
+		return workerConsumers, nil
+	}
+
+	var consumers []workerConsumer
+	for _, name := range strings.Split(names, ",") {
+		i := slices.IndexFunc(workerConsumers, func(c workerConsumer) bool { return c.name == strings.TrimSpace(name) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown consumer %q", name)
+		}
+		if !slices.ContainsFunc(consumers, func(c workerConsumer) bool { return c.name == workerConsumers[i].name }) {
+			consumers = append(consumers, workerConsumers[i])
+		}
+	}
+	return consumers, nil
+}
+
+// serve runs what opts selects until SIGINT or SIGTERM, then shuts it down
+// gracefully
+func serve(cfg *config.Config, opts roleOptions) {
+	signals, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	a := newApp(cfg)
+	defer a.Close()
+
+	// --- Consumers and Background Jobs ---
+	tasks := newBackgroundTasks()
+	if len(opts.consumers) > 0 {
+		policy := initRetryPolicy(cfg.Consumers)
+		for _, consumer := range opts.consumers {
+			slog.Info("Starting consumer", "consumer", consumer.name)
+			consumer.start(a, tasks, policy)
+		}
+	}
+	if opts.jobs {
+		startJobs(a, tasks)
+	}
+
+	// --- HTTP Server ---
+	// Workers serve the probes and metrics alone
+	healthHandler := newHealthHandler(a, tasks)
+	server := newProbeServer(a, healthHandler)
+	if opts.api {
+		server = newAPIServer(a, healthHandler)
+	}
+	go func() {
+		slog.Info("Server starting", "addr", server.Addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to start server", "error", err)
+		}
+	}()
+
+	// --- Graceful Shutdown ---
+	<-signals.Done()
+	// A second signal stops the service straight away
+	stopSignals()
+	shutdownGracefully(server, tasks, cfg.ShutdownTimeout)
+}
+
+// runMigrate applies the migrations that have not been applied yet, or
+// rolls back the last one, and returns the exit code
+func runMigrate(cfg *config.Config, args []string) int {
+	direction := "up"
+	if len(args) > 0 {
+		direction = args[0]
+	}
+	if len(args) > 1 || (direction != "up" && direction != "down") {
+		fmt.Fprint(os.Stderr, usage)
+		return 2
+	}
+
+	m, err := migrate.New("file://"+cfg.Database.MigrationsPath, cfg.Database.URL)
+	if err != nil {
+		slog.Error("Failed to load migrations", "error", err)
+		return 1
+	}
+	defer m.Close()
+
+	if direction == "up" {
+		err = m.Up()
+	} else {
+		err = m.Steps(-1)
+	}
+	if errors.Is(err, migrate.ErrNoChange) {
+		slog.Info("No migrations to apply")
+		return 0
+	}
+	if err != nil {
+		slog.Error("Failed to migrate", "direction", direction, "error", err)
+		return 1
+	}
+
+	version, _, _ := m.Version()
+	slog.Info("Migrated", "direction", direction, "version", version)
+	return 0
+}
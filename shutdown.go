@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backgroundTasks runs the consumers and jobs on a context of their own, so
+// they keep going while HTTP requests drain on shutdown
+type backgroundTasks struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu sync.Mutex
+	// returned are the tasks that returned before being stopped
+	returned []string
+}
+
+func newBackgroundTasks() *backgroundTasks {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundTasks{ctx: ctx, cancel: cancel}
+}
+
+// Go runs task, named name in health reports, in a goroutine until the
+// tasks are stopped
+func (b *backgroundTasks) Go(name string, task func(ctx context.Context)) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		task(b.ctx)
+		if b.ctx.Err() == nil {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			b.returned = append(b.returned, name)
+		}
+	}()
+}
+
+func (b *backgroundTasks) Name() string { return "background_tasks" }
+
+// Check fails once a task returned on its own, which only a restart fixes
+func (b *backgroundTasks) Check(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.returned) > 0 {
+		return fmt.Errorf("stopped unexpectedly: %s", strings.Join(b.returned, ", "))
+	}
+	return nil
+}
+
+// Stop cancels the tasks and waits for them to return, or for ctx to be
+// done. Consumers return once the messages they are processing are
+// finished and committed.
+func (b *backgroundTasks) Stop(ctx context.Context) error {
+	b.cancel()
+
+	stopped := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdownGracefully stops the service without losing work: the HTTP
+// server, if the role runs one, stops accepting connections and drains the
+// requests in flight, which may still publish events, then the consumers
+// and jobs stop. Kafka writers, Redis and PostgreSQL are closed by the app
+// once this returns. Whatever has not finished by timeout is abandoned.
+func shutdownGracefully(server *http.Server, tasks *backgroundTasks, timeout time.Duration) {
+	slog.Info("Shutting down, waiting for requests and messages in progress", "timeout", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Error("Failed to drain HTTP requests", "error", err)
+		}
+	}
+	if err := tasks.Stop(ctx); err != nil {
+		slog.Error("Consumers and jobs did not stop in time", "error", err)
+	}
+	slog.Info("Shutdown complete")
+}